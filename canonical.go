@@ -0,0 +1,214 @@
+package jsonvalue
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ================ CANONICAL JSON ================
+
+// Reference: [RFC 8785 - JSON Canonicalization Scheme (JCS)](https://www.rfc-editor.org/rfc/rfc8785)
+
+// MarshalCanonical returns canonical JSON bytes of current value as defined by
+// RFC 8785 (JCS). Object keys are sorted by UTF-16 code units, numbers are
+// formatted as ECMAScript does, and no blank character is generated. Marshal
+// options are not applicable for canonical output.
+//
+// As JCS represents all numbers as IEEE 754 double values, integers beyond
+// ±2^53 may lose precision. NaN, +Inf, -Inf and strings with invalid UTF-8
+// sequences are not allowed.
+//
+// MarshalCanonical 返回当前值按照 RFC 8785 (JCS) 定义的规范化 JSON 字节。object 的键按照
+// UTF-16 编码单元排序, 数字按照 ECMAScript 的方式格式化, 并且不会生成任何空白字符。规范化输出
+// 不支持序列化选项。
+//
+// 由于 JCS 将所有数字表示为 IEEE 754 双精度浮点数, 因此超出 ±2^53 的整数可能会丢失精度。不允许
+// NaN, +Inf, -Inf 以及包含非法 UTF-8 序列的字符串。
+func (v *V) MarshalCanonical() ([]byte, error) {
+	if v == nil || v.valueType == NotExist {
+		return nil, ErrValueUninitialized
+	}
+	buf := bytes.Buffer{}
+	if err := marshalCanonicalToBuffer(v, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MustMarshalCanonical is the same as MarshalCanonical, but panics if error
+// occurs.
+//
+// MustMarshalCanonical 与 MarshalCanonical 相同, 但是当错误发生时, 会 panic。
+func (v *V) MustMarshalCanonical() []byte {
+	b, err := v.MarshalCanonical()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func marshalCanonicalToBuffer(v *V, buf *bytes.Buffer) error {
+	switch v.valueType {
+	default:
+		return nil
+	case String:
+		return marshalCanonicalString(v.valueStr, buf)
+	case Number:
+		return marshalCanonicalNumber(v.num.f64, buf)
+	case Boolean:
+		marshalBoolean(v, buf)
+		return nil
+	case Null:
+		marshalNull(buf)
+		return nil
+	case Object:
+		return marshalCanonicalObject(v, buf)
+	case Array:
+		return marshalCanonicalArray(v, buf)
+	}
+}
+
+func marshalCanonicalObject(v *V, buf *bytes.Buffer) error {
+	c := v.loadedChildren()
+	keys := make([]string, 0, len(c.object))
+	for k := range c.object {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lessInUTF16(keys[i], keys[j])
+	})
+
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := marshalCanonicalString(k, buf); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := marshalCanonicalToBuffer(c.object[k].v, buf); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func marshalCanonicalArray(v *V, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+	for i, c := range v.loadedChildren().arr {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := marshalCanonicalToBuffer(c, buf); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// lessInUTF16 compares two strings by their UTF-16 code units.
+func lessInUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// marshalCanonicalString escapes only double quote, back slash and control
+// characters, as described in RFC 8785 section 3.2.2.2.
+func marshalCanonicalString(s string, buf *bytes.Buffer) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("%w, invalid UTF-8 sequence in string %q", ErrIllegalString, s)
+	}
+
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		chr := s[i]
+		switch {
+		case chr == '"' || chr == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(chr)
+		case chr == '\b':
+			buf.WriteString(`\b`)
+		case chr == '\t':
+			buf.WriteString(`\t`)
+		case chr == '\n':
+			buf.WriteString(`\n`)
+		case chr == '\f':
+			buf.WriteString(`\f`)
+		case chr == '\r':
+			buf.WriteString(`\r`)
+		case chr < 0x20:
+			fmt.Fprintf(buf, `\u%04x`, chr)
+		default:
+			buf.WriteByte(chr)
+		}
+	}
+	buf.WriteByte('"')
+	return nil
+}
+
+// marshalCanonicalNumber formats a number as ECMAScript Number.prototype.toString
+// does, which is described in RFC 8785 section 3.2.2.3.
+func marshalCanonicalNumber(f float64, buf io.Writer) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("%w: %v", ErrUnsupportedFloat, f)
+	}
+	_, _ = buf.Write([]byte(formatECMAScriptNumber(f)))
+	return nil
+}
+
+func formatECMAScriptNumber(f float64) string {
+	if f == 0 {
+		return "0" // including -0
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// shortest representation, such as "1.2345e+06"
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp := e, 0
+	if idx := strings.IndexByte(e, 'e'); idx >= 0 {
+		mantissa = e[:idx]
+		exp, _ = strconv.Atoi(e[idx+1:])
+	}
+	digits := strings.Replace(mantissa, ".", "", 1)
+	k := len(digits)
+	n := exp + 1 // position of decimal point
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+
+	expSign := "+"
+	if n-1 < 0 {
+		expSign = "-"
+	}
+	expStr := expSign + strconv.Itoa(int(math.Abs(float64(n-1))))
+	if k == 1 {
+		return sign + digits + "e" + expStr
+	}
+	return sign + digits[:1] + "." + digits[1:] + "e" + expStr
+}
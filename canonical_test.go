@@ -0,0 +1,160 @@
+package jsonvalue
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func testCanonical(t *testing.T) {
+	cv("canonical numbers", func() { testCanonicalNumbers(t) })
+	cv("canonical strings", func() { testCanonicalStrings(t) })
+	cv("canonical objects and arrays", func() { testCanonicalObjectsAndArrays(t) })
+	cv("canonical errors", func() { testCanonicalErrors(t) })
+}
+
+func testCanonicalNumbers(t *testing.T) {
+	cv("RFC 8785 appendix B", func() {
+		cases := []struct {
+			bits uint64
+			s    string
+		}{
+			{0x0000000000000000, "0"},
+			{0x8000000000000000, "0"},
+			{0x0000000000000001, "5e-324"},
+			{0x8000000000000001, "-5e-324"},
+			{0x7fefffffffffffff, "1.7976931348623157e+308"},
+			{0xffefffffffffffff, "-1.7976931348623157e+308"},
+			{0x4340000000000000, "9007199254740992"},
+			{0xc340000000000000, "-9007199254740992"},
+			{0x4430000000000000, "295147905179352830000"},
+			{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+			{0x44b52d02c7e14af6, "1e+23"},
+			{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+			{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+			{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+			{0x444b1ae4d6e2ef50, "1e+21"},
+			{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+			{0x3eb0c6f7a0b5ed8d, "0.000001"},
+			{0x41b3de4355555553, "333333333.3333332"},
+			{0x41b3de4355555554, "333333333.33333325"},
+			{0x41b3de4355555555, "333333333.3333333"},
+			{0x41b3de4355555556, "333333333.3333334"},
+			{0x41b3de4355555557, "333333333.33333343"},
+			{0xbecbf647612f3696, "-0.0000033333333333333333"},
+			{0x43143ff3c1cb0959, "1424953923781206.2"},
+		}
+		for _, c := range cases {
+			v := NewFloat64(math.Float64frombits(c.bits))
+			b, err := v.MarshalCanonical()
+			so(err, isNil)
+			so(string(b), eq, c.s)
+		}
+	})
+
+	cv("numbers from raw text", func() {
+		cases := [][2]string{
+			{`1.0`, `1`},
+			{`-0.0`, `0`},
+			{`1E2`, `100`},
+			{`0.1e1`, `1`},
+			{`12.5e21`, `1.25e+22`},
+			{`1e-7`, `1e-7`},
+			{`0.0000001234`, `1.234e-7`},
+		}
+		for _, c := range cases {
+			v := MustUnmarshalString(c[0])
+			so(string(v.MustMarshalCanonical()), eq, c[1])
+		}
+	})
+}
+
+func testCanonicalStrings(t *testing.T) {
+	v := NewString("\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\\/</\u2028")
+	b, err := v.MarshalCanonical()
+	so(err, isNil)
+	so(string(b), eq, "\"€$\\u000f\\nA'B\\\"\\\\\\\\\\\"\\\\/</\u2028\"")
+
+	v = NewString("\b\t\f\r\x01\x1f")
+	so(string(v.MustMarshalCanonical()), eq, `"\b\t\f\r\u0001\u001f"`)
+}
+
+func testCanonicalObjectsAndArrays(t *testing.T) {
+	cv("RFC 8785 section 3.2.3 sorting", func() {
+		raw := `{
+			"\u20ac": "Euro Sign",
+			"\r": "Carriage Return",
+			"\ufb33": "Hebrew Letter Dalet With Dagesh",
+			"1": "One",
+			"\ud83d\ude00": "Emoji: Grinning Face",
+			"\u0080": "Control",
+			"\u00f6": "Latin Small Letter O With Diaeresis"
+		}`
+		v := MustUnmarshalString(raw)
+		b, err := v.MarshalCanonical()
+		so(err, isNil)
+		so(string(b), eq, "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\","+
+			"\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\","+
+			"\"😀\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}")
+	})
+
+	cv("RFC 8785 section 3.2.2 example", func() {
+		raw := `{
+			"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+			"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+			"literals": [null, true, false]
+		}`
+		v := MustUnmarshalString(raw)
+		b, err := v.MarshalCanonical()
+		so(err, isNil)
+		so(string(b), eq, `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],`+
+			`"string":"€$\u000f\nA'B\"\\\\\"/"}`)
+	})
+
+	cv("nested and empty values", func() {
+		v := MustUnmarshalString(`{ "b" : [ {}, [ ] , { "y":1, "x":2 } ], "a" : { } }`)
+		so(string(v.MustMarshalCanonical()), eq, `{"a":{},"b":[{},[],{"x":2,"y":1}]}`)
+	})
+
+	cv("key sequence options do not affect output", func() {
+		v := NewObject()
+		v.MustSet(1).At("b")
+		v.MustSet(2).At("a")
+		so(v.MustMarshalString(OptSetSequence()), eq, `{"b":1,"a":2}`)
+		so(string(v.MustMarshalCanonical()), eq, `{"a":2,"b":1}`)
+	})
+}
+
+func testCanonicalErrors(t *testing.T) {
+	cv("uninitialized", func() {
+		var v *V
+		_, err := v.MarshalCanonical()
+		so(errors.Is(err, ErrValueUninitialized), isTrue)
+
+		_, err = (&V{}).MarshalCanonical()
+		so(errors.Is(err, ErrValueUninitialized), isTrue)
+	})
+
+	cv("unsupported float", func() {
+		for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			v := NewArray()
+			v.MustAppend(NewFloat64(f)).InTheEnd()
+			_, err := v.MarshalCanonical()
+			so(errors.Is(err, ErrUnsupportedFloat), isTrue)
+		}
+	})
+
+	cv("invalid UTF-8", func() {
+		v := NewObject()
+		v.MustSet("\xff").At("a")
+		_, err := v.MarshalCanonical()
+		so(errors.Is(err, ErrIllegalString), isTrue)
+
+		v = NewObject()
+		v.MustSet("a").At("\xfe")
+		_, err = v.MarshalCanonical()
+		so(errors.Is(err, ErrIllegalString), isTrue)
+
+		so(func() { v.MustMarshalCanonical() }, shouldPanic)
+	})
+}
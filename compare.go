@@ -105,12 +105,13 @@ func numberEqual(left, right *V) bool {
 }
 
 func objectEqual(left, right *V) bool {
-	if len(left.children.object) != len(right.children.object) {
+	lc, rc := left.loadedChildren(), right.loadedChildren()
+	if len(lc.object) != len(rc.object) {
 		return false
 	}
 
-	for k, leftChild := range left.children.object {
-		rightChild, exist := right.children.object[k]
+	for k, leftChild := range lc.object {
+		rightChild, exist := rc.object[k]
 		if !exist {
 			return false
 		}
@@ -122,12 +123,13 @@ func objectEqual(left, right *V) bool {
 }
 
 func arrayEqual(left, right *V) bool {
-	if len(left.children.arr) != len(right.children.arr) {
+	lc, rc := left.loadedChildren(), right.loadedChildren()
+	if len(lc.arr) != len(rc.arr) {
 		return false
 	}
 
-	for i, leftChild := range left.children.arr {
-		rightChild := right.children.arr[i]
+	for i, leftChild := range lc.arr {
+		rightChild := rc.arr[i]
 		if !leftChild.Equal(rightChild) {
 			return false
 		}
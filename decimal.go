@@ -0,0 +1,92 @@
+package jsonvalue
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ================ DECIMAL ================
+
+// NewDecimal returns an initialized num jsonvalue value by exact decimal type.
+// The scale of d is kept in marshaled text, for example 1.50 is marshaled as
+// "1.50" rather than "1.5".
+//
+// NewDecimal 根据给定的精确十进制数返回一个初始化好的数字类型的 jsonvalue 值。序列化文本会保留
+// d 的小数位数, 比如 1.50 会序列化为 "1.50" 而不是 "1.5"。
+func NewDecimal(d decimal.Decimal) *V {
+	v := new(globalPool{}, Number)
+	setDecimalToNumber(v, d)
+	return v
+}
+
+// Add adds d to current number value exactly, and keeps the larger scale of
+// both operands.
+//
+// Add 将 d 精确地加到当前数字值上, 结果保留两个操作数中较大的小数位数。
+func (v *V) Add(d decimal.Decimal) error {
+	return v.operateDecimal(func(cur decimal.Decimal) decimal.Decimal {
+		return cur.Add(d)
+	})
+}
+
+// Sub subtracts d from current number value exactly, and keeps the larger scale
+// of both operands.
+//
+// Sub 从当前数字值中精确地减去 d, 结果保留两个操作数中较大的小数位数。
+func (v *V) Sub(d decimal.Decimal) error {
+	return v.operateDecimal(func(cur decimal.Decimal) decimal.Decimal {
+		return cur.Sub(d)
+	})
+}
+
+// Mul multiplies current number value by d exactly. The scale of the result is
+// the sum of scales of both operands.
+//
+// Mul 将当前数字值精确地乘以 d。结果的小数位数为两个操作数的小数位数之和。
+func (v *V) Mul(d decimal.Decimal) error {
+	return v.operateDecimal(func(cur decimal.Decimal) decimal.Decimal {
+		return cur.Mul(d)
+	})
+}
+
+// Round rounds current number value to given decimal places, with half away
+// from zero. Negative places rounds integer part, for example 1234 with places
+// -2 becomes 1200. The result keeps exactly given places, such as 1.5 with
+// places 2 becomes 1.50.
+//
+// Round 将当前数字值四舍五入 (远离零方向) 到指定的小数位数。负数的 places 会对整数部分进行舍入,
+// 比如 1234 以 -2 舍入后为 1200。结果会严格保留指定的小数位数, 比如 1.5 以 2 舍入后为 1.50。
+func (v *V) Round(places int32) error {
+	return v.operateDecimal(func(cur decimal.Decimal) decimal.Decimal {
+		return cur.Round(places)
+	})
+}
+
+func (v *V) operateDecimal(op func(decimal.Decimal) decimal.Decimal) error {
+	if v == nil || v.valueType == NotExist {
+		return ErrValueUninitialized
+	}
+	if v.valueType != Number {
+		return ErrTypeNotMatch
+	}
+	cur, err := decimal.NewFromString(string(v.srcByte))
+	if err != nil {
+		return ErrUnsupportedFloat // NaN or ±Inf
+	}
+	setDecimalToNumber(v, op(cur))
+	return nil
+}
+
+// setDecimalToNumber replaces number content of v with d.
+func setDecimalToNumber(v *V, d decimal.Decimal) {
+	exp := d.Exponent()
+	if exp < 0 {
+		v.srcByte = []byte(d.StringFixed(-exp))
+	} else {
+		v.srcByte = []byte(d.String())
+	}
+
+	v.num.negative = d.Sign() < 0
+	v.num.floated = exp < 0
+	v.num.f64 = d.InexactFloat64()
+	setSaturatedNum(v, d.Truncate(0).BigInt())
+}
@@ -0,0 +1,129 @@
+package jsonvalue
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func testDecimal(t *testing.T) {
+	cv("decimal accessors", func() { testDecimalAccessors(t) })
+	cv("decimal arithmetic", func() { testDecimalArithmetic(t) })
+	cv("decimal errors", func() { testDecimalErrors(t) })
+}
+
+func testDecimalAccessors(t *testing.T) {
+	cv("get exact value", func() {
+		raw := `{"price":12345678901234567890.123456789,"Count":3,"str":"0.1","neg":-0.000001}`
+		v := MustUnmarshalString(raw)
+
+		d, err := v.GetDecimal("price")
+		so(err, isNil)
+		so(d.String(), eq, "12345678901234567890.123456789")
+
+		d, err = v.GetDecimal("neg")
+		so(err, isNil)
+		so(d.String(), eq, "-0.000001")
+
+		d, err = v.Caseless().GetDecimal("count")
+		so(err, isNil)
+		so(d.IntPart(), eq, 3)
+
+		d, err = v.GetDecimal("str")
+		so(errors.Is(err, ErrTypeNotMatch), isTrue)
+		so(d.String(), eq, "0.1")
+
+		d, err = v.GetDecimal("not exist")
+		so(errors.Is(err, ErrNotFound), isTrue)
+		so(d.IsZero(), isTrue)
+
+		so(NewBool(true).Decimal().IsZero(), isTrue)
+		so(NewString("1.25").Decimal().String(), eq, "1.25")
+		so(NewFloat64(math.NaN()).Decimal().IsZero(), isTrue)
+
+		var nilV *V
+		so(nilV.Decimal().IsZero(), isTrue)
+	})
+
+	cv("new and set", func() {
+		d := decimal.RequireFromString("12345678901234567890.123456789")
+		v := NewDecimal(d)
+		so(v.ValueType(), eq, Number)
+		so(v.MustMarshalString(), eq, "12345678901234567890.123456789")
+		so(v.Decimal().Equal(d), isTrue)
+		so(v.IsFloat(), isTrue)
+
+		v = NewDecimal(decimal.RequireFromString("1.50"))
+		so(v.MustMarshalString(), eq, "1.50")
+		so(v.Float64(), eq, 1.5)
+		so(v.Int(), eq, 1)
+
+		v = NewDecimal(decimal.New(-12, 0))
+		so(v.MustMarshalString(), eq, "-12")
+		so(v.Int64(), eq, -12)
+		so(v.IsInteger(), isTrue)
+
+		v = NewDecimal(decimal.RequireFromString("18446744073709551615"))
+		so(v.Uint64(), eq, uint64(math.MaxUint64))
+
+		o := NewObject()
+		_, err := o.SetDecimal(decimal.RequireFromString("0.30")).At("a", "b")
+		so(err, isNil)
+		o.MustSetDecimal(decimal.RequireFromString("-7.125")).At("c")
+		so(o.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":{"b":0.30},"c":-7.125}`)
+	})
+}
+
+func testDecimalArithmetic(t *testing.T) {
+	cv("no float rounding", func() {
+		v := MustUnmarshalString(`{"amount":0.1}`)
+		amount := v.MustGet("amount")
+		so(amount.Add(decimal.RequireFromString("0.2")), isNil)
+		so(amount.MustMarshalString(), eq, "0.3")
+		so(v.MustMarshalString(), eq, `{"amount":0.3}`)
+		so(amount.Float64(), eq, 0.3)
+	})
+
+	cv("add, sub, mul and round", func() {
+		v := MustUnmarshalString(`12345678901234567890.123456789`)
+		so(v.Add(decimal.RequireFromString("0.876543211")), isNil)
+		so(v.MustMarshalString(), eq, "12345678901234567891.000000000")
+
+		v = MustUnmarshalString(`10.00`)
+		so(v.Sub(decimal.RequireFromString("0.01")), isNil)
+		so(v.MustMarshalString(), eq, "9.99")
+		so(v.Sub(decimal.NewFromInt(20)), isNil)
+		so(v.MustMarshalString(), eq, "-10.01")
+		so(v.Int64(), eq, -10)
+
+		v = MustUnmarshalString(`19.99`)
+		so(v.Mul(decimal.NewFromInt(3)), isNil)
+		so(v.MustMarshalString(), eq, "59.97")
+		so(v.Mul(decimal.RequireFromString("0.085")), isNil)
+		so(v.MustMarshalString(), eq, "5.09745")
+		so(v.Round(2), isNil)
+		so(v.MustMarshalString(), eq, "5.10")
+		so(v.Round(0), isNil)
+		so(v.MustMarshalString(), eq, "5")
+		so(v.IsInteger(), isTrue)
+
+		v = NewInt(1234)
+		so(v.Round(-2), isNil)
+		so(v.MustMarshalString(), eq, "1200")
+
+		v = NewFloat64(-2.5)
+		so(v.Round(0), isNil)
+		so(v.Int(), eq, -3)
+	})
+}
+
+func testDecimalErrors(t *testing.T) {
+	var nilV *V
+	so(errors.Is(nilV.Add(decimal.Zero), ErrValueUninitialized), isTrue)
+	so(errors.Is((&V{}).Sub(decimal.Zero), ErrValueUninitialized), isTrue)
+	so(errors.Is(NewString("1").Mul(decimal.Zero), ErrTypeNotMatch), isTrue)
+	so(errors.Is(NewObject().Round(1), ErrTypeNotMatch), isTrue)
+	so(errors.Is(NewFloat64(math.Inf(1)).Add(decimal.Zero), ErrUnsupportedFloat), isTrue)
+}
@@ -0,0 +1,225 @@
+package jsonvalue
+
+import (
+	"io"
+)
+
+const decoderMinReadSize = 512
+
+// Decoder reads and decodes top-level JSON values one by one from an input
+// stream. Values could be concatenated directly or separated by blank
+// characters, such as `{"a":1} {"b":2}[3]`.
+//
+// Decoder 从一个输入流中逐个读取并解析顶层 JSON 值。这些值可以直接首尾相连, 也可以用空白字符
+// 分隔, 如 `{"a":1} {"b":2}[3]`。
+type Decoder struct {
+	readBuffer
+	scan valueScanner // state of the value being read
+}
+
+// readBuffer buffers data read from an input stream incrementally.
+type readBuffer struct {
+	r   io.Reader
+	buf []byte
+
+	scanp   int   // start of unread data in buf
+	scanned int64 // amount of data already scanned and removed from buf
+
+	err error // sticky reading error
+}
+
+// NewDecoder returns a new decoder that reads from r. Data from r is read
+// incrementally and buffered only until a complete value is available.
+//
+// NewDecoder 返回一个从 r 中读取数据的解码器。数据会被增量读取, 只缓存到能够组成一个完整的值为止。
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		readBuffer: readBuffer{
+			r: r,
+		},
+	}
+}
+
+// Decode reads the next JSON value from its input and returns it. io.EOF will
+// be returned if there are no more values in the input stream.
+//
+// If a complete value is read but it is not a valid JSON value, the decoder
+// skips over it and returns the parsing error. Further Decode calls are still
+// available. If the input stream ends in the middle of a value, a syntax error
+// is returned once, and further calls return io.EOF.
+//
+// Decode 从输入流中读取下一个 JSON 值并返回。如果输入流中已经没有更多的值了, 则返回 io.EOF。
+//
+// 如果读取到了一个完整的值, 但这个值不是合法的 JSON, 那么解码器会跳过这个值并返回解析错误, 后续
+// 依然可以继续调用 Decode。如果输入流在某个值的中间结束, 则会返回一次语法错误, 后续调用返回 io.EOF。
+func (dec *Decoder) Decode() (*V, error) {
+	if dec.r == nil {
+		return &V{}, ErrNilParameter
+	}
+
+	n, err := dec.readValue()
+	if err != nil {
+		return &V{}, err
+	}
+
+	// V values generated by the parser refer to the given bytes, therefore a
+	// separated copy is necessary as the decoder buffer would be reused.
+	src := dec.buf[dec.scanp : dec.scanp+n]
+	b := make([]byte, n)
+	copy(b, src)
+
+	p := newPool(n)
+	v, err := unmarshalWithIter(p, iter(b), 0)
+	p.release()
+	if err != nil {
+		err = locateSyntaxError(err, src)
+	}
+
+	dec.scanp += n
+	dec.scan = valueScanner{}
+	return v, err
+}
+
+// InputOffset returns the input stream byte offset of the current decoder
+// position.
+//
+// InputOffset 返回解码器当前在输入流中的字节偏移量。
+func (dec *Decoder) InputOffset() int64 {
+	return dec.inputOffset()
+}
+
+func (rb *readBuffer) inputOffset() int64 {
+	return rb.scanned + int64(rb.scanp)
+}
+
+// readValue skips leading blanks and ensures that a complete value is
+// buffered in dec.buf[dec.scanp:]. The length of the value is returned. The
+// scanning state is kept in dec.scan, so that data is scanned only once.
+func (dec *Decoder) readValue() (int, error) {
+	for {
+		reachEnd := false
+		if dec.scan.n == 0 {
+			dec.scanp, reachEnd = iter(dec.buf).skipBlanks(dec.scanp)
+		}
+
+		if !reachEnd {
+			n, complete := dec.scan.scan(dec.buf[dec.scanp:], dec.err != nil)
+			if complete {
+				return n, nil
+			}
+		}
+
+		if dec.err != nil {
+			if dec.err != io.EOF {
+				return 0, dec.err
+			}
+			if reachEnd {
+				return 0, io.EOF
+			}
+			// the incomplete value is dropped, so that io.EOF is returned next time
+			rest := dec.buf[dec.scanp:]
+			dec.scanp = len(dec.buf)
+			dec.scan = valueScanner{}
+			err := newSyntaxError(ErrRawBytesUnrecognized, len(rest), "", "unexpected EOF")
+			return 0, locateSyntaxError(err, rest)
+		}
+
+		dec.refill()
+	}
+}
+
+// refill reads more data from the underlying reader.
+func (rb *readBuffer) refill() {
+	// move unread data to the beginning of buffer
+	if rb.scanp > 0 {
+		rb.scanned += int64(rb.scanp)
+		n := copy(rb.buf, rb.buf[rb.scanp:])
+		rb.buf = rb.buf[:n]
+		rb.scanp = 0
+	}
+
+	if cap(rb.buf)-len(rb.buf) < decoderMinReadSize {
+		newBuf := make([]byte, len(rb.buf), 2*cap(rb.buf)+decoderMinReadSize)
+		copy(newBuf, rb.buf)
+		rb.buf = newBuf
+	}
+
+	n, err := rb.r.Read(rb.buf[len(rb.buf):cap(rb.buf)])
+	rb.buf = rb.buf[:len(rb.buf)+n]
+	if err != nil {
+		rb.err = err
+	}
+}
+
+// valueScanner finds out the boundary of a top-level value incrementally. As
+// data is appended to the buffer, scanning continues from where it stopped
+// last time. Validation is left to the parser.
+type valueScanner struct {
+	n        int  // count of scanned bytes from start of the value
+	depth    int  // nesting depth of arrays and objects
+	inString bool // inside a string
+	escaped  bool // previous byte in string is an escaping backslash
+}
+
+// scan continues scanning a value which starts at b[0]. b should contain all
+// bytes given in previous calls. The length of the value is returned if it is
+// complete.
+func (s *valueScanner) scan(b []byte, atEOF bool) (n int, complete bool) {
+	switch b[0] {
+	case '{', '[':
+		return s.scanComposite(b)
+	case '"':
+		if s.n == 0 {
+			s.n, s.inString = 1, true
+		}
+		return s.scanComposite(b)
+	}
+
+	// number, true, false, null or illegal characters
+	for i := s.n; i < len(b); i++ {
+		switch b[i] {
+		case ' ', '\r', '\n', '\t', '\b', '{', '}', '[', ']', ',', ':', '"':
+			if i == 0 {
+				return 1, true
+			}
+			return i, true
+		}
+	}
+	s.n = len(b)
+	return len(b), atEOF
+}
+
+// scanComposite scans an array, an object or a string.
+func (s *valueScanner) scanComposite(b []byte) (n int, complete bool) {
+	for i := s.n; i < len(b); i++ {
+		chr := b[i]
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case chr == '\\':
+				s.escaped = true
+			case chr == '"':
+				s.inString = false
+				if s.depth == 0 {
+					return i + 1, true
+				}
+			}
+			continue
+		}
+
+		switch chr {
+		case '{', '[':
+			s.depth++
+		case '}', ']':
+			s.depth--
+			if s.depth == 0 {
+				return i + 1, true
+			}
+		case '"':
+			s.inString = true
+		}
+	}
+	s.n = len(b)
+	return 0, false
+}
@@ -0,0 +1,188 @@
+package jsonvalue
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func testDecoder(t *testing.T) {
+	cv("general decoding", func() { testDecoderGeneral(t) })
+	cv("decode from small reads", func() { testDecoderSmallReads(t) })
+	cv("decode large values", func() { testDecoderLargeValue(t) })
+	cv("decoding errors", func() { testDecoderErrors(t) })
+}
+
+func decodeAll(dec *Decoder) (res []*V, err error) {
+	for {
+		v, err := dec.Decode()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return res, err
+		}
+		res = append(res, v)
+	}
+}
+
+func testDecoderGeneral(t *testing.T) {
+	cv("concatenated values", func() {
+		raw := `{"a":1}{"b":[2,"}"]}[3,{"c":"\"]"}]"string"`
+		dec := NewDecoder(strings.NewReader(raw))
+		values, err := decodeAll(dec)
+		so(err, isNil)
+		so(len(values), eq, 4)
+
+		so(values[0].MustGet("a").Int(), eq, 1)
+		so(values[1].MustGet("b", 1).String(), eq, "}")
+		so(values[2].MustGet(1, "c").String(), eq, `"]`)
+		so(values[3].String(), eq, "string")
+		so(dec.InputOffset(), eq, len(raw))
+	})
+
+	cv("whitespace separated values", func() {
+		raw := " 1 -2.5\n true\tfalse\r\nnull {} [] \"\" "
+		values, err := decodeAll(NewDecoder(strings.NewReader(raw)))
+		so(err, isNil)
+		so(len(values), eq, 8)
+
+		so(values[0].Int(), eq, 1)
+		so(values[1].Float64(), eq, -2.5)
+		so(values[2].Bool(), isTrue)
+		so(values[3].IsBoolean(), isTrue)
+		so(values[3].Bool(), isFalse)
+		so(values[4].IsNull(), isTrue)
+		so(values[5].IsObject(), isTrue)
+		so(values[6].IsArray(), isTrue)
+		so(values[7].IsString(), isTrue)
+	})
+
+	cv("values keep unchanged after further decoding", func() {
+		dec := NewDecoder(strings.NewReader(`{"msg":"hello"} {"msg":"world"}`))
+		v1, err := dec.Decode()
+		so(err, isNil)
+		v2, err := dec.Decode()
+		so(err, isNil)
+		so(v1.MustGet("msg").String(), eq, "hello")
+		so(v2.MustGet("msg").String(), eq, "world")
+	})
+
+	cv("empty input", func() {
+		dec := NewDecoder(strings.NewReader(" \r\n\t "))
+		_, err := dec.Decode()
+		so(err, eq, io.EOF)
+		_, err = dec.Decode()
+		so(err, eq, io.EOF)
+	})
+
+	cv("nil reader", func() {
+		_, err := NewDecoder(nil).Decode()
+		so(errors.Is(err, ErrNilParameter), isTrue)
+	})
+}
+
+func testDecoderSmallReads(t *testing.T) {
+	raw := `{"int":123456,"float":123.456,"string":"Hello, 世界","arr":[true,false,null]} 1234567 "a\"b"` +
+		`{"s":"\\\"}]\\"}`
+
+	r := iotest.OneByteReader(strings.NewReader(raw))
+	values, err := decodeAll(NewDecoder(r))
+	so(err, isNil)
+	so(len(values), eq, 4)
+
+	so(values[0].MustGet("int").Int(), eq, 123456)
+	so(values[0].MustGet("float").Float64(), eq, 123.456)
+	so(values[0].MustGet("string").String(), eq, "Hello, 世界")
+	so(values[0].MustGet("arr").Len(), eq, 3)
+	so(values[1].Int(), eq, 1234567)
+	so(values[2].String(), eq, `a"b`)
+	so(values[3].MustGet("s").String(), eq, `\"}]\`)
+
+	r = iotest.DataErrReader(strings.NewReader(`[1,2,3]`))
+	values, err = decodeAll(NewDecoder(r))
+	so(err, isNil)
+	so(len(values), eq, 1)
+	so(values[0].Len(), eq, 3)
+}
+
+func testDecoderLargeValue(t *testing.T) {
+	buf := bytes.Buffer{}
+	cnt := 10000
+	for i := 0; i < 3; i++ {
+		arr := NewArray()
+		for j := 0; j < cnt; j++ {
+			arr.MustAppendInt(j).InTheEnd()
+		}
+		buf.Write(arr.MustMarshal())
+		buf.WriteByte('\n')
+	}
+
+	raw := buf.String()
+	values, err := decodeAll(NewDecoder(&buf))
+	so(err, isNil)
+	so(len(values), eq, 3)
+	for _, v := range values {
+		so(v.Len(), eq, cnt)
+		so(v.MustGet(cnt-1).Int(), eq, cnt-1)
+	}
+
+	// each byte is scanned only once, even if it is read byte by byte
+	values, err = decodeAll(NewDecoder(iotest.OneByteReader(strings.NewReader(raw))))
+	so(err, isNil)
+	so(len(values), eq, 3)
+	so(values[2].MustGet(cnt-1).Int(), eq, cnt-1)
+}
+
+func testDecoderErrors(t *testing.T) {
+	cv("invalid value does not break decoder", func() {
+		dec := NewDecoder(strings.NewReader(`{"a":1} {"b"} [2]`))
+
+		v, err := dec.Decode()
+		so(err, isNil)
+		so(v.MustGet("a").Int(), eq, 1)
+
+		_, err = dec.Decode()
+		so(err, isErr)
+
+		v, err = dec.Decode()
+		so(err, isNil)
+		so(v.MustGet(0).Int(), eq, 2)
+
+		_, err = dec.Decode()
+		so(err, eq, io.EOF)
+	})
+
+	cv("invalid literal", func() {
+		dec := NewDecoder(strings.NewReader(`nul 1`))
+		_, err := dec.Decode()
+		so(err, isErr)
+
+		v, err := dec.Decode()
+		so(err, isNil)
+		so(v.Int(), eq, 1)
+	})
+
+	cv("unexpected EOF", func() {
+		dec := NewDecoder(strings.NewReader(`{"a":[1,2`))
+		_, err := dec.Decode()
+		so(err, isErr)
+		so(errors.Is(err, ErrRawBytesUnrecognized), isTrue)
+		_, err = dec.Decode()
+		so(err, eq, io.EOF)
+		so(dec.InputOffset(), eq, 9)
+
+		dec = NewDecoder(strings.NewReader(`"abc`))
+		_, err = dec.Decode()
+		so(err, isErr)
+	})
+
+	cv("reader error", func() {
+		r := iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader(`[1,2]`)))
+		_, err := NewDecoder(r).Decode()
+		so(errors.Is(err, iotest.ErrTimeout), isTrue)
+	})
+}
@@ -0,0 +1,391 @@
+package jsonvalue
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ================ DIFF ================
+
+// DiffType tells how a value differs between two JSON values.
+//
+// DiffType 表示两个 JSON 值之间某个值的差异类型。
+type DiffType uint8
+
+const (
+	// DiffAdded indicates that a value exists only in the second JSON value.
+	//
+	// DiffAdded 表示一个值仅存在于第二个 JSON 值中。
+	DiffAdded DiffType = 1
+	// DiffRemoved indicates that a value exists only in the first JSON value.
+	//
+	// DiffRemoved 表示一个值仅存在于第一个 JSON 值中。
+	DiffRemoved DiffType = 2
+	// DiffChanged indicates that a value has the same type but different content.
+	//
+	// DiffChanged 表示一个值的类型相同, 但内容不同。
+	DiffChanged DiffType = 3
+	// DiffTypeChanged indicates that a value has different types.
+	//
+	// DiffTypeChanged 表示一个值的类型不同。
+	DiffTypeChanged DiffType = 4
+)
+
+// String returns description of the diff type.
+func (t DiffType) String() string {
+	switch t {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	case DiffTypeChanged:
+		return "type changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Difference describes one difference between two JSON values. For DiffAdded,
+// Old is a value with NotExist type, while for DiffRemoved, New is.
+//
+// Difference 表示两个 JSON 值之间的一个差异。对于 DiffAdded, Old 是一个 NotExist 类型的值;
+// 而对于 DiffRemoved, New 则是 NotExist 类型的值。
+type Difference struct {
+	Type DiffType
+	Path Path
+	Old  *V
+	New  *V
+}
+
+// String returns a readable description of the difference, such as
+// `changed /data/list/0: 1 -> 2`.
+//
+// String 返回差异的可读描述, 如 `changed /data/list/0: 1 -> 2`。
+func (d Difference) String() string {
+	ptr := d.Path.Pointer()
+	if ptr == "" {
+		ptr = "/"
+	}
+	switch d.Type {
+	case DiffAdded:
+		return fmt.Sprintf("%v %s: %s", d.Type, ptr, d.New.MustMarshalString())
+	case DiffRemoved:
+		return fmt.Sprintf("%v %s: %s", d.Type, ptr, d.Old.MustMarshalString())
+	default:
+		return fmt.Sprintf("%v %s: %s -> %s", d.Type, ptr, d.Old.MustMarshalString(), d.New.MustMarshalString())
+	}
+}
+
+// DiffOption is the option type for Diff function.
+//
+// DiffOption 表示 Diff 函数的选项。
+type DiffOption interface {
+	mergeToDiffOpt(opt *diffOptions)
+}
+
+type diffOptions struct {
+	ignoreArrayOrder bool
+	ignorePaths      [][]string
+	caseless         bool
+	tolerance        float64
+}
+
+// DiffOptIgnoreArrayOrder compares arrays regardless of element sequence. Each
+// element is matched with an equal one in the other array, and unmatched ones
+// are reported as DiffRemoved or DiffAdded.
+//
+// DiffOptIgnoreArrayOrder 表示比较数组时忽略成员的顺序。每个成员都会与另一个数组中相等的成员
+// 进行匹配, 而未能匹配的成员则报告为 DiffRemoved 或 DiffAdded。
+func DiffOptIgnoreArrayOrder() DiffOption {
+	return diffOptIgnoreArrayOrder{}
+}
+
+type diffOptIgnoreArrayOrder struct{}
+
+func (diffOptIgnoreArrayOrder) mergeToDiffOpt(opt *diffOptions) {
+	opt.ignoreArrayOrder = true
+}
+
+// DiffOptIgnorePaths ignores values, including their children, located by given
+// JSON Pointers. A "*" token matches any key or index, such as "/items/*/id".
+// Illegal pointers are ignored.
+//
+// DiffOptIgnorePaths 忽略给定的 JSON Pointer 所指定的值及其子成员。"*" 可以匹配任意键或下标,
+// 如 "/items/*/id"。不合法的 pointer 会被忽略。
+func DiffOptIgnorePaths(pointers ...string) DiffOption {
+	return diffOptIgnorePaths(pointers)
+}
+
+type diffOptIgnorePaths []string
+
+func (o diffOptIgnorePaths) mergeToDiffOpt(opt *diffOptions) {
+	for _, ptr := range o {
+		if tokens, err := parsePointer(ptr); err == nil {
+			opt.ignorePaths = append(opt.ignorePaths, tokens)
+		}
+	}
+}
+
+// DiffOptCaseless compares object keys caselessly. Paths in the result follow
+// keys in the first value if exist.
+//
+// DiffOptCaseless 表示比较 object 的键时忽略大小写。结果中的路径优先使用第一个值中的键。
+func DiffOptCaseless() DiffOption {
+	return diffOptCaseless{}
+}
+
+type diffOptCaseless struct{}
+
+func (diffOptCaseless) mergeToDiffOpt(opt *diffOptions) {
+	opt.caseless = true
+}
+
+// DiffOptNumberTolerance treats two numbers as equal if the absolute value of
+// their difference is not greater than given tolerance.
+//
+// DiffOptNumberTolerance 表示如果两个数字之差的绝对值不大于给定的容差, 则认为它们相等。
+func DiffOptNumberTolerance(tolerance float64) DiffOption {
+	return diffOptNumberTolerance(tolerance)
+}
+
+type diffOptNumberTolerance float64
+
+func (o diffOptNumberTolerance) mergeToDiffOpt(opt *diffOptions) {
+	opt.tolerance = math.Abs(float64(o))
+}
+
+// Diff compares two JSON values and returns all differences between them. Objects
+// and arrays are compared recursively, therefore DiffChanged is only reported for
+// values other than objects and arrays. An empty result means the two values are
+// equal.
+//
+// Diff 比较两个 JSON 值并返回它们之间的全部差异。object 和 array 会被递归地比较, 因此只有
+// object 和 array 之外的值才会报告 DiffChanged。返回空结果表示两个值相等。
+func Diff(a, b *V, opts ...DiffOption) []Difference {
+	d := differ{}
+	for _, o := range opts {
+		if o != nil {
+			o.mergeToDiffOpt(&d.opt)
+		}
+	}
+	if a == nil {
+		a = &V{}
+	}
+	if b == nil {
+		b = &V{}
+	}
+	d.diff(nil, a, b)
+	return d.res
+}
+
+type differ struct {
+	opt diffOptions
+	res []Difference
+}
+
+func (d *differ) add(typ DiffType, path Path, a, b *V) {
+	d.res = append(d.res, Difference{
+		Type: typ,
+		Path: path,
+		Old:  a,
+		New:  b,
+	})
+}
+
+func (d *differ) isIgnored(path Path) bool {
+	for _, tokens := range d.opt.ignorePaths {
+		if len(tokens) > len(path) {
+			continue
+		}
+		matched := true
+		for i, t := range tokens {
+			if t == "*" {
+				continue
+			}
+			item := path[i]
+			if item.Idx >= 0 {
+				matched = t == strconv.Itoa(item.Idx)
+			} else {
+				matched = t == item.Key
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) equal(path Path, a, b *V) bool {
+	sub := differ{opt: d.opt}
+	sub.diff(path, a, b)
+	return len(sub.res) == 0
+}
+
+func (d *differ) diff(path Path, a, b *V) {
+	if d.isIgnored(path) {
+		return
+	}
+
+	switch {
+	case a.valueType == NotExist && b.valueType == NotExist:
+		return
+	case a.valueType == NotExist:
+		d.add(DiffAdded, path, a, b)
+		return
+	case b.valueType == NotExist:
+		d.add(DiffRemoved, path, a, b)
+		return
+	case a.valueType != b.valueType:
+		d.add(DiffTypeChanged, path, a, b)
+		return
+	}
+
+	switch a.valueType {
+	case Object:
+		d.diffObject(path, a, b)
+	case Array:
+		if d.opt.ignoreArrayOrder {
+			d.diffUnorderedArray(path, a, b)
+		} else {
+			d.diffArray(path, a, b)
+		}
+	case Number:
+		if !d.numberEqual(a, b) {
+			d.add(DiffChanged, path, a, b)
+		}
+	default:
+		if !a.Equal(b) {
+			d.add(DiffChanged, path, a, b)
+		}
+	}
+}
+
+func (d *differ) numberEqual(a, b *V) bool {
+	if d.opt.tolerance == 0 {
+		return numberEqual(a, b)
+	}
+	return numberEqual(a, b) || math.Abs(a.Float64()-b.Float64()) <= d.opt.tolerance
+}
+
+func (d *differ) diffObject(path Path, a, b *V) {
+	type keyAndValue struct {
+		k string
+		v *V
+	}
+	var aKeys []keyAndValue
+	a.RangeObjectsBySetSequence(func(k string, c *V) bool {
+		aKeys = append(aKeys, keyAndValue{k, c})
+		return true
+	})
+
+	// keys in a -> keys in b. Exact keys are matched firstly, then caseless ones.
+	bObject := b.loadedChildren().object
+	pairs := make(map[string]string, len(aKeys))
+	matched := make(map[string]bool, len(bObject))
+	for _, kv := range aKeys {
+		if _, exist := bObject[kv.k]; exist {
+			pairs[kv.k] = kv.k
+			matched[kv.k] = true
+		}
+	}
+	if d.opt.caseless {
+		for _, kv := range aKeys {
+			if _, exist := pairs[kv.k]; exist {
+				continue
+			}
+			if bk, ok := caselessObjectKey(b, kv.k, matched); ok {
+				pairs[kv.k] = bk
+				matched[bk] = true
+			}
+		}
+	}
+
+	for _, kv := range aKeys {
+		bc := &V{}
+		if bk, exist := pairs[kv.k]; exist {
+			bc = bObject[bk].v
+		}
+		d.diff(appendDiffPath(path, PathItem{Idx: -1, Key: kv.k}), kv.v, bc)
+	}
+
+	b.RangeObjectsBySetSequence(func(k string, bc *V) bool {
+		if !matched[k] {
+			d.diff(appendDiffPath(path, PathItem{Idx: -1, Key: k}), &V{}, bc)
+		}
+		return true
+	})
+}
+
+// caselessObjectKey searches an unmatched key in object caselessly. If there
+// are multiple ones, the earliest set one is returned.
+func caselessObjectKey(obj *V, key string, matched map[string]bool) (string, bool) {
+	resKey, resID, found := "", uint32(0), false
+	lowerKey := strings.ToLower(key)
+	for k, c := range obj.loadedChildren().object {
+		if matched[k] || strings.ToLower(k) != lowerKey {
+			continue
+		}
+		if !found || c.id < resID {
+			resKey, resID, found = k, c.id, true
+		}
+	}
+	return resKey, found
+}
+
+func (d *differ) diffArray(path Path, a, b *V) {
+	aArr, bArr := a.loadedChildren().arr, b.loadedChildren().arr
+	for i := 0; i < len(aArr) || i < len(bArr); i++ {
+		ac, bc := &V{}, &V{}
+		if i < len(aArr) {
+			ac = aArr[i]
+		}
+		if i < len(bArr) {
+			bc = bArr[i]
+		}
+		d.diff(appendDiffPath(path, PathItem{Idx: i}), ac, bc)
+	}
+}
+
+func (d *differ) diffUnorderedArray(path Path, a, b *V) {
+	bArr := b.loadedChildren().arr
+	matched := make([]bool, len(bArr))
+
+	for i, ac := range a.loadedChildren().arr {
+		found := false
+		for j, bc := range bArr {
+			if matched[j] {
+				continue
+			}
+			if d.equal(appendDiffPath(path, PathItem{Idx: j}), ac, bc) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.diff(appendDiffPath(path, PathItem{Idx: i}), ac, &V{})
+		}
+	}
+
+	for j, bc := range bArr {
+		if !matched[j] {
+			d.diff(appendDiffPath(path, PathItem{Idx: j}), &V{}, bc)
+		}
+	}
+}
+
+// appendDiffPath returns a new path, so that paths in results never share
+// underlying array.
+func appendDiffPath(path Path, item PathItem) Path {
+	res := make(Path, len(path), len(path)+1)
+	copy(res, path)
+	return append(res, item)
+}
@@ -0,0 +1,162 @@
+package jsonvalue
+
+import (
+	"testing"
+)
+
+func testDiff(t *testing.T) {
+	cv("general diff", func() { testDiffGeneral(t) })
+	cv("ignore array order", func() { testDiffIgnoreArrayOrder(t) })
+	cv("ignore paths", func() { testDiffIgnorePaths(t) })
+	cv("caseless", func() { testDiffCaseless(t) })
+	cv("number tolerance", func() { testDiffNumberTolerance(t) })
+}
+
+func diffStrings(diffs []Difference) []string {
+	res := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		res = append(res, d.String())
+	}
+	return res
+}
+
+func testDiffGeneral(t *testing.T) {
+	cv("equal values", func() {
+		a := MustUnmarshalString(`{"a":[1,{"b":null}],"c":1.0,"d":"str","e":true}`)
+		b := MustUnmarshalString(`{"e":true,"d":"str","c":1,"a":[1,{"b":null}]}`)
+		so(len(Diff(a, b)), eq, 0)
+		so(len(Diff(nil, nil)), eq, 0)
+		so(len(Diff(&V{}, nil)), eq, 0)
+	})
+
+	cv("differences", func() {
+		a := MustUnmarshalString(`{"id":1,"name":"Alice","tags":["a","b","c"],"info":{"age":20,"city":"X"},"flag":true,"n":null}`)
+		b := MustUnmarshalString(`{"id":1,"name":"Bob","tags":["a","c"],"info":{"age":"20","zip":"100"},"flag":false,"n":null,"extra":[]}`)
+
+		diffs := Diff(a, b)
+		so(diffStrings(diffs), resemble, []string{
+			`changed /name: "Alice" -> "Bob"`,
+			`changed /tags/1: "b" -> "c"`,
+			`removed /tags/2: "c"`,
+			`type changed /info/age: 20 -> "20"`,
+			`removed /info/city: "X"`,
+			`added /info/zip: "100"`,
+			`changed /flag: true -> false`,
+			`added /extra: []`,
+		})
+
+		so(diffs[0].Type, eq, DiffChanged)
+		so(diffs[0].Path.Pointer(), eq, "/name")
+		so(diffs[0].Old.String(), eq, "Alice")
+		so(diffs[0].New.String(), eq, "Bob")
+
+		so(diffs[2].Type, eq, DiffRemoved)
+		so(diffs[2].New.ValueType(), eq, NotExist)
+		so(diffs[7].Type, eq, DiffAdded)
+		so(diffs[7].Old.ValueType(), eq, NotExist)
+		so(diffs[3].Type, eq, DiffTypeChanged)
+	})
+
+	cv("root values", func() {
+		diffs := Diff(NewInt(1), NewString("1"))
+		so(diffStrings(diffs), resemble, []string{`type changed /: 1 -> "1"`})
+		so(len(diffs[0].Path), eq, 0)
+
+		diffs = Diff(nil, NewInt(1))
+		so(diffStrings(diffs), resemble, []string{`added /: 1`})
+		diffs = Diff(NewInt(1), nil)
+		so(diffStrings(diffs), resemble, []string{`removed /: 1`})
+	})
+
+	cv("diff type string", func() {
+		so(DiffAdded.String(), eq, "added")
+		so(DiffRemoved.String(), eq, "removed")
+		so(DiffChanged.String(), eq, "changed")
+		so(DiffTypeChanged.String(), eq, "type changed")
+		so(DiffType(0).String(), eq, "unknown")
+	})
+}
+
+func testDiffIgnoreArrayOrder(t *testing.T) {
+	a := MustUnmarshalString(`{"list":[{"id":1},{"id":2},{"id":3},3]}`)
+	b := MustUnmarshalString(`{"list":[3.0,{"id":3},{"id":1},{"id":4}]}`)
+
+	so(diffStrings(Diff(a, b, DiffOptIgnoreArrayOrder())), resemble, []string{
+		`removed /list/1: {"id":2}`,
+		`added /list/3: {"id":4}`,
+	})
+
+	b = MustUnmarshalString(`{"list":[3,{"id":3},{"id":2},{"id":1}]}`)
+	so(len(Diff(a, b, DiffOptIgnoreArrayOrder())), eq, 0)
+	so(len(Diff(a, b)), ne, 0)
+
+	// duplicated elements
+	a = MustUnmarshalString(`[1,1,2]`)
+	b = MustUnmarshalString(`[2,1,2]`)
+	so(diffStrings(Diff(a, b, DiffOptIgnoreArrayOrder())), resemble, []string{
+		`removed /1: 1`,
+		`added /2: 2`,
+	})
+}
+
+func testDiffIgnorePaths(t *testing.T) {
+	a := MustUnmarshalString(`{"ts":1,"data":{"items":[{"id":1,"v":"a"},{"id":2,"v":"b"}],"a/b":1}}`)
+	b := MustUnmarshalString(`{"ts":2,"data":{"items":[{"id":3,"v":"a"},{"id":4,"v":"c"}],"a/b":2}}`)
+
+	so(len(Diff(a, b)), eq, 5)
+
+	diffs := Diff(a, b, DiffOptIgnorePaths("/ts", "/data/items/*/id", "/data/a~1b", "illegal"))
+	so(diffStrings(diffs), resemble, []string{`changed /data/items/1/v: "b" -> "c"`})
+
+	diffs = Diff(a, b, DiffOptIgnorePaths("/ts", "/data/items/1", "/data/a~1b"), DiffOptIgnorePaths("/data/items/0/id"))
+	so(len(diffs), eq, 0)
+
+	so(len(Diff(a, b, DiffOptIgnorePaths(""))), eq, 0)
+	so(len(Diff(a, b, DiffOptIgnorePaths("/data"), nil)), eq, 1)
+
+	// works with unordered arrays
+	a = MustUnmarshalString(`[{"id":1,"v":"a"},{"id":2,"v":"b"}]`)
+	b = MustUnmarshalString(`[{"id":3,"v":"b"},{"id":4,"v":"a"}]`)
+	so(len(Diff(a, b, DiffOptIgnoreArrayOrder(), DiffOptIgnorePaths("/*/id"))), eq, 0)
+}
+
+func testDiffCaseless(t *testing.T) {
+	a := MustUnmarshalString(`{"Name":"Alice","AGE":20,"info":{"City":"X"}}`)
+	b := MustUnmarshalString(`{"name":"Alice","age":21,"Info":{"city":"X"},"Extra":1}`)
+
+	so(len(Diff(a, b)), eq, 7)
+	so(diffStrings(Diff(a, b, DiffOptCaseless())), resemble, []string{
+		`changed /AGE: 20 -> 21`,
+		`added /Extra: 1`,
+	})
+
+	// exact keys go first, and each key is matched only once
+	a = MustUnmarshalString(`{"a":1,"A":2}`)
+	b = MustUnmarshalString(`{"A":2,"a":1}`)
+	so(len(Diff(a, b, DiffOptCaseless())), eq, 0)
+
+	a = MustUnmarshalString(`{"A":1,"a":2}`)
+	b = MustUnmarshalString(`{"a":1}`)
+	so(diffStrings(Diff(a, b, DiffOptCaseless())), resemble, []string{
+		`removed /A: 1`,
+		`changed /a: 2 -> 1`,
+	})
+
+	a = MustUnmarshalString(`{"A":1,"b":2}`)
+	b = MustUnmarshalString(`{"B":2,"a":1,"Aa":3}`)
+	so(diffStrings(Diff(a, b, DiffOptCaseless())), resemble, []string{
+		`added /Aa: 3`,
+	})
+}
+
+func testDiffNumberTolerance(t *testing.T) {
+	a := MustUnmarshalString(`{"a":1.0001,"b":[100,200],"c":"1"}`)
+	b := MustUnmarshalString(`{"a":1.0002,"b":[100.4,201],"c":"1.0"}`)
+
+	so(len(Diff(a, b)), eq, 4)
+	so(diffStrings(Diff(a, b, DiffOptNumberTolerance(0.5))), resemble, []string{
+		`changed /b/1: 200 -> 201`,
+		`changed /c: "1" -> "1.0"`,
+	})
+	so(len(Diff(a, b, DiffOptNumberTolerance(-1))), eq, 1)
+}
@@ -0,0 +1,600 @@
+package jsonvalue
+
+import (
+	"sort"
+)
+
+// ================ FORMAT-PRESERVING DOCUMENT ================
+
+// Document is a parsed JSON text which keeps original formatting, such as
+// comments, blank lines, key sequence and indentation. Set, Delete and Append
+// of a Document only rewrite the text spans they touch, therefore automated
+// edits of hand-maintained configuration files produce minimal diffs.
+//
+// Document 表示一个保留了原始格式 (如注释, 空行, 键的顺序以及缩进) 的已解析 JSON 文本。Document
+// 的 Set, Delete 和 Append 操作只会重写其所涉及的文本片段, 因此对手工维护的配置文件进行自动化编辑时,
+// 产生的差异最小。
+type Document struct {
+	src  []byte
+	opts []UnmarshalOption
+
+	root   *V // internal value holding spans, never exposed
+	spans  map[*V]nodeSpan
+	indent string // detected indent unit, empty for single-line documents
+	colon  string // detected colon between keys and values, with spaces
+	space  string // detected spaces after commas in one line
+
+	value          *V // value for Value method, copied from root on demand
+	valuePositions *Positions
+}
+
+// UnmarshalDocument parses raw bytes into a Document. Options are used for
+// parsing the original text and the text after each edit, typically
+// UnmarshalOptJSONC() or UnmarshalOptJSON5() for configuration files.
+//
+// UnmarshalDocument 将原始字节数据解析为 Document。选项会用于解析原始文本以及每次编辑之后的文本, 对于
+// 配置文件, 通常是 UnmarshalOptJSONC() 或 UnmarshalOptJSON5()。
+func UnmarshalDocument(b []byte, opts ...UnmarshalOption) (*Document, error) {
+	d := &Document{
+		opts: opts,
+	}
+	if err := d.parse(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// UnmarshalDocumentString is equivalent to UnmarshalDocument([]byte(s), opts...).
+//
+// UnmarshalDocumentString 等效于 UnmarshalDocument([]byte(s), opts...)。
+func UnmarshalDocumentString(s string, opts ...UnmarshalOption) (*Document, error) {
+	return UnmarshalDocument([]byte(s), opts...)
+}
+
+// Value returns the parsed value of current text. It is re-generated after
+// each edit, therefore modifying it directly changes nothing in the text and is
+// lost after next edit.
+//
+// Value 返回当前文本解析得到的值。每次编辑之后都会重新生成该值, 因此直接对其进行修改不会改变文本,
+// 并且会在下一次编辑之后丢失。
+func (d *Document) Value() *V {
+	if d.value == nil && d.root != nil {
+		d.valuePositions = &Positions{src: d.src, spans: make(map[*V]nodeSpan, len(d.spans))}
+		d.value = d.copyValue(d.root)
+	}
+	return d.value
+}
+
+// Bytes returns current text of the Document.
+//
+// Bytes 返回 Document 当前的文本。
+func (d *Document) Bytes() []byte {
+	b := make([]byte, len(d.src))
+	copy(b, d.src)
+	return b
+}
+
+// String returns current text of the Document.
+//
+// String 返回 Document 当前的文本。
+func (d *Document) String() string {
+	return string(d.src)
+}
+
+// Set sets child at given path, just like v.Set(child).At(...). If the value
+// already exists, only its text is replaced. Otherwise, a new member is added
+// after the last member of the object, in the same style as its siblings.
+// Missing intermediate objects are created, while missing array elements are
+// not. Child could be *V or any type supported by Import.
+//
+// Set 在给定的路径上设置 child, 类似于 v.Set(child).At(...)。如果值已存在, 则仅替换其文本; 否则会
+// 按照与兄弟节点相同的风格, 在 object 的最后一个成员之后添加新成员。缺失的中间 object 会被自动创建,
+// 但缺失的数组元素不会。child 可以是 *V 或 Import 所支持的任意类型。
+func (d *Document) Set(child any, firstParam any, otherParams ...any) error {
+	c, err := documentChild(child)
+	if err != nil {
+		return err
+	}
+	path, err := documentPath(firstParam, otherParams)
+	if err != nil {
+		return err
+	}
+
+	cur := d.root
+	for i, param := range path {
+		next, err := getInCurrentValue(cur, false, param)
+		if err == nil {
+			cur = next
+			continue
+		}
+		if cur.valueType != Object {
+			return err
+		}
+
+		// create member path[i] in cur with remaining path
+		key, ok := param.(string)
+		if !ok {
+			return err
+		}
+		for j := len(path) - 1; j > i; j-- {
+			k, ok := path[j].(string)
+			if !ok {
+				return ErrNotFound
+			}
+			o := NewObject()
+			setToObjectChildren(o, k, c)
+			c = o
+		}
+		return d.apply(d.insertEdits(cur, key, c))
+	}
+
+	s := d.spans[cur]
+	return d.apply([]textEdit{{s.start, s.end, d.format(c, d.indentOf(s.start))}})
+}
+
+// Append appends child to the end of the array at given path. If no path is
+// given, current document should be an array.
+//
+// Append 将 child 追加到给定路径的 array 的末尾。如果未指定路径, 则当前文档本身应为 array。
+func (d *Document) Append(child any, params ...any) error {
+	c, err := documentChild(child)
+	if err != nil {
+		return err
+	}
+
+	arr := d.root
+	if len(params) > 0 {
+		if arr, err = get(d.root, false, params[0], params[1:]...); err != nil {
+			return err
+		}
+	}
+	if arr.valueType != Array {
+		return ErrNotArrayValue
+	}
+	return d.apply(d.insertEdits(arr, "", c))
+}
+
+// Delete deletes the value at given path, together with its separator and the
+// comments on its lines.
+//
+// Delete 删除给定路径上的值, 同时删除其分隔符以及所在行上的注释。
+func (d *Document) Delete(firstParam any, otherParams ...any) error {
+	path, err := documentPath(firstParam, otherParams)
+	if err != nil {
+		return err
+	}
+
+	parent := d.root
+	if len(path) > 1 {
+		if parent, err = get(d.root, false, path[0], path[1:len(path)-1]...); err != nil {
+			return err
+		}
+	}
+	target, err := getInCurrentValue(parent, false, path[len(path)-1])
+	if err != nil {
+		return err
+	}
+	return d.apply(d.deleteEdits(parent, target))
+}
+
+func documentChild(child any) (*V, error) {
+	if v, ok := child.(*V); ok {
+		if v == nil || v.valueType == NotExist {
+			return nil, ErrValueUninitialized
+		}
+		return v, nil
+	}
+	return Import(child)
+}
+
+func documentPath(firstParam any, otherParams []any) ([]any, error) {
+	if ok, params := isSliceAndExtractJointParams(firstParam); ok {
+		if len(otherParams) > 0 {
+			return nil, ErrMultipleParamNotSupportedWithIfSliceOrArrayGiven
+		}
+		if len(params) == 0 {
+			return nil, ErrParameterError
+		}
+		return params, nil
+	}
+	return append([]any{firstParam}, otherParams...), nil
+}
+
+// ---- parsing ----
+
+func (d *Document) parse(b []byte) error {
+	root, spans, err := d.unmarshal(b)
+	if err != nil {
+		return err
+	}
+
+	d.src, d.root, d.spans = b, root, spans
+	d.value, d.valuePositions = nil, nil
+	d.indent = d.detectIndent()
+	d.colon, d.space = d.detectSeparators()
+	return nil
+}
+
+// unmarshal parses b with options of the Document and records spans of all
+// values.
+func (d *Document) unmarshal(b []byte) (*V, map[*V]nodeSpan, error) {
+	opt := *combineUnmarshalOptions(d.opts)
+	opt.positions = &Positions{}
+
+	if len(b) == 0 {
+		return nil, nil, ErrNilParameter
+	}
+	if err := opt.checkSize(len(b)); err != nil {
+		return nil, nil, err
+	}
+	buf := make([]byte, len(b))
+	copy(buf, b)
+	v, err := unmarshalNoCopyWithOptions(buf, b, &opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, opt.positions.spans, nil
+}
+
+// copyValue deep copies v from root for Value method, together with spans of
+// all copied values, so that values and positions come from the same parsing.
+func (d *Document) copyValue(v *V) *V {
+	var c *V
+	switch v.valueType {
+	default:
+		c = v.deepCopy()
+	case Object:
+		c = newObject(globalPool{})
+		for k, child := range v.loadedChildren().object {
+			c.children.object[k] = childWithProperty{id: child.id, v: d.copyValue(child.v)}
+		}
+		c.children.incrID = v.children.incrID
+	case Array:
+		c = newArray(globalPool{})
+		arr := v.loadedChildren().arr
+		c.children.arr = make([]*V, 0, len(arr))
+		for _, child := range arr {
+			c.children.arr = append(c.children.arr, d.copyValue(child))
+		}
+	}
+	d.valuePositions.spans[c] = d.spans[v]
+	return c
+}
+
+// detectIndent returns indent unit of the first container whose members start
+// on new lines.
+func (d *Document) detectIndent() string {
+	found, foundAt := "", len(d.src)
+	for v, s := range d.spans {
+		if s.start >= foundAt {
+			continue
+		}
+		members := d.members(v)
+		if len(members) == 0 {
+			continue
+		}
+		first := memberStart(members[0])
+		if !d.startsLine(first) || d.lineStart(first) <= s.start {
+			continue
+		}
+		outer, inner := d.indentOf(s.start), d.indentOf(first)
+		if len(inner) > len(outer) && inner[:len(outer)] == outer {
+			found, foundAt = inner[len(outer):], s.start
+		}
+	}
+	return found
+}
+
+// detectSeparators returns the colon of the first object member, and spaces
+// after the first comma followed by a member in the same line. If no colon is
+// found, ":" is used for single-line documents and ": " for others. If no such
+// comma is found, a space follows commas only if the colon ends with a space.
+func (d *Document) detectSeparators() (colon, space string) {
+	colonAt, spaceAt := len(d.src), len(d.src)
+	for v := range d.spans {
+		members := d.members(v)
+		for i, m := range members {
+			if m.keyStart >= 0 && m.keyStart < colonAt {
+				if c := d.src[m.keyEnd:m.start]; isColonText(c) {
+					colon, colonAt = string(c), m.keyStart
+				}
+			}
+			start := memberStart(m)
+			if i == 0 || start >= spaceAt || d.startsLine(start) {
+				continue
+			}
+			j := start
+			for j > 0 && (d.src[j-1] == ' ' || d.src[j-1] == '\t') {
+				j--
+			}
+			if j > 0 && d.src[j-1] == ',' {
+				space, spaceAt = string(d.src[j:start]), start
+			}
+		}
+	}
+	if colonAt == len(d.src) {
+		colon = ":"
+		if d.indent != "" {
+			colon = ": "
+		}
+	}
+	if spaceAt == len(d.src) && colon[len(colon)-1] == ' ' {
+		space = " "
+	}
+	return colon, space
+}
+
+// members returns spans of children of v in source sequence.
+func (d *Document) members(v *V) []nodeSpan {
+	var res []nodeSpan
+	switch v.valueType {
+	case Array:
+		for _, c := range v.loadedChildren().arr {
+			res = append(res, d.spans[c])
+		}
+	case Object:
+		for _, c := range v.loadedChildren().object {
+			res = append(res, d.spans[c.v])
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].start < res[j].start })
+	}
+	return res
+}
+
+func memberStart(s nodeSpan) int {
+	if s.keyStart >= 0 {
+		return s.keyStart
+	}
+	return s.start
+}
+
+// ---- editing ----
+
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// apply applies non-overlapping edits, and then re-parses the text.
+func (d *Document) apply(edits []textEdit) error {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+
+	b := d.src
+	for _, e := range edits {
+		res := make([]byte, 0, len(b)-(e.end-e.start)+len(e.text))
+		res = append(res, b[:e.start]...)
+		res = append(res, e.text...)
+		res = append(res, b[e.end:]...)
+		b = res
+	}
+	return d.parse(b)
+}
+
+// insertEdits returns edits which add c to the end of container. For objects,
+// key is the key of c.
+func (d *Document) insertEdits(container *V, key string, c *V) []textEdit {
+	s := d.spans[container]
+	members := d.members(container)
+
+	member := func(prefix string, colon string) string {
+		text := d.format(c, prefix)
+		if container.valueType == Object {
+			text = d.format(NewString(key), "") + colon + text
+		}
+		return text
+	}
+
+	if len(members) == 0 {
+		prefix := d.indentOf(s.start)
+		if d.indent == "" {
+			return []textEdit{{s.start + 1, s.start + 1, member(prefix, d.colon)}}
+		}
+		text := "\n" + prefix + d.indent + member(prefix+d.indent, d.colon)
+		if inner := d.src[s.start+1 : s.end-1]; isBlankText(inner) {
+			return []textEdit{{s.start + 1, s.end - 1, text + "\n" + prefix}}
+		}
+		return []textEdit{{s.start + 1, s.start + 1, text}}
+	}
+
+	last := members[len(members)-1]
+	lastStart := memberStart(last)
+
+	lead, prefix := "", ""
+	if d.startsLine(lastStart) {
+		prefix = d.indentOf(lastStart)
+		lead = "\n" + prefix
+	}
+
+	colon := d.colon
+	if last.keyStart >= 0 {
+		if c := d.src[last.keyEnd:last.start]; isColonText(c) {
+			colon = string(c)
+		}
+	}
+
+	if lead == "" {
+		prefix = d.indentOf(lastStart)
+		if len(members) > 1 {
+			// the same spaces as before the last member
+			i := lastStart
+			for i > 0 && (d.src[i-1] == ' ' || d.src[i-1] == '\t') {
+				i--
+			}
+			lead = string(d.src[i:lastStart])
+		} else {
+			lead = d.space
+		}
+	}
+
+	// insert after the comma and comments on the same line of the last member
+	i := d.skipSpaces(last.end)
+	hasComma := i < len(d.src) && d.src[i] == ','
+	pos := last.end
+	if hasComma {
+		pos = i + 1
+	}
+	if j := d.skipLineTrivia(pos); d.atLineEnd(j) {
+		pos = j
+	}
+
+	text := lead + member(prefix, colon)
+	if hasComma {
+		return []textEdit{{pos, pos, text + ","}}
+	}
+	if pos == last.end {
+		return []textEdit{{pos, pos, "," + text}}
+	}
+	return []textEdit{{last.end, last.end, ","}, {pos, pos, text}}
+}
+
+// deleteEdits returns edits which remove target from parent.
+func (d *Document) deleteEdits(parent, target *V) []textEdit {
+	s := d.spans[target]
+	start := memberStart(s)
+	members := d.members(parent)
+
+	var prev *nodeSpan
+	for i := range members {
+		if members[i].start == s.start {
+			if i > 0 {
+				prev = &members[i-1]
+			}
+			break
+		}
+	}
+
+	i := d.skipSpaces(s.end)
+	hasComma := i < len(d.src) && d.src[i] == ','
+	afterComma := s.end
+	if hasComma {
+		afterComma = i + 1
+	}
+
+	// the member occupies whole lines
+	if j := d.skipLineTrivia(afterComma); d.startsLine(start) && d.atLineEnd(j) {
+		edits := []textEdit{{d.lineStart(start), d.nextLineStart(j), ""}}
+		if !hasComma && prev != nil {
+			if k := d.skipSpaces(prev.end); d.src[k] == ',' {
+				edits = append(edits, textEdit{k, k + 1, ""})
+			}
+		}
+		return edits
+	}
+
+	switch {
+	case hasComma:
+		return []textEdit{{start, d.skipSpaces(afterComma), ""}}
+	case prev != nil:
+		return []textEdit{{prev.end, s.end, ""}}
+	default:
+		return []textEdit{{start, s.end, ""}}
+	}
+}
+
+// format marshals v, where prefix is the indent of the line v starts at.
+func (d *Document) format(v *V, prefix string) string {
+	opts := []Option{OptUTF8(), OptEscapeHTML(false), OptEscapeSlash(false), OptSetSequence()}
+	if d.indent != "" {
+		opts = append(opts, OptIndent(prefix, d.indent))
+	}
+	s, err := v.MarshalString(opts...)
+	if err != nil {
+		return v.MustMarshalString()
+	}
+	return s
+}
+
+// ---- text helpers ----
+
+func (d *Document) lineStart(pos int) int {
+	for pos > 0 && d.src[pos-1] != '\n' && d.src[pos-1] != '\r' {
+		pos--
+	}
+	return pos
+}
+
+// nextLineStart returns position after the line ending at pos.
+func (d *Document) nextLineStart(pos int) int {
+	if pos < len(d.src) && d.src[pos] == '\r' {
+		pos++
+	}
+	if pos < len(d.src) && d.src[pos] == '\n' {
+		pos++
+	}
+	return pos
+}
+
+func (d *Document) indentOf(pos int) string {
+	start := d.lineStart(pos)
+	end := start
+	for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+		end++
+	}
+	return string(d.src[start:end])
+}
+
+// startsLine tells whether only spaces are before pos in its line.
+func (d *Document) startsLine(pos int) bool {
+	return pos-d.lineStart(pos) == len(d.indentOf(pos))
+}
+
+func (d *Document) skipSpaces(pos int) int {
+	for pos < len(d.src) && (d.src[pos] == ' ' || d.src[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+// skipLineTrivia skips spaces and comments in the same line.
+func (d *Document) skipLineTrivia(pos int) int {
+	for {
+		pos = d.skipSpaces(pos)
+		if pos+1 >= len(d.src) || d.src[pos] != '/' {
+			return pos
+		}
+		switch d.src[pos+1] {
+		case '/':
+			for pos < len(d.src) && d.src[pos] != '\n' && d.src[pos] != '\r' {
+				pos++
+			}
+			return pos
+		case '*':
+			next := skipComment(iter(d.src), pos, len(d.src))
+			if next == pos {
+				return pos
+			}
+			pos = next
+		default:
+			return pos
+		}
+	}
+}
+
+func (d *Document) atLineEnd(pos int) bool {
+	return pos >= len(d.src) || d.src[pos] == '\n' || d.src[pos] == '\r'
+}
+
+func isBlankText(b []byte) bool {
+	for _, c := range b {
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+// isColonText tells whether b is a colon with optional spaces in one line.
+func isColonText(b []byte) bool {
+	colon := 0
+	for _, c := range b {
+		switch c {
+		case ':':
+			colon++
+		case ' ', '\t':
+			// OK
+		default:
+			return false
+		}
+	}
+	return colon == 1
+}
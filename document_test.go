@@ -0,0 +1,300 @@
+package jsonvalue
+
+import (
+	"errors"
+	"testing"
+)
+
+func testDocument(t *testing.T) {
+	cv("parse", func() { testDocumentParse(t) })
+	cv("set existing value", func() { testDocumentSetExisting(t) })
+	cv("set new member", func() { testDocumentSetNew(t) })
+	cv("append", func() { testDocumentAppend(t) })
+	cv("delete", func() { testDocumentDelete(t) })
+	cv("single line", func() { testDocumentSingleLine(t) })
+	cv("errors", func() { testDocumentErrors(t) })
+}
+
+const testDocumentConfig = `// service config
+{
+    // listening
+    "host": "0.0.0.0",
+    "port": 8080, // default port
+
+    /* upstream list */
+    "upstreams": [
+        "a.example.com",
+        "b.example.com", // backup
+    ],
+    "log": {
+        "level": "info"
+    },
+}
+`
+
+func testDocumentParse(t *testing.T) {
+	_, err := UnmarshalDocumentString(testDocumentConfig)
+	so(err, isErr)
+
+	doc, err := UnmarshalDocumentString(testDocumentConfig, UnmarshalOptJSONC())
+	so(err, isNil)
+	so(doc.String(), eq, testDocumentConfig)
+	so(string(doc.Bytes()), eq, testDocumentConfig)
+	so(doc.Value().MustGet("port").Int(), eq, 8080)
+	so(doc.Value().MustGet("upstreams").Len(), eq, 2)
+	so(doc.indent, eq, "    ")
+}
+
+func testDocumentSetExisting(t *testing.T) {
+	doc, err := UnmarshalDocumentString(testDocumentConfig, UnmarshalOptJSONC())
+	so(err, isNil)
+
+	err = doc.Set(9090, "port")
+	so(err, isNil)
+	so(doc.Value().MustGet("port").Int(), eq, 9090)
+	so(doc.String(), eq, `// service config
+{
+    // listening
+    "host": "0.0.0.0",
+    "port": 9090, // default port
+
+    /* upstream list */
+    "upstreams": [
+        "a.example.com",
+        "b.example.com", // backup
+    ],
+    "log": {
+        "level": "info"
+    },
+}
+`)
+
+	err = doc.Set("c.example.com", "upstreams", 1)
+	so(err, isNil)
+	err = doc.Set(NewObject(map[string]any{"level": "debug"}), "log")
+	so(err, isNil)
+	so(doc.String(), eq, `// service config
+{
+    // listening
+    "host": "0.0.0.0",
+    "port": 9090, // default port
+
+    /* upstream list */
+    "upstreams": [
+        "a.example.com",
+        "c.example.com", // backup
+    ],
+    "log": {
+        "level": "debug"
+    },
+}
+`)
+
+	// modifying value does not change the text
+	doc.Value().MustSet(1).At("port")
+	so(doc.String(), hasSubStr, `"port": 9090,`)
+	so(doc.Set(9091, "port"), isNil)
+	so(doc.Value().MustGet("port").Int(), eq, 9091)
+}
+
+func testDocumentSetNew(t *testing.T) {
+	doc, err := UnmarshalDocumentString(testDocumentConfig, UnmarshalOptJSONC())
+	so(err, isNil)
+
+	err = doc.Set("/var/log/app.log", "log", "file")
+	so(err, isNil)
+	err = doc.Set(true, "tls", "enabled")
+	so(err, isNil)
+	so(doc.String(), eq, `// service config
+{
+    // listening
+    "host": "0.0.0.0",
+    "port": 8080, // default port
+
+    /* upstream list */
+    "upstreams": [
+        "a.example.com",
+        "b.example.com", // backup
+    ],
+    "log": {
+        "level": "info",
+        "file": "/var/log/app.log"
+    },
+    "tls": {
+        "enabled": true
+    },
+}
+`)
+	so(doc.Value().MustGet("tls", "enabled").Bool(), isTrue)
+
+	// after a member with trailing comment
+	doc, err = UnmarshalDocumentString("{\n  \"a\": 1, // one\n  \"b\": 2 // two\n}", UnmarshalOptJSONC())
+	so(err, isNil)
+	err = doc.Set("3", "c")
+	so(err, isNil)
+	so(doc.String(), eq, "{\n  \"a\": 1, // one\n  \"b\": 2, // two\n  \"c\": \"3\"\n}")
+
+	// into an empty object
+	err = doc.Set(map[string]any{}, "d")
+	so(err, isNil)
+	err = doc.Set(4, "d", "e")
+	so(err, isNil)
+	so(doc.String(), eq, "{\n  \"a\": 1, // one\n  \"b\": 2, // two\n  \"c\": \"3\",\n  \"d\": {\n    \"e\": 4\n  }\n}")
+
+	// JSON5 document with tab indent and trailing comma
+	doc, err = UnmarshalDocumentString("{\n\tname: 'demo', // c\n}", UnmarshalOptJSON5())
+	so(err, isNil)
+	so(doc.indent, eq, "\t")
+	so(doc.Set("prod", "name"), isNil)
+	so(doc.Set(1, "n"), isNil)
+	so(doc.String(), eq, "{\n\tname: \"prod\", // c\n\t\"n\": 1,\n}")
+}
+
+func testDocumentAppend(t *testing.T) {
+	doc, err := UnmarshalDocumentString(testDocumentConfig, UnmarshalOptJSONC())
+	so(err, isNil)
+
+	err = doc.Append("d.example.com", "upstreams")
+	so(err, isNil)
+	so(doc.String(), eq, `// service config
+{
+    // listening
+    "host": "0.0.0.0",
+    "port": 8080, // default port
+
+    /* upstream list */
+    "upstreams": [
+        "a.example.com",
+        "b.example.com", // backup
+        "d.example.com",
+    ],
+    "log": {
+        "level": "info"
+    },
+}
+`)
+	so(doc.Value().MustGet("upstreams").Len(), eq, 3)
+
+	doc, err = UnmarshalDocumentString("[1, 2]")
+	so(err, isNil)
+	so(doc.Append(3), isNil)
+	so(doc.String(), eq, "[1, 2, 3]")
+
+	doc, err = UnmarshalDocumentString("[]")
+	so(err, isNil)
+	so(doc.Append(NewObject(map[string]any{"a": 1})), isNil)
+	so(doc.String(), eq, `[{"a":1}]`)
+}
+
+func testDocumentDelete(t *testing.T) {
+	doc, err := UnmarshalDocumentString(testDocumentConfig, UnmarshalOptJSONC())
+	so(err, isNil)
+
+	so(doc.Delete("port"), isNil)
+	so(doc.Delete("upstreams", 1), isNil)
+	so(doc.Delete("log", "level"), isNil)
+	so(doc.String(), eq, `// service config
+{
+    // listening
+    "host": "0.0.0.0",
+
+    /* upstream list */
+    "upstreams": [
+        "a.example.com",
+    ],
+    "log": {
+    },
+}
+`)
+
+	// removing the last member also removes the comma before it
+	doc, err = UnmarshalDocumentString("{\n  \"a\": 1,\n  \"b\": 2\n}")
+	so(err, isNil)
+	so(doc.Delete("b"), isNil)
+	so(doc.String(), eq, "{\n  \"a\": 1\n}")
+}
+
+func testDocumentSingleLine(t *testing.T) {
+	doc, err := UnmarshalDocumentString(`{"a": 1, "b": [1, 2], "c": 3}`)
+	so(err, isNil)
+	so(doc.indent, eq, "")
+
+	so(doc.Delete("b"), isNil)
+	so(doc.String(), eq, `{"a": 1, "c": 3}`)
+	so(doc.Delete("c"), isNil)
+	so(doc.String(), eq, `{"a": 1}`)
+	so(doc.Set([]int{1, 2}, "list"), isNil)
+	so(doc.String(), eq, `{"a": 1, "list": [1,2]}`)
+	so(doc.Delete("a"), isNil)
+	so(doc.Delete("list"), isNil)
+	so(doc.String(), eq, `{}`)
+	so(doc.Set("x", "k"), isNil)
+	so(doc.String(), eq, `{"k":"x"}`)
+
+	doc, err = UnmarshalDocumentString(`{"a":1}`)
+	so(err, isNil)
+	so(doc.Set(2, []any{"b"}), isNil)
+	so(doc.String(), eq, `{"a":1,"b":2}`)
+
+	cv("arrays and objects share separator style", func() {
+		cases := []struct {
+			raw, expected string
+		}{
+			{`{"x":[]}`, `{"x":[1,2]}`},
+			{`{"x":{}}`, `{"x":{"a":1,"b":2}}`},
+			{`{"a": 1, "x": []}`, `{"a": 1, "x": [1, 2]}`},
+			{`{"x": {}}`, `{"x": {"a": 1, "b": 2}}`},
+			{`[[0, 0], []]`, `[[0, 0], [1, 2]]`},
+		}
+		for _, c := range cases {
+			doc, err := UnmarshalDocumentString(c.raw)
+			so(err, isNil)
+			if c.raw[0] == '[' {
+				so(doc.Append(1, 1), isNil)
+				so(doc.Append(2, 1), isNil)
+			} else if doc.Value().MustGet("x").ValueType() == Array {
+				so(doc.Append(1, "x"), isNil)
+				so(doc.Append(2, "x"), isNil)
+			} else {
+				so(doc.Set(1, "x", "a"), isNil)
+				so(doc.Set(2, "x", "b"), isNil)
+			}
+			so(doc.String(), eq, c.expected)
+		}
+
+		doc, err := UnmarshalDocumentString(`[]`)
+		so(err, isNil)
+		so(doc.Append(1), isNil)
+		so(doc.Append(2), isNil)
+		so(doc.String(), eq, `[1,2]`)
+	})
+}
+
+func testDocumentErrors(t *testing.T) {
+	_, err := UnmarshalDocument(nil)
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = UnmarshalDocumentString(`[1,2,3]`, UnmarshalOptMaxArrayLength(2))
+	so(errors.Is(err, ErrArrayLengthLimitExceeded), isTrue)
+
+	doc, err := UnmarshalDocumentString(`{"a":[1],"b":"c"}`)
+	so(err, isNil)
+
+	so(errors.Is(doc.Set(1, "a", 5), ErrOutOfRange), isTrue)
+	so(doc.Set(1, "b", "c"), isErr)
+	so(doc.Set(1, "x", 1), isErr)
+	so(doc.Set(nil, "x"), isNil)
+	so(errors.Is(doc.Set((*V)(nil), "x"), ErrValueUninitialized), isTrue)
+	so(doc.Set(make(chan int), "x"), isErr)
+	so(errors.Is(doc.Set(1, []any{}), ErrParameterError), isTrue)
+	so(errors.Is(doc.Set(1, []any{"a"}, "b"), ErrMultipleParamNotSupportedWithIfSliceOrArrayGiven), isTrue)
+
+	so(errors.Is(doc.Append(1, "b"), ErrNotArrayValue), isTrue)
+	so(errors.Is(doc.Append(1), ErrNotArrayValue), isTrue)
+	so(doc.Append(1, "not exist"), isErr)
+
+	so(errors.Is(doc.Delete("not exist"), ErrNotFound), isTrue)
+	so(doc.Delete("a", 3), isErr)
+	so(doc.Delete("b", "c"), isErr)
+
+	so(doc.String(), eq, `{"a":[1],"b":"c","x":null}`)
+}
@@ -0,0 +1,399 @@
+package jsonvalue
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// encoderFlushSize is the buffered size above which Encoder writes data to
+// the underlying writer.
+const encoderFlushSize = 4096
+
+// Encoder writes JSON values to an output stream incrementally, without
+// building the whole value tree in memory. Containers are written by Begin and
+// End methods, members of objects are written by Key followed by a value, for
+// example:
+//
+//	enc := jsonvalue.NewEncoder(w, jsonvalue.OptIndent("", "  "))
+//	enc.BeginArray()
+//	for _, row := range rows {
+//		enc.BeginObject()
+//		enc.Key("id")
+//		enc.Int64(row.ID)
+//		enc.Key("tags")
+//		enc.Value(row.Tags)
+//		enc.EndObject()
+//	}
+//	enc.EndArray()
+//
+// Marshaling options are the same as MarshalWrite. However, key sequence
+// options only take effect in values written by Value, as other object members
+// are always written in calling order. Each top-level value is followed by a
+// newline character.
+//
+// An error caused by a wrong call or an unsupported value could be ignored, as
+// nothing is written in this case. If the unsupported value is an object
+// member, the whole member including its key is dropped. But once writing to
+// the output stream fails, all further calls return that error.
+//
+// Encoder 向输出流中增量写入 JSON 值, 而不需要在内存中构建完整的值树。容器通过 Begin 和 End 方法写入,
+// object 的成员则通过 Key 以及紧随其后的值写入, 参见上文的例子。
+//
+// 序列化选项与 MarshalWrite 相同。不过键的顺序相关的选项仅对通过 Value 写入的值生效, 其他 object 成员
+// 总是按照调用顺序写入。每一个顶层的值后面都会跟随一个换行符。
+//
+// 由错误的调用或者不支持的值所导致的错误可以忽略, 因为此时不会写入任何数据。如果不支持的值是 object 的
+// 成员, 则包括键在内的整个成员都会被丢弃。但是一旦写入输出流失败, 后续的所有调用都会返回该错误。
+type Encoder struct {
+	w   io.Writer
+	opt *Opt
+	err error // sticky writing error
+
+	buf     encodeBuffer // data to be written to w
+	scratch encodeBuffer // marshaled value to be appended to buf
+
+	stack []encoderFrame // opened containers
+
+	val V      // reusable value for primitive types
+	num []byte // reusable number text
+}
+
+// encoderFrame describes an opened container in Encoder.
+type encoderFrame struct {
+	object bool
+	count  int // count of written members
+
+	hasKey bool
+	key    string
+}
+
+// encodeBuffer is a reusable io.Writer.
+type encodeBuffer []byte
+
+func (b *encodeBuffer) Write(d []byte) (int, error) {
+	*b = append(*b, d...)
+	return len(d), nil
+}
+
+// NewEncoder returns a new encoder that writes to w with given marshaling
+// options. Data is buffered and written to w after each top-level value is
+// completed, or when the buffer is large enough.
+//
+// NewEncoder 返回一个向 w 写入数据的编码器, 并使用给定的序列化选项。数据会被缓存, 并在每个顶层值写入完毕,
+// 或者缓存足够大时写入 w。
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	return &Encoder{
+		w:   w,
+		opt: combineOptions(opts),
+	}
+}
+
+// BeginObject starts an object.
+//
+// BeginObject 开始一个 object。
+func (e *Encoder) BeginObject() error {
+	return e.begin(true)
+}
+
+// EndObject ends current object.
+//
+// EndObject 结束当前的 object。
+func (e *Encoder) EndObject() error {
+	return e.end(true)
+}
+
+// BeginArray starts an array.
+//
+// BeginArray 开始一个 array。
+func (e *Encoder) BeginArray() error {
+	return e.begin(false)
+}
+
+// EndArray ends current array.
+//
+// EndArray 结束当前的 array。
+func (e *Encoder) EndArray() error {
+	return e.end(false)
+}
+
+// Key specifies the key of next member in current object.
+//
+// Key 指定当前 object 中下一个成员的键。
+func (e *Encoder) Key(k string) error {
+	if err := e.check(); err != nil {
+		return err
+	}
+	f := e.top()
+	if f == nil || !f.object {
+		return fmt.Errorf("%w, key %q is not in an object", ErrInvalidEncoderCall, k)
+	}
+	if f.hasKey {
+		return fmt.Errorf("%w, key %q is followed by another key %q", ErrInvalidEncoderCall, f.key, k)
+	}
+	f.hasKey, f.key = true, k
+	return nil
+}
+
+// Value writes a *V value.
+//
+// Value 写入一个 *V 值。
+func (e *Encoder) Value(v *V) error {
+	if v == nil || v.valueType == NotExist {
+		return ErrValueUninitialized
+	}
+	return e.encode(v)
+}
+
+// String writes a string value.
+//
+// String 写入一个字符串值。
+func (e *Encoder) String(s string) error {
+	e.val = V{valueType: String, valueStr: s}
+	return e.encode(&e.val)
+}
+
+// Bool writes a boolean value.
+//
+// Bool 写入一个布尔值。
+func (e *Encoder) Bool(b bool) error {
+	e.val = V{valueType: Boolean, valueBool: b}
+	return e.encode(&e.val)
+}
+
+// Null writes a null value.
+//
+// Null 写入一个 null 值。
+func (e *Encoder) Null() error {
+	e.val = V{valueType: Null}
+	return e.encode(&e.val)
+}
+
+// Int writes an int value.
+//
+// Int 写入一个 int 值。
+func (e *Encoder) Int(i int) error {
+	return e.Int64(int64(i))
+}
+
+// Int64 writes an int64 value.
+//
+// Int64 写入一个 int64 值。
+func (e *Encoder) Int64(i int64) error {
+	e.num = strconv.AppendInt(e.num[:0], i, 10)
+	e.val = V{valueType: Number, srcByte: e.num}
+	e.val.num.negative = i < 0
+	e.val.num.f64 = float64(i)
+	e.val.num.i64 = i
+	e.val.num.u64 = uint64(i)
+	return e.encode(&e.val)
+}
+
+// Uint64 writes an uint64 value.
+//
+// Uint64 写入一个 uint64 值。
+func (e *Encoder) Uint64(u uint64) error {
+	e.num = strconv.AppendUint(e.num[:0], u, 10)
+	e.val = V{valueType: Number, srcByte: e.num}
+	e.val.num.f64 = float64(u)
+	e.val.num.i64 = int64(u)
+	e.val.num.u64 = u
+	return e.encode(&e.val)
+}
+
+// Float64 writes a float64 value in the same format as NewFloat64. NaN, +Inf
+// and -Inf are handled by marshaling options.
+//
+// Float64 按照与 NewFloat64 相同的格式写入一个 float64 值。NaN, +Inf 和 -Inf 按照序列化选项处理。
+func (e *Encoder) Float64(f float64) error {
+	e.val = V{valueType: Number}
+	e.val.num.negative = f < 0
+	e.val.num.f64 = f
+	e.val.num.i64 = int64(f)
+	e.val.num.u64 = uint64(f)
+	if isValidFloat(f) {
+		format := byte('f')
+		if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+			format = 'e'
+		}
+		e.num = strconv.AppendFloat(e.num[:0], f, format, -1, 64)
+		e.val.srcByte = e.num
+	}
+	return e.encode(&e.val)
+}
+
+// Flush writes all buffered data to the underlying writer.
+//
+// Flush 将所有缓存的数据写入底层的 writer。
+func (e *Encoder) Flush() error {
+	if err := e.check(); err != nil {
+		return err
+	}
+	if len(e.buf) == 0 {
+		return nil
+	}
+	_, err := e.w.Write(e.buf)
+	e.buf = e.buf[:0]
+	if err != nil {
+		e.err = err
+	}
+	return err
+}
+
+func (e *Encoder) check() error {
+	if e.w == nil {
+		return ErrNilParameter
+	}
+	return e.err
+}
+
+func (e *Encoder) top() *encoderFrame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	return &e.stack[len(e.stack)-1]
+}
+
+// checkValuePosition checks whether a value could be written now.
+func (e *Encoder) checkValuePosition() error {
+	if err := e.check(); err != nil {
+		return err
+	}
+	if f := e.top(); f != nil && f.object && !f.hasKey {
+		return fmt.Errorf("%w, value in object without key", ErrInvalidEncoderCall)
+	}
+	return nil
+}
+
+// pushPath pushes key or index of next value for number formatting options.
+func (e *Encoder) pushPath() {
+	f := e.top()
+	if f == nil || !e.opt.numberFormat.trackPath {
+		return
+	}
+	if f.object {
+		e.opt.numberFormat.pushPath(f.key)
+	} else {
+		e.opt.numberFormat.pushPath(strconv.Itoa(f.count))
+	}
+}
+
+func (e *Encoder) popPath() {
+	if e.top() != nil {
+		e.opt.numberFormat.popPath()
+	}
+}
+
+// writePrefix writes separator, indent and key before next value.
+func (e *Encoder) writePrefix() {
+	f := e.top()
+	if f == nil {
+		return
+	}
+	if f.count > 0 {
+		_, _ = e.buf.Write([]byte{','})
+	}
+	if e.opt.indent.enabled {
+		_, _ = e.buf.Write([]byte{'\n'})
+		e.opt.indent.cnt = len(e.stack)
+		writeIndent(&e.buf, e.opt)
+	}
+	if f.object {
+		_, _ = e.buf.Write([]byte{'"'})
+		escapeStringToBuff(f.key, &e.buf, e.opt)
+		if e.opt.indent.enabled {
+			_, _ = e.buf.Write([]byte{'"', ':', ' '})
+		} else {
+			_, _ = e.buf.Write([]byte{'"', ':'})
+		}
+		f.hasKey, f.key = false, ""
+	}
+	f.count++
+}
+
+// valueDone is invoked after a value is written completely.
+func (e *Encoder) valueDone() error {
+	if len(e.stack) == 0 {
+		_, _ = e.buf.Write([]byte{'\n'})
+		return e.Flush()
+	}
+	if len(e.buf) >= encoderFlushSize {
+		return e.Flush()
+	}
+	return nil
+}
+
+func (e *Encoder) encode(v *V) error {
+	if err := e.checkValuePosition(); err != nil {
+		return err
+	}
+	if f := e.top(); f != nil && f.object && v.IsNull() && e.opt.OmitNull {
+		f.hasKey, f.key = false, ""
+		return nil
+	}
+
+	// The value is marshaled into scratch buffer first, so that nothing
+	// would be written if error occurs.
+	e.pushPath()
+	e.opt.indent.cnt = len(e.stack)
+	e.scratch = e.scratch[:0]
+	err := marshalToBuffer(v, nil, &e.scratch, e.opt)
+	e.popPath()
+	if err != nil {
+		// the member is dropped, so that next member could be written
+		if f := e.top(); f != nil && f.object {
+			f.hasKey, f.key = false, ""
+		}
+		return err
+	}
+
+	e.writePrefix()
+	_, _ = e.buf.Write(e.scratch)
+	return e.valueDone()
+}
+
+func (e *Encoder) begin(object bool) error {
+	if err := e.checkValuePosition(); err != nil {
+		return err
+	}
+	e.pushPath()
+	e.writePrefix()
+	if object {
+		_, _ = e.buf.Write([]byte{'{'})
+	} else {
+		_, _ = e.buf.Write([]byte{'['})
+	}
+	e.stack = append(e.stack, encoderFrame{object: object})
+	return nil
+}
+
+func (e *Encoder) end(object bool) error {
+	if err := e.check(); err != nil {
+		return err
+	}
+	f := e.top()
+	if f == nil || f.object != object {
+		if object {
+			return fmt.Errorf("%w, no object to end", ErrInvalidEncoderCall)
+		}
+		return fmt.Errorf("%w, no array to end", ErrInvalidEncoderCall)
+	}
+	if f.hasKey {
+		return fmt.Errorf("%w, key %q without value", ErrInvalidEncoderCall, f.key)
+	}
+
+	e.stack = e.stack[:len(e.stack)-1]
+	if f.count > 0 && e.opt.indent.enabled {
+		_, _ = e.buf.Write([]byte{'\n'})
+		e.opt.indent.cnt = len(e.stack)
+		writeIndent(&e.buf, e.opt)
+	}
+	if object {
+		_, _ = e.buf.Write([]byte{'}'})
+	} else {
+		_, _ = e.buf.Write([]byte{']'})
+	}
+	e.popPath()
+	return e.valueDone()
+}
@@ -0,0 +1,312 @@
+package jsonvalue
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func testEncoder(t *testing.T) {
+	cv("general encoding", func() { testEncoderGeneral(t) })
+	cv("same as marshal", func() { testEncoderSameAsMarshal(t) })
+	cv("options", func() { testEncoderOptions(t) })
+	cv("flushing", func() { testEncoderFlush(t) })
+	cv("encoding errors", func() { testEncoderErrors(t) })
+}
+
+// encodeStream writes v by Encoder, containers are written member by member.
+func encodeStream(enc *Encoder, v *V) {
+	switch v.ValueType() {
+	default:
+		so(enc.Value(v), isNil)
+	case Object:
+		so(enc.BeginObject(), isNil)
+		v.RangeObjectsBySetSequence(func(k string, child *V) bool {
+			so(enc.Key(k), isNil)
+			encodeStream(enc, child)
+			return true
+		})
+		so(enc.EndObject(), isNil)
+	case Array:
+		so(enc.BeginArray(), isNil)
+		v.RangeArray(func(_ int, child *V) bool {
+			encodeStream(enc, child)
+			return true
+		})
+		so(enc.EndArray(), isNil)
+	}
+}
+
+func testEncoderGeneral(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := NewEncoder(buf, OptEscapeSlash(false), OptUTF8())
+
+	so(enc.BeginObject(), isNil)
+	so(enc.Key("str"), isNil)
+	so(enc.String("a/b\n中"), isNil)
+	so(enc.Key("int"), isNil)
+	so(enc.Int(-1), isNil)
+	so(enc.Key("int64"), isNil)
+	so(enc.Int64(math.MinInt64), isNil)
+	so(enc.Key("uint64"), isNil)
+	so(enc.Uint64(math.MaxUint64), isNil)
+	so(enc.Key("float"), isNil)
+	so(enc.Float64(1.5), isNil)
+	so(enc.Key("small"), isNil)
+	so(enc.Float64(1e-7), isNil)
+	so(enc.Key("bool"), isNil)
+	so(enc.Bool(true), isNil)
+	so(enc.Key("null"), isNil)
+	so(enc.Null(), isNil)
+	so(enc.Key("value"), isNil)
+	so(enc.Value(MustUnmarshalString(`[1,{}]`)), isNil)
+	so(enc.Key("arr"), isNil)
+	so(enc.BeginArray(), isNil)
+	so(enc.BeginObject(), isNil)
+	so(enc.EndObject(), isNil)
+	so(enc.BeginArray(), isNil)
+	so(enc.EndArray(), isNil)
+	so(enc.Bool(false), isNil)
+	so(enc.EndArray(), isNil)
+	so(buf.Len(), eq, 0) // not flushed yet
+	so(enc.EndObject(), isNil)
+
+	so(buf.String(), eq, `{"str":"a/b\n中","int":-1,"int64":-9223372036854775808,`+
+		`"uint64":18446744073709551615,"float":1.5,"small":1e-07,"bool":true,"null":null,`+
+		`"value":[1,{}],"arr":[{},[],false]}`+"\n")
+
+	// multiple top-level values
+	buf.Reset()
+	so(enc.Int(1), isNil)
+	so(enc.String("s"), isNil)
+	so(enc.BeginArray(), isNil)
+	so(enc.EndArray(), isNil)
+	so(buf.String(), eq, "1\n\"s\"\n[]\n")
+
+	values, err := decodeAll(NewDecoder(buf))
+	so(err, isNil)
+	so(len(values), eq, 3)
+}
+
+func testEncoderSameAsMarshal(t *testing.T) {
+	raw := `{"a":[1,-2.50,3e2,{"b":"c\"<&>/"}],"d":true,"e":null,"f":{},"g":[],` +
+		`"h":{"i":[[null,{"j":9007199254740993}]],"k":"中文"},"l":0.1234}`
+	v := MustUnmarshalString(raw)
+
+	optsList := [][]Option{
+		{OptSetSequence()},
+		{OptSetSequence(), OptIndent("", "  ")},
+		{OptSetSequence(), OptIndent(">", "\t"), OptOmitNull(true)},
+		{OptSetSequence(), OptEscapeHTML(true), OptEscapeSlash(true)},
+		{OptSetSequence(), OptUTF8(), OptEscapeHTML(false), OptEscapeSlash(false)},
+		{OptSetSequence(), OptNumberShortest(), OptNumberLargeIntAsString()},
+		{OptSetSequence(), OptNumberDecimalPlaces(1, "/a/*", "/l")},
+	}
+
+	for _, opts := range optsList {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf, opts...)
+		encodeStream(enc, v)
+
+		expected := v.MustMarshalString(opts...) + "\n"
+		so(buf.String(), eq, expected)
+
+		// the whole value written at once
+		buf.Reset()
+		so(enc.Value(v), isNil)
+		so(buf.String(), eq, expected)
+	}
+}
+
+func testEncoderOptions(t *testing.T) {
+	cv("omit null", func() {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf, OptOmitNull(true))
+		so(enc.BeginObject(), isNil)
+		so(enc.Key("a"), isNil)
+		so(enc.Null(), isNil)
+		so(enc.Key("b"), isNil)
+		so(enc.BeginArray(), isNil)
+		so(enc.Null(), isNil)
+		so(enc.EndArray(), isNil)
+		so(enc.Key("c"), isNil)
+		so(enc.Value(NewNull()), isNil)
+		so(enc.EndObject(), isNil)
+		so(buf.String(), eq, "{\"b\":[null]}\n")
+
+		buf.Reset()
+		so(enc.BeginObject(), isNil)
+		so(enc.Key("a"), isNil)
+		so(enc.Null(), isNil)
+		so(enc.EndObject(), isNil)
+		so(buf.String(), eq, "{}\n")
+	})
+
+	cv("indent", func() {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf, OptIndent("", "  "))
+		so(enc.BeginArray(), isNil)
+		so(enc.Int(1), isNil)
+		so(enc.BeginObject(), isNil)
+		so(enc.Key("a"), isNil)
+		so(enc.Value(NewArray()), isNil)
+		so(enc.EndObject(), isNil)
+		so(enc.EndArray(), isNil)
+		so(buf.String(), eq, "[\n  1,\n  {\n    \"a\": []\n  }\n]\n")
+	})
+
+	cv("NaN and Inf", func() {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf, OptFloatNaNToNull(), OptFloatInfToString("+∞", "-∞"), OptUTF8())
+		so(enc.BeginArray(), isNil)
+		so(enc.Float64(math.NaN()), isNil)
+		so(enc.Float64(math.Inf(1)), isNil)
+		so(enc.Float64(math.Inf(-1)), isNil)
+		so(enc.EndArray(), isNil)
+		so(buf.String(), eq, "[null,\"+∞\",\"-∞\"]\n")
+
+		buf.Reset()
+		enc = NewEncoder(buf)
+		so(enc.BeginArray(), isNil)
+		err := enc.Float64(math.NaN())
+		so(errors.Is(err, ErrUnsupportedFloat), isTrue)
+		err = enc.Float64(math.Inf(-1))
+		so(errors.Is(err, ErrUnsupportedFloat), isTrue)
+		so(enc.Float64(1), isNil)
+		so(enc.EndArray(), isNil)
+		so(buf.String(), eq, "[1]\n")
+
+		// the member with unsupported value is dropped
+		buf.Reset()
+		so(enc.BeginObject(), isNil)
+		so(enc.Key("f"), isNil)
+		err = enc.Float64(math.NaN())
+		so(errors.Is(err, ErrUnsupportedFloat), isTrue)
+		so(enc.Key("g"), isNil)
+		so(enc.Int(1), isNil)
+		so(enc.Key("h"), isNil)
+		err = enc.Value(NewFloat64(math.Inf(1)))
+		so(errors.Is(err, ErrUnsupportedFloat), isTrue)
+		so(enc.EndObject(), isNil)
+		so(buf.String(), eq, "{\"g\":1}\n")
+	})
+
+	cv("key sequence in values", func() {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf, OptDefaultStringSequence())
+		so(enc.BeginObject(), isNil)
+		so(enc.Key("z"), isNil)
+		so(enc.Value(MustUnmarshalString(`{"c":1,"b":2,"a":3}`)), isNil)
+		so(enc.Key("y"), isNil)
+		so(enc.Int(0), isNil)
+		so(enc.EndObject(), isNil)
+		so(buf.String(), eq, "{\"z\":{\"a\":3,\"b\":2,\"c\":1},\"y\":0}\n")
+	})
+
+	cv("default options", func() {
+		SetDefaultMarshalOptions(OptEscapeSlash(false))
+		defer ResetDefaultMarshalOptions()
+
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf, OptUTF8())
+		so(enc.String("/中"), isNil)
+		so(buf.String(), eq, "\"/中\"\n")
+	})
+}
+
+func testEncoderFlush(t *testing.T) {
+	cv("large array", func() {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf)
+		so(enc.BeginArray(), isNil)
+
+		n := 0
+		for i := 0; buf.Len() == 0; i++ {
+			so(enc.String(strings.Repeat("x", 100)), isNil)
+			n++
+		}
+		so(buf.Len() >= encoderFlushSize, isTrue)
+
+		so(enc.EndArray(), isNil)
+		v, err := UnmarshalString(buf.String())
+		so(err, isNil)
+		so(v.Len(), eq, n)
+	})
+
+	cv("explicit flush", func() {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf)
+		so(enc.BeginArray(), isNil)
+		so(enc.Int(1), isNil)
+		so(enc.Flush(), isNil)
+		so(buf.String(), eq, "[1")
+		so(enc.Flush(), isNil)
+		so(enc.EndArray(), isNil)
+		so(buf.String(), eq, "[1]\n")
+	})
+
+	cv("writing error", func() {
+		enc := NewEncoder(iotest.TruncateWriter(&bytes.Buffer{}, 0))
+		so(enc.Int(1), isNil) // TruncateWriter never fails
+
+		errW := errors.New("writing error")
+		enc = NewEncoder(errorWriter{errW})
+		so(enc.BeginArray(), isNil)
+		so(enc.Int(1), isNil)
+		so(errors.Is(enc.EndArray(), errW), isTrue)
+		so(errors.Is(enc.Int(1), errW), isTrue)
+		so(errors.Is(enc.Key("a"), errW), isTrue)
+		so(errors.Is(enc.BeginObject(), errW), isTrue)
+		so(errors.Is(enc.Flush(), errW), isTrue)
+	})
+}
+
+type errorWriter struct {
+	err error
+}
+
+func (w errorWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func testEncoderErrors(t *testing.T) {
+	cv("nil writer", func() {
+		enc := NewEncoder(nil)
+		so(errors.Is(enc.BeginObject(), ErrNilParameter), isTrue)
+		so(errors.Is(enc.Int(1), ErrNilParameter), isTrue)
+		so(errors.Is(enc.Flush(), ErrNilParameter), isTrue)
+	})
+
+	cv("nil value", func() {
+		enc := NewEncoder(&bytes.Buffer{})
+		so(errors.Is(enc.Value(nil), ErrValueUninitialized), isTrue)
+		so(errors.Is(enc.Value(&V{}), ErrValueUninitialized), isTrue)
+	})
+
+	cv("wrong calls", func() {
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf)
+		so(errors.Is(enc.Key("a"), ErrInvalidEncoderCall), isTrue)
+		so(errors.Is(enc.EndObject(), ErrInvalidEncoderCall), isTrue)
+		so(errors.Is(enc.EndArray(), ErrInvalidEncoderCall), isTrue)
+
+		so(enc.BeginObject(), isNil)
+		so(errors.Is(enc.Int(1), ErrInvalidEncoderCall), isTrue)
+		so(errors.Is(enc.BeginArray(), ErrInvalidEncoderCall), isTrue)
+		so(errors.Is(enc.EndArray(), ErrInvalidEncoderCall), isTrue)
+		so(enc.Key("a"), isNil)
+		so(errors.Is(enc.Key("b"), ErrInvalidEncoderCall), isTrue)
+		so(errors.Is(enc.EndObject(), ErrInvalidEncoderCall), isTrue)
+		so(enc.BeginArray(), isNil)
+		so(errors.Is(enc.Key("c"), ErrInvalidEncoderCall), isTrue)
+		so(errors.Is(enc.EndObject(), ErrInvalidEncoderCall), isTrue)
+		so(enc.EndArray(), isNil)
+		so(enc.EndObject(), isNil)
+
+		// wrong calls write nothing
+		so(buf.String(), eq, "{\"a\":[]}\n")
+	})
+}
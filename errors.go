@@ -1,5 +1,11 @@
 package jsonvalue
 
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
 // Error is equivalent to string and used to create some error constants in this package.
 // Error constants: http://godoc.org/github.com/Andrew-M-C/go.jsonvalue/#pkg-constants
 type Error string
@@ -96,4 +102,189 @@ const (
 	//
 	// ErrParameterError 表示各种参数错误
 	ErrParameterError = Error("parameter error")
+
+	// ErrLineTooLong indicates that a line read by LinesReader is longer than
+	// its MaxLineSize.
+	//
+	// ErrLineTooLong 表示 LinesReader 读取的行超出了其 MaxLineSize 限制
+	ErrLineTooLong = Error("line too long")
+
+	// ErrInvalidPointer indicates that given JSON Pointer (RFC 6901) is illegal.
+	//
+	// ErrInvalidPointer 表示给定的 JSON Pointer (RFC 6901) 不合法
+	ErrInvalidPointer = Error("invalid JSON pointer")
+
+	// ErrInvalidJSONPath indicates that given JSONPath (RFC 9535) expression is illegal.
+	//
+	// ErrInvalidJSONPath 表示给定的 JSONPath (RFC 9535) 表达式不合法
+	ErrInvalidJSONPath = Error("invalid JSONPath expression")
+
+	// ErrInvalidPatch indicates that given JSON Patch (RFC 6902) document is illegal.
+	//
+	// ErrInvalidPatch 表示给定的 JSON Patch (RFC 6902) 文档不合法
+	ErrInvalidPatch = Error("invalid JSON patch")
+
+	// ErrPatchTestFailed indicates that a "test" operation in JSON Patch failed.
+	//
+	// ErrPatchTestFailed 表示 JSON Patch 中的 "test" 操作未通过
+	ErrPatchTestFailed = Error("JSON patch test failed")
+
+	// ErrDuplicateKey indicates that an object contains duplicated keys in strict
+	// parsing.
+	//
+	// ErrDuplicateKey 表示在严格解析模式下, object 中包含重复的键
+	ErrDuplicateKey = Error("duplicate object key")
+
+	// ErrDepthLimitExceeded indicates that nesting of arrays and objects is
+	// deeper than the limit specified by UnmarshalOptMaxDepth.
+	//
+	// ErrDepthLimitExceeded 表示 array 和 object 的嵌套深度超出了 UnmarshalOptMaxDepth 指定的限制
+	ErrDepthLimitExceeded = Error("nesting depth limit exceeded")
+
+	// ErrSizeLimitExceeded indicates that raw text is larger than the limit
+	// specified by UnmarshalOptMaxBytes.
+	//
+	// ErrSizeLimitExceeded 表示原始文本的大小超出了 UnmarshalOptMaxBytes 指定的限制
+	ErrSizeLimitExceeded = Error("input size limit exceeded")
+
+	// ErrStringLengthLimitExceeded indicates that a string or an object key is
+	// longer than the limit specified by UnmarshalOptMaxStringLength.
+	//
+	// ErrStringLengthLimitExceeded 表示字符串或 object 的键的长度超出了
+	// UnmarshalOptMaxStringLength 指定的限制
+	ErrStringLengthLimitExceeded = Error("string length limit exceeded")
+
+	// ErrObjectKeysLimitExceeded indicates that an object has more keys than the
+	// limit specified by UnmarshalOptMaxObjectKeys.
+	//
+	// ErrObjectKeysLimitExceeded 表示 object 的键数量超出了 UnmarshalOptMaxObjectKeys 指定的限制
+	ErrObjectKeysLimitExceeded = Error("object keys limit exceeded")
+
+	// ErrArrayLengthLimitExceeded indicates that an array has more elements than
+	// the limit specified by UnmarshalOptMaxArrayLength.
+	//
+	// ErrArrayLengthLimitExceeded 表示 array 的元素数量超出了 UnmarshalOptMaxArrayLength
+	// 指定的限制
+	ErrArrayLengthLimitExceeded = Error("array length limit exceeded")
+
+	// ErrNodeLimitExceeded indicates that total count of values is more than the
+	// limit specified by UnmarshalOptMaxNodes.
+	//
+	// ErrNodeLimitExceeded 表示值的总数超出了 UnmarshalOptMaxNodes 指定的限制
+	ErrNodeLimitExceeded = Error("node count limit exceeded")
+
+	// ErrInvalidEncoderCall indicates that an Encoder method is called in a
+	// wrong state, such as writing a value in an object without a key.
+	//
+	// ErrInvalidEncoderCall 表示在错误的状态下调用了 Encoder 的方法, 比如在 object 中写入值时
+	// 没有指定键
+	ErrInvalidEncoderCall = Error("invalid encoder call")
 )
+
+// SyntaxError describes where and why a raw JSON text could not be parsed. It
+// wraps one of the Error constants above, therefore errors.Is could still be
+// used to check error category, such as errors.Is(err, ErrNotObjectValue).
+//
+// SyntaxError 描述了 JSON 文本在什么位置、因为什么原因解析失败。它包装了上文中的某一个 Error
+// 常量, 因此依然可以使用 errors.Is 判断错误类别, 比如 errors.Is(err, ErrNotObjectValue)。
+type SyntaxError struct {
+	// Offset is the byte offset where error occurs, starting from 0.
+	//
+	// Offset 表示错误发生的字节偏移量, 从 0 开始。
+	Offset int
+	// Line is the line number where error occurs, starting from 1.
+	//
+	// Line 表示错误发生的行号, 从 1 开始。
+	Line int
+	// Column is the column number where error occurs, counted in characters and
+	// starting from 1.
+	//
+	// Column 表示错误发生的列号, 以字符为单位, 从 1 开始。
+	Column int
+	// Excerpt is a short excerpt of the input text around the error position.
+	//
+	// Excerpt 表示错误位置附近的一小段输入文本。
+	Excerpt string
+	// Expected describes the expected token, such as "':'" or "value". It may
+	// be empty if it is not clear.
+	//
+	// Expected 描述期望的符号, 比如 "':'" 或 "value"。如果无法明确, 则为空。
+	Expected string
+	// Msg is the description of the error.
+	//
+	// Msg 是错误描述。
+	Msg string
+
+	err Error
+}
+
+// Error implements error interface.
+func (e *SyntaxError) Error() string {
+	buf := bytes.Buffer{}
+	buf.WriteString(e.err.Error())
+	buf.WriteString(", ")
+	buf.WriteString(e.Msg)
+
+	if e.Line > 0 {
+		fmt.Fprintf(&buf, " at line %d, column %d (offset %d)", e.Line, e.Column, e.Offset)
+	} else {
+		fmt.Fprintf(&buf, " at Position %d", e.Offset)
+	}
+	if e.Expected != "" {
+		buf.WriteString(", expecting ")
+		buf.WriteString(e.Expected)
+	}
+	if e.Excerpt != "" {
+		fmt.Fprintf(&buf, ", near %q", e.Excerpt)
+	}
+	return buf.String()
+}
+
+// Unwrap returns the underlying Error constant.
+func (e *SyntaxError) Unwrap() error {
+	return e.err
+}
+
+func newSyntaxError(err Error, offset int, expected string, f string, a ...any) error {
+	return &SyntaxError{
+		Offset:   offset,
+		Expected: expected,
+		Msg:      fmt.Sprintf(f, a...),
+		err:      err,
+	}
+}
+
+const syntaxErrorExcerptRadius = 16
+
+// locateSyntaxError fills line, column and excerpt of a *SyntaxError with the
+// source text.
+func locateSyntaxError(err error, src []byte) error {
+	se, ok := err.(*SyntaxError)
+	if !ok {
+		return err
+	}
+	offset := se.Offset
+	if offset < 0 || offset > len(src) {
+		return err
+	}
+
+	lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+	se.Line = bytes.Count(src[:lineStart], []byte{'\n'}) + 1
+	se.Column = utf8.RuneCount(src[lineStart:offset]) + 1
+
+	from, to := offset-syntaxErrorExcerptRadius, offset+syntaxErrorExcerptRadius
+	if from < 0 {
+		from = 0
+	}
+	if to > len(src) {
+		to = len(src)
+	}
+	for from > 0 && !utf8.RuneStart(src[from]) {
+		from--
+	}
+	for to < len(src) && !utf8.RuneStart(src[to]) {
+		to++
+	}
+	se.Excerpt = string(src[from:to])
+	return se
+}
@@ -0,0 +1,140 @@
+package jsonvalue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testSyntaxError(t *testing.T) {
+	cv("offset, line and column", func() { testSyntaxErrorPosition(t) })
+	cv("errors.Is with Error constants", func() { testSyntaxErrorIs(t) })
+	cv("excerpt", func() { testSyntaxErrorExcerpt(t) })
+}
+
+func getSyntaxError(err error) *SyntaxError {
+	se := &SyntaxError{}
+	so(errors.As(err, &se), isTrue)
+	return se
+}
+
+func testSyntaxErrorPosition(t *testing.T) {
+	cv("single line", func() {
+		_, err := UnmarshalString(`{"a":1,"b" 2}`)
+		so(err, isErr)
+		t.Log(err)
+
+		se := getSyntaxError(err)
+		so(se.Offset, eq, 11)
+		so(se.Line, eq, 1)
+		so(se.Column, eq, 12)
+		so(se.Expected, eq, "':'")
+		so(err.Error(), hasSubStr, "line 1, column 12")
+	})
+
+	cv("multiple lines", func() {
+		raw := "{\n  \"a\": 1,\n  \"中文\": tru\n}"
+		_, err := Unmarshal([]byte(raw))
+		so(err, isErr)
+		t.Log(err)
+
+		se := getSyntaxError(err)
+		so(se.Offset, eq, strings.Index(raw, "tru"))
+		so(se.Line, eq, 3)
+		so(se.Column, eq, 9)
+		so(se.Expected, eq, "'true'")
+	})
+
+	cv("line is not affected by escaped characters", func() {
+		raw := "[\"a\\nb\\nc\",\n\"\\uD800\"]"
+		_, err := UnmarshalString(raw)
+		so(err, isErr)
+		t.Log(err)
+
+		se := getSyntaxError(err)
+		so(se.Line, eq, 2)
+		so(se.Column, eq, 2)
+	})
+
+	cv("no copy", func() {
+		_, err := UnmarshalNoCopy([]byte("[1,\n2,\n-]"))
+		so(err, isErr)
+		se := getSyntaxError(err)
+		so(se.Line, eq, 3)
+		so(se.Column, eq, 1)
+	})
+
+	cv("decoder", func() {
+		dec := NewDecoder(strings.NewReader("{\"a\":1}\n[1,\n\"2\" }"))
+		_, err := dec.Decode()
+		so(err, isNil)
+		_, err = dec.Decode()
+		so(err, isErr)
+		t.Log(err)
+
+		se := getSyntaxError(err)
+		so(se.Line, eq, 2)
+		so(se.Column, eq, 5)
+	})
+}
+
+func testSyntaxErrorIs(t *testing.T) {
+	cases := []struct {
+		raw string
+		err Error
+	}{
+		{`{"a" 1}`, ErrNotObjectValue},
+		{`{"a":1`, ErrNotObjectValue},
+		{`[1,2`, ErrNotArrayValue},
+		{`[1,#]`, ErrRawBytesUnrecognized},
+		{`{} 1`, ErrRawBytesUnrecognized},
+		{`"\x"`, ErrIllegalString},
+		{`"abc`, ErrIllegalString},
+		{`[tru]`, ErrNotValidBoolValue},
+		{`[fals]`, ErrNotValidBoolValue},
+		{`[nul]`, ErrNotValidNullValue},
+		{`[01]`, ErrNotValidNumberValue},
+		{`[1.]`, ErrNotValidNumberValue},
+	}
+
+	for _, c := range cases {
+		_, err := UnmarshalString(c.raw)
+		so(err, isErr)
+		t.Logf("%s - %v", c.raw, err)
+		so(errors.Is(err, c.err), isTrue)
+
+		se := getSyntaxError(err)
+		so(se.Line, eq, 1)
+		so(se.Column, eq, se.Offset+1)
+	}
+}
+
+func testSyntaxErrorExcerpt(t *testing.T) {
+	raw := `{"message":"你好，世界","list":[1,2,3,4,5,6,7,8,9,10],"error":nulll}`
+	_, err := UnmarshalString(raw)
+	so(err, isErr)
+	t.Log(err)
+
+	se := getSyntaxError(err)
+	so(strings.Contains(raw, se.Excerpt), isTrue)
+	so(se.Excerpt, hasSubStr, "nulll")
+	so(err.Error(), hasSubStr, "near")
+
+	// excerpt should be cut at UTF-8 boundary
+	raw = `["你好，世界你好，世界你好，世界", tru]`
+	_, err = UnmarshalString(raw)
+	so(err, isErr)
+	se = getSyntaxError(err)
+	so(strings.Contains(raw, se.Excerpt), isTrue)
+
+	// excerpt is of the original text, not the text unescaped in place
+	raw = `["\u4F60", tru]`
+	for _, f := range []func() error{
+		func() error { _, err := UnmarshalString(raw); return err },
+		func() error { _, err := Unmarshal([]byte(raw)); return err },
+	} {
+		se = getSyntaxError(f())
+		so(se, notNil)
+		so(se.Excerpt, eq, raw)
+	}
+}
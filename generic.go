@@ -0,0 +1,376 @@
+//go:build go1.18
+// +build go1.18
+
+package jsonvalue
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================ GENERIC ACCESSORS ================
+
+// Get returns sub value in specified position converted to type T. Param
+// formats are like At(). Supported types are the same as As.
+//
+// Get 返回按照参数指定的位置的成员值, 并转换为 T 类型。参数格式与 At() 函数相同, 支持的类型与 As 相同。
+func Get[T any](v *V, firstParam any, otherParams ...any) (T, error) {
+	child, err := get(v, false, firstParam, otherParams...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return As[T](child)
+}
+
+// GetOr is same as Get, but returns defaultValue if error occurs.
+//
+// GetOr 与 Get 相同, 但是当错误发生时返回 defaultValue。
+func GetOr[T any](v *V, defaultValue T, firstParam any, otherParams ...any) T {
+	res, err := Get[T](v, firstParam, otherParams...)
+	if err != nil {
+		return defaultValue
+	}
+	return res
+}
+
+// As converts v to type T. Supported types are:
+//
+//   - Numeric types, including named ones such as time.Duration, from numbers.
+//     ErrOutOfRange is returned if the number overflows T, and the fraction part
+//     is truncated for integer types.
+//   - string from strings and bool from booleans.
+//   - []byte from Base64 strings, same as GetBytes.
+//   - time.Time from RFC 3339 strings, or numbers as Unix timestamps in seconds.
+//   - *V, which returns v itself.
+//   - Slices of supported types from arrays, and maps with string keys and
+//     supported value types from objects.
+//   - Pointers to supported types.
+//   - Other types, such as structs and interfaces, are converted by Export.
+//
+// Null is converted to zero value of T, except for numeric types, string and
+// bool. Unlike the GetXxx methods, numbers in strings are not converted. If
+// error occurs, zero value of T is returned.
+//
+// As 将 v 转换为 T 类型。支持的类型如下:
+//
+//   - 数值类型, 包括 time.Duration 等具名类型, 从数字转换。如果数字超出了 T 的范围则返回
+//     ErrOutOfRange, 转为整型时小数部分会被截断。
+//   - string 从字符串转换, bool 从布尔值转换。
+//   - []byte 从 Base64 字符串转换, 与 GetBytes 相同。
+//   - time.Time 从 RFC 3339 字符串转换, 或者将数字视为以秒为单位的 Unix 时间戳。
+//   - *V, 此时返回 v 本身。
+//   - 上述类型的切片从数组转换, 键为字符串、值为上述类型的 map 从对象转换。
+//   - 指向上述类型的指针。
+//   - 其他类型, 比如结构体和接口, 通过 Export 转换。
+//
+// 除了数值类型、string 和 bool 之外, null 会被转换为 T 的零值。与 GetXxx 方法不同, 字符串中的数字不会
+// 被转换。如果发生错误, 则返回 T 的零值。
+func As[T any](v *V) (T, error) {
+	var zero T
+	if v == nil || v.valueType == NotExist {
+		return zero, ErrValueUninitialized
+	}
+
+	// common types without reflection, so that res would not escape
+	var res T
+	var err error
+	switch p := any(&res).(type) {
+	case *int:
+		var i int64
+		i, err = asInt64(v, typeOfInt)
+		*p = int(i)
+	case *int64:
+		*p, err = asInt64(v, typeOfInt64)
+	case *uint64:
+		*p, err = asUint64(v, typeOfUint64)
+	case *float64:
+		*p, err = asFloat64(v, typeOfFloat64)
+	case *string:
+		*p, err = asString(v)
+	case *bool:
+		*p, err = asBool(v)
+	default:
+		return asReflectValue[T](v)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return res, nil
+}
+
+func asReflectValue[T any](v *V) (T, error) {
+	var res T
+	if err := asValue(v, reflect.ValueOf(&res).Elem()); err != nil {
+		var zero T
+		return zero, err
+	}
+	return res, nil
+}
+
+// MustAs is same as As, but returns zero value of T if error occurs.
+//
+// MustAs 与 As 相同, 但是当错误发生时返回 T 的零值。
+func MustAs[T any](v *V) T {
+	res, _ := As[T](v)
+	return res
+}
+
+var (
+	typeOfV       = reflect.TypeOf((*V)(nil))
+	typeOfTime    = reflect.TypeOf(time.Time{})
+	typeOfInt     = reflect.TypeOf(int(0))
+	typeOfInt64   = reflect.TypeOf(int64(0))
+	typeOfUint64  = reflect.TypeOf(uint64(0))
+	typeOfFloat64 = reflect.TypeOf(float64(0))
+)
+
+// asValue converts v to the addressable value dst.
+func asValue(v *V, dst reflect.Value) error {
+	t := dst.Type()
+	switch {
+	case t == typeOfV:
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	case t == typeOfTime:
+		return asTime(v, dst)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := asInt64(v, t)
+		if err == nil {
+			dst.SetInt(i)
+		}
+		return err
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := asUint64(v, t)
+		if err == nil {
+			dst.SetUint(u)
+		}
+		return err
+
+	case reflect.Float32, reflect.Float64:
+		f, err := asFloat64(v, t)
+		if err == nil {
+			dst.SetFloat(f)
+		}
+		return err
+
+	case reflect.String:
+		s, err := asString(v)
+		if err == nil {
+			dst.SetString(s)
+		}
+		return err
+
+	case reflect.Bool:
+		b, err := asBool(v)
+		if err == nil {
+			dst.SetBool(b)
+		}
+		return err
+
+	case reflect.Slice:
+		if v.valueType == Null {
+			dst.Set(reflect.Zero(t))
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return asBytes(v, dst)
+		}
+		return asSlice(v, dst)
+
+	case reflect.Map:
+		if v.valueType == Null {
+			dst.Set(reflect.Zero(t))
+			return nil
+		}
+		if t.Key().Kind() != reflect.String {
+			return fmt.Errorf("%w, unsupported map key type %v", ErrTypeNotMatch, t.Key())
+		}
+		return asMap(v, dst)
+
+	case reflect.Ptr:
+		if v.valueType == Null {
+			dst.Set(reflect.Zero(t))
+			return nil
+		}
+		elem := reflect.New(t.Elem())
+		if err := asValue(v, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+
+	default:
+		return v.Export(dst.Addr().Interface())
+	}
+}
+
+// asInt64 converts number v to an integer of type t.
+func asInt64(v *V, t reflect.Type) (int64, error) {
+	if v.valueType != Number {
+		return 0, ErrTypeNotMatch
+	}
+	i, ok := numberToInt64(v)
+	if bits := uint(t.Bits()); !ok || (bits < 64 && (i < -1<<(bits-1) || i >= 1<<(bits-1))) {
+		return 0, fmt.Errorf("%w, %v overflows %v", ErrOutOfRange, v, t)
+	}
+	return i, nil
+}
+
+// asUint64 converts number v to an unsigned integer of type t.
+func asUint64(v *V, t reflect.Type) (uint64, error) {
+	if v.valueType != Number {
+		return 0, ErrTypeNotMatch
+	}
+	u, ok := numberToUint64(v)
+	if bits := uint(t.Bits()); !ok || (bits < 64 && u >= 1<<bits) {
+		return 0, fmt.Errorf("%w, %v overflows %v", ErrOutOfRange, v, t)
+	}
+	return u, nil
+}
+
+// asFloat64 converts number v to a float of type t.
+func asFloat64(v *V, t reflect.Type) (float64, error) {
+	if v.valueType != Number {
+		return 0, ErrTypeNotMatch
+	}
+	f := v.num.f64
+	if t.Kind() == reflect.Float32 && !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+		return 0, fmt.Errorf("%w, %v overflows %v", ErrOutOfRange, v, t)
+	}
+	return f, nil
+}
+
+func asString(v *V) (string, error) {
+	if v.valueType != String {
+		return "", ErrTypeNotMatch
+	}
+	return v.valueStr, nil
+}
+
+func asBool(v *V) (bool, error) {
+	if v.valueType != Boolean {
+		return false, ErrTypeNotMatch
+	}
+	return v.valueBool, nil
+}
+
+// Bounds of float64 values which could be converted to int64 and uint64
+// exactly. The lower bound of int64 is -int64Bound.
+const (
+	int64Bound  = float64(1 << 63)
+	uint64Bound = float64(1 << 64)
+)
+
+// numberToInt64 returns the integer part of number v. ok is false if it
+// overflows int64. Integers are checked by cached values directly, while
+// floats and integers near the bounds are checked by BigInt.
+func numberToInt64(v *V) (i int64, ok bool) {
+	if f := v.num.f64; !v.num.floated && f > -int64Bound && f < int64Bound {
+		return v.num.i64, true
+	}
+	if !isValidFloat(v.num.f64) {
+		return 0, false
+	}
+	b := v.BigInt()
+	return b.Int64(), b.IsInt64()
+}
+
+// numberToUint64 returns the integer part of number v. ok is false if it
+// overflows uint64.
+func numberToUint64(v *V) (u uint64, ok bool) {
+	if f := v.num.f64; !v.num.floated && f >= 0 && f < uint64Bound {
+		return v.num.u64, true
+	}
+	if !isValidFloat(v.num.f64) {
+		return 0, false
+	}
+	b := v.BigInt()
+	return b.Uint64(), b.IsUint64()
+}
+
+func asTime(v *V, dst reflect.Value) error {
+	switch v.valueType {
+	default:
+		return ErrTypeNotMatch
+
+	case Null:
+		dst.Set(reflect.ValueOf(time.Time{}))
+		return nil
+
+	case String:
+		tm, err := time.Parse(time.RFC3339Nano, v.valueStr)
+		if err != nil {
+			return fmt.Errorf("%w, %v", ErrTypeNotMatch, err)
+		}
+		dst.Set(reflect.ValueOf(tm))
+		return nil
+
+	case Number:
+		if !isValidFloat(v.num.f64) {
+			return fmt.Errorf("%w, %v is not a valid timestamp", ErrOutOfRange, v)
+		}
+		d := v.Decimal()
+		sec := d.Truncate(0)
+		nsec := d.Sub(sec).Shift(9).Truncate(0)
+		if !sec.Equal(decimal.NewFromInt(sec.IntPart())) {
+			return fmt.Errorf("%w, %v is not a valid timestamp", ErrOutOfRange, v)
+		}
+		dst.Set(reflect.ValueOf(time.Unix(sec.IntPart(), nsec.IntPart())))
+		return nil
+	}
+}
+
+func asBytes(v *V, dst reflect.Value) error {
+	if v.valueType != String {
+		return ErrTypeNotMatch
+	}
+	b, err := internal.b64.DecodeString(v.valueStr)
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrTypeNotMatch, err)
+	}
+	dst.SetBytes(b)
+	return nil
+}
+
+func asSlice(v *V, dst reflect.Value) error {
+	if v.valueType != Array {
+		return ErrTypeNotMatch
+	}
+	arr := v.loadedChildren().arr
+	res := reflect.MakeSlice(dst.Type(), len(arr), len(arr))
+	for i, child := range arr {
+		if err := asValue(child, res.Index(i)); err != nil {
+			return fmt.Errorf("%w, index %d", err, i)
+		}
+	}
+	dst.Set(res)
+	return nil
+}
+
+func asMap(v *V, dst reflect.Value) error {
+	if v.valueType != Object {
+		return ErrTypeNotMatch
+	}
+	t := dst.Type()
+	obj := v.loadedChildren().object
+	res := reflect.MakeMapWithSize(t, len(obj))
+	elem := reflect.New(t.Elem()).Elem()
+	for k, child := range obj {
+		elem.Set(reflect.Zero(t.Elem()))
+		if err := asValue(child.v, elem); err != nil {
+			return fmt.Errorf("%w, key %q", err, k)
+		}
+		res.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+	}
+	dst.Set(res)
+	return nil
+}
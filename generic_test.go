@@ -0,0 +1,300 @@
+//go:build go1.18
+// +build go1.18
+
+package jsonvalue
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGeneric(t *testing.T) {
+	test(t, "test generic accessors", testGeneric)
+}
+
+func testGeneric(t *testing.T) {
+	cv("numbers", func() { testGenericNumbers(t) })
+	cv("basic types", func() { testGenericBasicTypes(t) })
+	cv("time", func() { testGenericTime(t) })
+	cv("containers", func() { testGenericContainers(t) })
+	cv("export", func() { testGenericExport(t) })
+	cv("Get and GetOr", func() { testGenericGet(t) })
+}
+
+func testGenericNumbers(t *testing.T) {
+	v := MustUnmarshalString(`[-1, 255, 256, 1.9, 18446744073709551615, -9223372036854775808, 3.5e38]`)
+	num := func(i int) *V { return v.MustGet(i) }
+
+	i, err := As[int](num(0))
+	so(err, isNil)
+	so(i, eq, -1)
+
+	u8, err := As[uint8](num(1))
+	so(err, isNil)
+	so(u8, eq, 255)
+
+	_, err = As[uint8](num(2))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	_, err = As[uint](num(0))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	_, err = As[int8](num(1))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+
+	i16, err := As[int16](num(3))
+	so(err, isNil)
+	so(i16, eq, 1)
+
+	u64, err := As[uint64](num(4))
+	so(err, isNil)
+	so(u64, eq, uint64(math.MaxUint64))
+	_, err = As[int64](num(4))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+
+	i64, err := As[int64](num(5))
+	so(err, isNil)
+	so(i64, eq, int64(math.MinInt64))
+
+	f, err := As[float64](num(3))
+	so(err, isNil)
+	so(f, eq, 1.9)
+	_, err = As[float32](num(6))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	f32, err := As[float32](num(3))
+	so(err, isNil)
+	so(f32, eq, float32(1.9))
+
+	_, err = As[int](NewFloat64(math.Inf(1)))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+
+	// bounds
+	bounds := MustUnmarshalString(`[9223372036854775807, -9223372036854775808, 9223372036854775808,
+		-9223372036854775809, 18446744073709551616, -0, -0.5, 0.99999999999999999999]`)
+	bound := func(i int) *V { return bounds.MustGet(i) }
+	i64, err = As[int64](bound(0))
+	so(err, isNil)
+	so(i64, eq, int64(math.MaxInt64))
+	i64, err = As[int64](bound(1))
+	so(err, isNil)
+	so(i64, eq, int64(math.MinInt64))
+	_, err = As[int64](bound(2))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	u64, err = As[uint64](bound(2))
+	so(err, isNil)
+	so(u64, eq, uint64(1)<<63)
+	_, err = As[int64](bound(3))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	_, err = As[uint64](bound(4))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	u, err := As[uint](bound(5))
+	so(err, isNil)
+	so(u, eq, 0)
+	u, err = As[uint](bound(6))
+	so(err, isNil)
+	so(u, eq, 0)
+	i, err = As[int](bound(7))
+	so(err, isNil)
+	so(i, eq, 0)
+	_, err = As[int64](NewFloat64(1e300))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	_, err = As[uint64](NewFloat64(-1e300))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	i, err = As[int](NewFloat64(-2.5))
+	so(err, isNil)
+	so(i, eq, -2)
+
+	// common types are converted without allocation, as GetInt does
+	n := num(1)
+	so(testing.AllocsPerRun(100, func() { _, _ = As[int](n) }), eq, 0)
+	so(testing.AllocsPerRun(100, func() { _, _ = As[uint64](n) }), eq, 0)
+	so(testing.AllocsPerRun(100, func() { _, _ = As[float64](n) }), eq, 0)
+
+	d, err := As[time.Duration](num(1))
+	so(err, isNil)
+	so(d, eq, 255*time.Nanosecond)
+
+	// numbers in strings are not converted
+	_, err = As[int](NewString("1"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+	_, err = As[float64](NewBool(true))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+	_, err = As[uint](NewNull())
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+}
+
+func testGenericBasicTypes(t *testing.T) {
+	s, err := As[string](NewString("hello"))
+	so(err, isNil)
+	so(s, eq, "hello")
+	_, err = As[string](NewInt(1))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+
+	type myString string
+	ms, err := As[myString](NewString("named"))
+	so(err, isNil)
+	so(ms, eq, myString("named"))
+
+	b, err := As[bool](NewBool(true))
+	so(err, isNil)
+	so(b, isTrue)
+	_, err = As[bool](NewString("true"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+
+	bytes, err := As[[]byte](NewBytes([]byte{1, 2, 3}))
+	so(err, isNil)
+	so(bytes, resemble, []byte{1, 2, 3})
+	_, err = As[[]byte](NewString("!@#"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+
+	v := NewObject()
+	res, err := As[*V](v)
+	so(err, isNil)
+	so(res == v, isTrue)
+
+	_, err = As[int](nil)
+	so(errors.Is(err, ErrValueUninitialized), isTrue)
+	_, err = As[int](&V{})
+	so(errors.Is(err, ErrValueUninitialized), isTrue)
+
+	so(MustAs[string](NewString("s")), eq, "s")
+	so(MustAs[string](NewInt(1)), eq, "")
+	so(MustAs[int](NewInt(1)), eq, 1)
+}
+
+func testGenericTime(t *testing.T) {
+	tm, err := As[time.Time](NewString("2024-01-02T03:04:05.123Z"))
+	so(err, isNil)
+	so(tm.Equal(time.Date(2024, 1, 2, 3, 4, 5, 123000000, time.UTC)), isTrue)
+
+	tm, err = As[time.Time](NewString("2024-01-02T03:04:05+08:00"))
+	so(err, isNil)
+	so(tm.Unix(), eq, time.Date(2024, 1, 1, 19, 4, 5, 0, time.UTC).Unix())
+
+	tm, err = As[time.Time](MustUnmarshalString(`1700000000.25`))
+	so(err, isNil)
+	so(tm.Equal(time.Unix(1700000000, 250000000)), isTrue)
+
+	tm, err = As[time.Time](MustUnmarshalString(`-1.5`))
+	so(err, isNil)
+	so(tm.Equal(time.Unix(-2, 500000000)), isTrue)
+
+	tm, err = As[time.Time](NewNull())
+	so(err, isNil)
+	so(tm.IsZero(), isTrue)
+
+	_, err = As[time.Time](NewString("2024-01-02"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+	_, err = As[time.Time](MustUnmarshalString(`1e30`))
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+	_, err = As[time.Time](NewBool(true))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+}
+
+func testGenericContainers(t *testing.T) {
+	v := MustUnmarshalString(`{
+		"ints": [1, 2, 3],
+		"nested": [["a"], [], null],
+		"map": {"a": 1, "b": 2},
+		"maps": {"x": {"k": true}},
+		"values": [1, "s", null],
+		"ptrs": [1, null],
+		"mixed": [1, "2"],
+		"null": null
+	}`)
+
+	ints, err := As[[]int](v.MustGet("ints"))
+	so(err, isNil)
+	so(ints, resemble, []int{1, 2, 3})
+
+	nested, err := As[[][]string](v.MustGet("nested"))
+	so(err, isNil)
+	so(nested, resemble, [][]string{{"a"}, {}, nil})
+
+	m, err := As[map[string]uint](v.MustGet("map"))
+	so(err, isNil)
+	so(m, resemble, map[string]uint{"a": 1, "b": 2})
+
+	type key string
+	maps, err := As[map[key]map[string]bool](v.MustGet("maps"))
+	so(err, isNil)
+	so(maps, resemble, map[key]map[string]bool{"x": {"k": true}})
+
+	values, err := As[[]*V](v.MustGet("values"))
+	so(err, isNil)
+	so(len(values), eq, 3)
+	so(values[1] == v.MustGet("values", 1), isTrue)
+
+	ptrs, err := As[[]*int](v.MustGet("ptrs"))
+	so(err, isNil)
+	so(len(ptrs), eq, 2)
+	so(*ptrs[0], eq, 1)
+	so(ptrs[1], isNil)
+
+	nilSlice, err := As[[]int](v.MustGet("null"))
+	so(err, isNil)
+	so(nilSlice, isNil)
+	nilMap, err := As[map[string]int](v.MustGet("null"))
+	so(err, isNil)
+	so(nilMap, isNil)
+
+	_, err = As[[]int](v.MustGet("mixed"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+	so(err.Error(), hasSubStr, "index 1")
+
+	_, err = As[map[string]string](v.MustGet("map"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+
+	_, err = As[[]int](v.MustGet("map"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+	_, err = As[map[string]int](v.MustGet("ints"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+	_, err = As[map[int]int](v.MustGet("map"))
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+}
+
+func testGenericExport(t *testing.T) {
+	type item struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}
+
+	v := MustUnmarshalString(`{"items":[{"id":1,"tags":["a"]},{"id":2}],"any":{"k":[1]}}`)
+
+	items, err := As[[]item](v.MustGet("items"))
+	so(err, isNil)
+	so(items, resemble, []item{{ID: 1, Tags: []string{"a"}}, {ID: 2}})
+
+	it, err := As[*item](v.MustGet("items", 0))
+	so(err, isNil)
+	so(it.ID, eq, 1)
+
+	a, err := As[any](v.MustGet("any"))
+	so(err, isNil)
+	so(a, resemble, map[string]any{"k": []any{float64(1)}})
+
+	_, err = As[item](NewString("item"))
+	so(err, isErr)
+}
+
+func testGenericGet(t *testing.T) {
+	v := MustUnmarshalString(`{"data":{"list":[{"name":"a","n":1}]}}`)
+
+	name, err := Get[string](v, "data", "list", 0, "name")
+	so(err, isNil)
+	so(name, eq, "a")
+
+	n, err := Get[int64](v, []any{"data", "list", 0, "n"})
+	so(err, isNil)
+	so(n, eq, 1)
+
+	_, err = Get[int](v, "data", "none")
+	so(errors.Is(err, ErrNotFound), isTrue)
+	_, err = Get[int](v, "data", "list", 0, "name")
+	so(errors.Is(err, ErrTypeNotMatch), isTrue)
+
+	so(GetOr(v, "default", "data", "list", 0, "name"), eq, "a")
+	so(GetOr(v, "default", "data", "list", 1, "name"), eq, "default")
+	so(GetOr(v, 10, "data", "list", 0, "name"), eq, 10)
+	so(GetOr(v, []int{1}, "data"), resemble, []int{1})
+}
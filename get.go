@@ -3,7 +3,10 @@ package jsonvalue
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"strings"
+
+	"github.com/shopspring/decimal"
 )
 
 // ================ GET ================
@@ -14,9 +17,9 @@ import (
 func (v *V) Len() int {
 	switch v.valueType {
 	case Array:
-		return len(v.children.arr)
+		return len(v.loadedChildren().arr)
 	case Object:
-		return len(v.children.object)
+		return len(v.loadedChildren().object)
 	default:
 		return 0
 	}
@@ -58,17 +61,19 @@ func get(v *V, caseless bool, firstParam any, otherParams ...any) (*V, error) {
 }
 
 func initCaselessStorage(v *V) {
-	if v.children.lowerCaseKeys != nil {
+	c := v.loadedChildren()
+	if c.lowerCaseKeys != nil {
 		return
 	}
-	v.children.lowerCaseKeys = make(map[string]map[string]struct{}, len(v.children.object))
-	for k := range v.children.object {
+	c.lowerCaseKeys = make(map[string]map[string]struct{}, len(c.object))
+	for k := range c.object {
 		addCaselessKey(v, k)
 	}
 }
 
 func getFromObjectChildren(v *V, caseless bool, key string) (child *V, exist bool) {
-	childProperty, exist := v.children.object[key]
+	c := v.loadedChildren()
+	childProperty, exist := c.object[key]
 	if exist {
 		return childProperty.v, true
 	}
@@ -80,13 +85,13 @@ func getFromObjectChildren(v *V, caseless bool, key string) (child *V, exist boo
 	initCaselessStorage(v)
 
 	lowerCaseKey := strings.ToLower(key)
-	keys, exist := v.children.lowerCaseKeys[lowerCaseKey]
+	keys, exist := c.lowerCaseKeys[lowerCaseKey]
 	if !exist {
 		return &V{}, false
 	}
 
 	for actualKey := range keys {
-		childProperty, exist = v.children.object[actualKey]
+		childProperty, exist = c.object[actualKey]
 		if exist {
 			return childProperty.v, true
 		}
@@ -300,6 +305,54 @@ func getFloat32(v *V, caseless bool, firstParam any, otherParams ...any) (float3
 	return ret.Float32(), err
 }
 
+// GetDecimal is equivalent to v, err := Get(...); v.Decimal(). If error occurs, returns zero.
+//
+// GetDecimal 等效于 v, err := Get(...); v.Decimal()。如果发生错误，则返回 0。
+func (v *V) GetDecimal(firstParam any, otherParams ...any) (decimal.Decimal, error) {
+	return getDecimal(v, false, firstParam, otherParams...)
+}
+
+func getDecimal(v *V, caseless bool, firstParam any, otherParams ...any) (decimal.Decimal, error) {
+	ret, err := get(v, caseless, firstParam, otherParams...)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	ret, err = getNumberAndErrorFromValue(ret)
+	return ret.Decimal(), err
+}
+
+// GetBigInt is equivalent to v, err := Get(...); v.BigInt(). If error occurs, returns zero.
+//
+// GetBigInt 等效于 v, err := Get(...); v.BigInt()。如果发生错误，则返回 0。
+func (v *V) GetBigInt(firstParam any, otherParams ...any) (*big.Int, error) {
+	return getBigInt(v, false, firstParam, otherParams...)
+}
+
+func getBigInt(v *V, caseless bool, firstParam any, otherParams ...any) (*big.Int, error) {
+	ret, err := get(v, caseless, firstParam, otherParams...)
+	if err != nil {
+		return &big.Int{}, err
+	}
+	ret, err = getNumberAndErrorFromValue(ret)
+	return ret.BigInt(), err
+}
+
+// GetBigFloat is equivalent to v, err := Get(...); v.BigFloat(). If error occurs, returns zero.
+//
+// GetBigFloat 等效于 v, err := Get(...); v.BigFloat()。如果发生错误，则返回 0。
+func (v *V) GetBigFloat(firstParam any, otherParams ...any) (*big.Float, error) {
+	return getBigFloat(v, false, firstParam, otherParams...)
+}
+
+func getBigFloat(v *V, caseless bool, firstParam any, otherParams ...any) (*big.Float, error) {
+	ret, err := get(v, caseless, firstParam, otherParams...)
+	if err != nil {
+		return &big.Float{}, err
+	}
+	ret, err = getNumberAndErrorFromValue(ret)
+	return ret.BigFloat(), err
+}
+
 // GetBool is equivalent to v, err := Get(...); v.Bool(). If error occurs, returns false.
 //
 // GetBool 等效于 v, err := Get(...); v.Bool()。如果发生错误，则返回 false。
@@ -389,6 +442,9 @@ type Caseless interface {
 	GetUint32(firstParam any, otherParams ...any) (uint32, error)
 	GetFloat64(firstParam any, otherParams ...any) (float64, error)
 	GetFloat32(firstParam any, otherParams ...any) (float32, error)
+	GetDecimal(firstParam any, otherParams ...any) (decimal.Decimal, error)
+	GetBigInt(firstParam any, otherParams ...any) (*big.Int, error)
+	GetBigFloat(firstParam any, otherParams ...any) (*big.Float, error)
 	GetBool(firstParam any, otherParams ...any) (bool, error)
 	GetNull(firstParam any, otherParams ...any) error
 	GetObject(firstParam any, otherParams ...any) (*V, error)
@@ -473,6 +529,18 @@ func (g *caselessOp) GetFloat32(firstParam any, otherParams ...any) (float32, er
 	return getFloat32(g.v, true, firstParam, otherParams...)
 }
 
+func (g *caselessOp) GetDecimal(firstParam any, otherParams ...any) (decimal.Decimal, error) {
+	return getDecimal(g.v, true, firstParam, otherParams...)
+}
+
+func (g *caselessOp) GetBigInt(firstParam any, otherParams ...any) (*big.Int, error) {
+	return getBigInt(g.v, true, firstParam, otherParams...)
+}
+
+func (g *caselessOp) GetBigFloat(firstParam any, otherParams ...any) (*big.Float, error) {
+	return getBigFloat(g.v, true, firstParam, otherParams...)
+}
+
 func (g *caselessOp) GetBool(firstParam any, otherParams ...any) (bool, error) {
 	return getBool(g.v, true, firstParam, otherParams...)
 }
@@ -12,6 +12,7 @@ func testGet(t *testing.T) {
 	cv("NotExist get", func() { testNotExistGet(t) })
 	cv("get number from a string", func() { testGetNumFromString(t) })
 	cv("get with slice", func() { testGetWithSlice(t) })
+	cv("get big numbers", func() { testGetBigNumber(t) })
 }
 
 func testJsonvalue_Get(t *testing.T) {
@@ -628,3 +629,54 @@ func testGetWithSlice(_ *testing.T) {
 	so(err, isErr)
 
 }
+
+func testGetBigNumber(*testing.T) {
+	raw := `{"int":-12345,"Float":12345678901234567890.0123456789,"str":"99.5","bool":true}`
+	v := MustUnmarshalString(raw)
+
+	cv("GetBigInt", func() {
+		i, err := v.GetBigInt("int")
+		so(err, isNil)
+		so(i.Int64(), eq, -12345)
+
+		i, err = v.GetBigInt("Float")
+		so(err, isNil)
+		so(i.String(), eq, "12345678901234567890")
+
+		i, err = v.GetBigInt("str")
+		so(errors.Is(err, ErrTypeNotMatch), isTrue)
+		so(i.Int64(), eq, 99)
+
+		i, err = v.GetBigInt("bool")
+		so(errors.Is(err, ErrTypeNotMatch), isTrue)
+		so(i.Sign(), eq, 0)
+
+		i, err = v.Caseless().GetBigInt("INT")
+		so(err, isNil)
+		so(i.Int64(), eq, -12345)
+
+		_, err = v.GetBigInt("not exist")
+		so(errors.Is(err, ErrNotFound), isTrue)
+	})
+
+	cv("GetBigFloat", func() {
+		f, err := v.GetBigFloat("Float")
+		so(err, isNil)
+		so(f.Text('f', 10), eq, "12345678901234567890.0123456789")
+
+		f, err = v.Caseless().GetBigFloat("float")
+		so(err, isNil)
+		so(f.Text('f', 10), eq, "12345678901234567890.0123456789")
+
+		f, err = v.GetBigFloat("str")
+		so(errors.Is(err, ErrTypeNotMatch), isTrue)
+		so(f.Text('f', 1), eq, "99.5")
+
+		_, err = v.GetBigFloat("not exist")
+		so(errors.Is(err, ErrNotFound), isTrue)
+
+		var nilV *V
+		so(nilV.BigFloat().Sign(), eq, 0)
+		so(nilV.BigInt().Sign(), eq, 0)
+	})
+}
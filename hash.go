@@ -0,0 +1,196 @@
+package jsonvalue
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"sort"
+	"strconv"
+)
+
+// ================ HASH ================
+
+// Type tags written before each value, so that values of different types never
+// share the same digest input.
+const (
+	hashTagNotExist = byte('x')
+	hashTagNull     = byte('n')
+	hashTagTrue     = byte('t')
+	hashTagFalse    = byte('f')
+	hashTagNumber   = byte('d')
+	hashTagString   = byte('s')
+	hashTagArray    = byte('a')
+	hashTagObject   = byte('o')
+)
+
+// Hash writes a structural digest of current value into given hash.Hash. The
+// result does not depend on the sequence of object keys, or how numbers were
+// written, for example 1.0, 1 and 1e0 are the same. In other words, values
+// which are Equal always generate same digests.
+//
+// Hash 将当前值的结构化摘要写入给定的 hash.Hash 中。结果与 object 中键的顺序无关, 也与数字的
+// 书写方式无关, 比如 1.0, 1 和 1e0 是相同的。换言之, Equal 的值总是生成相同的摘要。
+func (v *V) Hash(h hash.Hash) {
+	if h == nil {
+		return
+	}
+	if v == nil {
+		v = &V{}
+	}
+	w := hashWriter{h: h}
+	w.write(v)
+}
+
+// Fingerprint returns SHA-256 checksum of the structural digest of current value,
+// which is the same as Hash method. It is suitable for cache keys or ETags.
+//
+// Fingerprint 返回当前值结构化摘要的 SHA-256 校验和, 摘要规则与 Hash 方法相同。可以用于缓存的
+// 键或 ETag。
+func (v *V) Fingerprint() [32]byte {
+	h := sha256.New()
+	v.Hash(h)
+
+	res := [32]byte{}
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+type hashWriter struct {
+	h   hash.Hash
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (w *hashWriter) writeByte(b byte) {
+	w.buf[0] = b
+	_, _ = w.h.Write(w.buf[:1])
+}
+
+func (w *hashWriter) writeLen(n int) {
+	l := binary.PutUvarint(w.buf[:], uint64(n))
+	_, _ = w.h.Write(w.buf[:l])
+}
+
+// writeString writes length before content, so that boundaries of adjacent
+// strings are unambiguous.
+func (w *hashWriter) writeString(s string) {
+	w.writeLen(len(s))
+	_, _ = w.h.Write([]byte(s))
+}
+
+func (w *hashWriter) write(v *V) {
+	switch v.valueType {
+	default:
+		w.writeByte(hashTagNotExist)
+	case Null:
+		w.writeByte(hashTagNull)
+	case Boolean:
+		if v.valueBool {
+			w.writeByte(hashTagTrue)
+		} else {
+			w.writeByte(hashTagFalse)
+		}
+	case Number:
+		w.writeByte(hashTagNumber)
+		w.writeString(hashNumberString(v))
+	case String:
+		w.writeByte(hashTagString)
+		w.writeString(v.valueStr)
+	case Array:
+		w.writeByte(hashTagArray)
+		arr := v.loadedChildren().arr
+		w.writeLen(len(arr))
+		for _, c := range arr {
+			w.write(c)
+		}
+	case Object:
+		w.writeByte(hashTagObject)
+		object := v.loadedChildren().object
+		w.writeLen(len(object))
+		keys := make([]string, 0, len(object))
+		for k := range object {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			w.writeString(k)
+			w.write(object[k].v)
+		}
+	}
+}
+
+// hashNumberString returns a normalized text of a number, which is the sign,
+// the digits without leading or trailing zeros, and the exponent of the last
+// digit, such as "-125e-1" for -12.50. The number is never expanded, therefore
+// huge exponents such as 1e-10000000 cost nothing. Like numberEqual, srcByte is
+// the source of truth.
+func hashNumberString(v *V) string {
+	b := v.srcByte
+	if len(b) == 0 {
+		// NaN, +Inf and -Inf
+		return strconv.FormatFloat(v.num.f64, 'g', -1, 64)
+	}
+
+	negative := b[0] == '-'
+	if negative {
+		b = b[1:]
+	}
+
+	exp := int64(0)
+	if idx := bytes.IndexAny(b, "eE"); idx >= 0 {
+		e, err := strconv.ParseInt(string(b[idx+1:]), 10, 32)
+		if err != nil {
+			return string(v.srcByte) // exponent beyond int32, which decimal does not support either
+		}
+		b, exp = b[:idx], e
+	}
+
+	digits := make([]byte, 0, len(b)+1)
+	if negative {
+		digits = append(digits, '-')
+	}
+	signLen := len(digits)
+	for i, c := range b {
+		if c == '.' {
+			exp -= int64(len(b) - i - 1)
+			continue
+		}
+		if c == '0' && len(digits) == signLen {
+			continue // leading zeros
+		}
+		digits = append(digits, c)
+	}
+	if len(digits) == signLen {
+		return "0" // including -0
+	}
+	for digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+		exp++
+	}
+
+	if exp == 0 {
+		return string(digits)
+	}
+	digits = append(digits, 'e')
+	return string(strconv.AppendInt(digits, exp, 10))
+}
+
+// isPlainIntegerBytes tells whether b is an integer without exponent, leading
+// zeros or negative zero, which is already normalized.
+func isPlainIntegerBytes(b []byte) bool {
+	if b[0] == '-' {
+		b = b[1:]
+		if len(b) == 0 || b[0] == '0' {
+			return false
+		}
+	}
+	if len(b) == 0 || (b[0] == '0' && len(b) > 1) {
+		return false
+	}
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package jsonvalue
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+func testHash(t *testing.T) {
+	cv("same fingerprints", func() { testHashSame(t) })
+	cv("different fingerprints", func() { testHashDifferent(t) })
+	cv("hash with other algorithm", func() { testHashOtherAlgorithm(t) })
+}
+
+func testHashSame(t *testing.T) {
+	cv("key sequence", func() {
+		a := MustUnmarshalString(`{"a":1,"b":{"c":[1,2],"d":null},"e":"E"}`)
+		b := MustUnmarshalString(`{"e":"E","b":{"d":null,"c":[1,2]},"a":1}`)
+		so(a.Fingerprint(), eq, b.Fingerprint())
+
+		c := NewObject()
+		c.MustSet("E").At("e")
+		c.MustSet(1).At("a")
+		c.MustSetNull().At("b", "d")
+		c.MustSet(MustUnmarshalString(`[1,2]`)).At("b", "c")
+		so(c.Fingerprint(), eq, a.Fingerprint())
+	})
+
+	cv("number formats", func() {
+		numbers := []string{`1`, `1.0`, `1.00`, `1e0`, `1E0`, `10e-1`, `0.1e1`}
+		fp := NewInt(1).Fingerprint()
+		for _, s := range numbers {
+			so(MustUnmarshalString(s).Fingerprint(), eq, fp)
+		}
+		so(NewFloat64(1).Fingerprint(), eq, fp)
+		so(NewUint64(1).Fingerprint(), eq, fp)
+
+		so(MustUnmarshalString(`-0.0`).Fingerprint(), eq, NewInt(0).Fingerprint())
+		so(MustUnmarshalString(`-12.50`).Fingerprint(), eq, NewFloat64(-12.5).Fingerprint())
+		so(MustUnmarshalString(`1e18`).Fingerprint(), eq, NewUint64(1000000000000000000).Fingerprint())
+		so(MustUnmarshalString(`[1.5e3]`).Fingerprint(), eq, MustUnmarshalString(`[1500]`).Fingerprint())
+	})
+
+	cv("extreme exponents are not expanded", func() {
+		v := MustUnmarshalString(`1e-10000000`)
+		so(hashNumberString(v), eq, "1e-10000000")
+		so(v.Fingerprint(), eq, MustUnmarshalString(`0.0100e-9999998`).Fingerprint())
+		so(v.Fingerprint(), ne, MustUnmarshalString(`1e-9999999`).Fingerprint())
+
+		so(hashNumberString(MustUnmarshalString(`-12.50`)), eq, "-125e-1")
+		so(hashNumberString(MustUnmarshalString(`1200`)), eq, "12e2")
+		so(hashNumberString(MustUnmarshalString(`-0.000e5`)), eq, "0")
+	})
+
+	cv("equal values always generate same fingerprints", func() {
+		pairs := [][2]string{
+			{`{"a":[1,2.0,{"b":3E2}]}`, `{"a":[1.0,2,{"b":300}]}`},
+			{`[true,false,null,""]`, `[true, false, null, ""]`},
+			{`"中"`, `"中"`},
+		}
+		for _, p := range pairs {
+			a, b := MustUnmarshalString(p[0]), MustUnmarshalString(p[1])
+			so(a.Equal(b), isTrue)
+			so(a.Fingerprint(), eq, b.Fingerprint())
+		}
+	})
+
+	cv("NaN and Inf", func() {
+		so(NewFloat64(math.NaN()).Fingerprint(), eq, NewFloat64(math.NaN()).Fingerprint())
+		so(NewFloat64(math.Inf(1)).Fingerprint(), ne, NewFloat64(math.Inf(-1)).Fingerprint())
+	})
+}
+
+func testHashDifferent(t *testing.T) {
+	values := []string{
+		`null`, `true`, `false`, `0`, `1`, `-1`, `1.5`, `""`, `"1"`, `"null"`,
+		`[]`, `{}`, `[null]`, `[[]]`, `[{}]`, `{"":null}`, `{"a":null}`, `{"null":null}`,
+		`["a","b"]`, `["ab"]`, `["b","a"]`, `[1,2]`, `[2,1]`, `[[1],2]`, `[1,[2]]`,
+		`{"a":"b"}`, `{"ab":""}`, `{"a":{"b":null}}`, `{"a":["b"]}`, `{"a":1,"b":2}`, `{"a":2,"b":1}`,
+	}
+	fingerprints := map[[32]byte]string{}
+	for _, s := range values {
+		fp := MustUnmarshalString(s).Fingerprint()
+		existed, exist := fingerprints[fp]
+		so(existed, eq, "")
+		so(exist, isFalse)
+		fingerprints[fp] = s
+	}
+
+	var nilV *V
+	so(nilV.Fingerprint(), eq, (&V{}).Fingerprint())
+	so(nilV.Fingerprint(), ne, NewNull().Fingerprint())
+}
+
+func testHashOtherAlgorithm(t *testing.T) {
+	v := MustUnmarshalString(`{"b":[1,2,3],"a":"hello"}`)
+
+	h := md5.New()
+	v.Hash(h)
+	sum := hex.EncodeToString(h.Sum(nil))
+	so(len(sum), eq, 32)
+
+	h = md5.New()
+	MustUnmarshalString(`{"a":"hello","b":[1.0,2,3]}`).Hash(h)
+	so(hex.EncodeToString(h.Sum(nil)), eq, sum)
+
+	// Fingerprint is identical to Hash with SHA-256
+	h = sha256.New()
+	v.Hash(h)
+	fp := v.Fingerprint()
+	so(hex.EncodeToString(h.Sum(nil)), eq, hex.EncodeToString(fp[:]))
+
+	// nil hash should be ignored
+	v.Hash(nil)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -75,8 +76,9 @@ func extractStringValue(v *V) *V {
 }
 
 func extractObjectValue(v *V) *V {
-	for key, subV := range v.children.object {
-		v.children.object[key] = childWithProperty{
+	c := v.loadedChildren()
+	for key, subV := range c.object {
+		c.object[key] = childWithProperty{
 			id: subV.id,
 			v:  extractValue(subV.v),
 		}
@@ -85,8 +87,9 @@ func extractObjectValue(v *V) *V {
 }
 
 func extractArrayValue(v *V) *V {
-	for i, subV := range v.children.arr {
-		v.children.arr[i] = extractValue(subV)
+	c := v.loadedChildren()
+	for i, subV := range c.arr {
+		c.arr[i] = extractValue(subV)
 	}
 	return v
 }
@@ -117,6 +120,12 @@ func (e ext) shouldOmitEmpty() bool {
 func validateValAndReturnParser(v reflect.Value, ex ext) (out reflect.Value, fu parserFunc, err error) {
 	out = v
 
+	// math/big types, which should be numbers instead of what their marshalers
+	// generate
+	if fu = checkBigNumberParser(v); fu != nil {
+		return
+	}
+
 	// json.Marshaler and encoding.TextMarshaler
 	if o, f := checkAndParseMarshaler(v); f != nil {
 		// jsonvalue itself
@@ -315,6 +324,69 @@ func parseTextMarshaler(v reflect.Value, ex ext) (*V, error) {
 	return NewString(string(b)), nil
 }
 
+func checkBigNumberParser(v reflect.Value) parserFunc {
+	if !v.IsValid() {
+		return nil
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	default:
+		return nil
+	case internal.types.BigInt:
+		return parseBigIntValue
+	case internal.types.BigFloat:
+		return parseBigFloatValue
+	}
+}
+
+func parseBigIntValue(v reflect.Value, ex ext) (*V, error) {
+	var b *big.Int
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return parseInvalidValue(v, ex)
+		}
+		b, _ = v.Interface().(*big.Int)
+	} else {
+		i, _ := getPointerOfValue(v).Interface().(*big.Int)
+		b = i
+	}
+
+	if b.Sign() == 0 && ex.shouldOmitEmpty() {
+		return nil, nil
+	}
+	if ex.toString {
+		return NewString(b.String()), nil
+	}
+	return NewBigInt(b), nil
+}
+
+func parseBigFloatValue(v reflect.Value, ex ext) (*V, error) {
+	var f *big.Float
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return parseInvalidValue(v, ex)
+		}
+		f, _ = v.Interface().(*big.Float)
+	} else {
+		p, _ := getPointerOfValue(v).Interface().(*big.Float)
+		f = p
+	}
+
+	if f.IsInf() {
+		return &V{}, fmt.Errorf("%w: %v", ErrUnsupportedFloat, f)
+	}
+	if f.Sign() == 0 && ex.shouldOmitEmpty() {
+		return nil, nil
+	}
+	if ex.toString {
+		return NewString(bigFloatText(f)), nil
+	}
+	return NewBigFloat(f), nil
+}
+
 func parseInvalidValue(_ reflect.Value, ex ext) (*V, error) {
 	if ex.shouldOmitEmpty() {
 		return nil, nil
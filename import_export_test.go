@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"testing"
 	"time"
@@ -217,6 +219,7 @@ func testStructConv_Import(t *testing.T) {
 	cv("array and slice", func() { testStructConv_Import_ArrayAndSlice(t) })
 	cv("json.Marshaler", func() { testStructConv_Import_JSONMarshaler(t) })
 	cv("encoding.TextMarshaler", func() { testStructConv_Import_TextMarshaler(t) })
+	cv("math/big types", func() { testStructConv_Import_BigNumbers(t) })
 }
 
 func testStructConv_Import_RawAndBytes(t *testing.T) {
@@ -1428,3 +1431,54 @@ func testExtractAll(t *testing.T) {
 		so(v, notNil)
 	})
 }
+
+func testStructConv_Import_BigNumbers(t *testing.T) {
+	cv("general", func() {
+		huge, _ := (&big.Int{}).SetString("123456789012345678901234567890", 10)
+		st := struct {
+			IntPtr   *big.Int   `json:"int_ptr"`
+			Int      big.Int    `json:"int"`
+			FloatPtr *big.Float `json:"float_ptr"`
+			Float    big.Float  `json:"float"`
+			NilInt   *big.Int   `json:"nil_int"`
+			NilFloat *big.Float `json:"nil_float"`
+			Str      *big.Int   `json:"str,string"`
+			Omit     *big.Int   `json:"omit,omitempty"`
+		}{
+			IntPtr:   huge,
+			Int:      *big.NewInt(-1),
+			FloatPtr: big.NewFloat(1.5),
+			Float:    *big.NewFloat(0.25),
+			Str:      big.NewInt(1024),
+			Omit:     big.NewInt(0),
+		}
+
+		v, err := Import(st)
+		so(err, isNil)
+		so(v.MustMarshalString(OptSetSequence()), eq,
+			`{"int_ptr":123456789012345678901234567890,"int":-1,"float_ptr":1.5,"float":0.25,`+
+				`"nil_int":null,"nil_float":null,"str":"1024"}`)
+
+		i, err := v.GetBigInt("int_ptr")
+		so(err, isNil)
+		so(i.Cmp(huge), eq, 0)
+	})
+
+	cv("New and Set", func() {
+		huge, _ := (&big.Int{}).SetString("-99999999999999999999", 10)
+		v := New(huge)
+		so(v.MustMarshalString(), eq, "-99999999999999999999")
+
+		v = New(*big.NewFloat(2))
+		so(v.MustMarshalString(), eq, "2")
+
+		o := NewObject()
+		o.MustSet(huge).At("a")
+		so(o.MustMarshalString(), eq, `{"a":-99999999999999999999}`)
+	})
+
+	cv("Inf", func() {
+		_, err := Import([]*big.Float{big.NewFloat(math.Inf(-1))})
+		so(errors.Is(err, ErrUnsupportedFloat), isTrue)
+	})
+}
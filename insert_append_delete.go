@@ -280,9 +280,10 @@ func (ins *insert) After(firstParam any, otherParams ...any) (*V, error) {
 }
 
 func insertToArr(v *V, pos int, child *V) {
-	v.children.arr = append(v.children.arr, nil)
-	copy(v.children.arr[pos+1:], v.children.arr[pos:])
-	v.children.arr[pos] = child
+	c := v.loadedChildren()
+	c.arr = append(c.arr, nil)
+	copy(c.arr[pos+1:], c.arr[pos:])
+	c.arr[pos] = child
 }
 
 // ================ APPEND ================
@@ -522,9 +523,10 @@ func (apd *appender) InTheEnd(params ...any) (*V, error) {
 // MARK: DELETE
 
 func delFromObjectChildren(v *V, caseless bool, key string) (exist bool) {
-	_, exist = v.children.object[key]
+	c := v.loadedChildren()
+	_, exist = c.object[key]
 	if exist {
-		delete(v.children.object, key)
+		delete(c.object, key)
 		delCaselessKey(v, key)
 		return true
 	}
@@ -536,15 +538,15 @@ func delFromObjectChildren(v *V, caseless bool, key string) (exist bool) {
 	initCaselessStorage(v)
 
 	lowerKey := strings.ToLower(key)
-	keys, exist := v.children.lowerCaseKeys[lowerKey]
+	keys, exist := c.lowerCaseKeys[lowerKey]
 	if !exist {
 		return false
 	}
 
 	for actualKey := range keys {
-		_, exist = v.children.object[actualKey]
+		_, exist = c.object[actualKey]
 		if exist {
-			delete(v.children.object, actualKey)
+			delete(c.object, actualKey)
 			delCaselessKey(v, actualKey)
 			return true
 		}
@@ -625,8 +627,9 @@ func deleteInCurrentArray(v *V, param any) error {
 }
 
 func deleteInArr(v *V, pos int) {
-	le := len(v.children.arr)
-	v.children.arr[pos] = nil
-	copy(v.children.arr[pos:], v.children.arr[pos+1:])
-	v.children.arr = v.children.arr[:le-1]
+	c := v.loadedChildren()
+	le := len(c.arr)
+	c.arr[pos] = nil
+	copy(c.arr[pos:], c.arr[pos+1:])
+	c.arr = c.arr[:le-1]
 }
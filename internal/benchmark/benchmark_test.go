@@ -20,3 +20,54 @@ func Benchmark_Unmarshal_Jsonvalue(b *testing.B) {
 		_, _ = jsonvalue.Unmarshal(origB)
 	}
 }
+
+func Benchmark_Unmarshal_JsonvalueParser(b *testing.B) {
+	origB := unmarshalText
+	p := jsonvalue.NewParser()
+	defer p.Release()
+	for i := 0; i < b.N; i++ {
+		_, _ = p.Unmarshal(origB)
+	}
+}
+
+// largeText is a large payload of which only a few fields are read
+var largeText = func() []byte {
+	items := jsonvalue.NewArray()
+	for i := 0; i < 2000; i++ {
+		item := jsonvalue.NewObject()
+		item.MustSet(i).At("id")
+		item.MustSet("Hello, world!").At("name")
+		item.MustSet(123.456789).At("score")
+		item.MustSet([]string{"a", "b", "c"}).At("tags")
+		items.MustAppend(item).InTheEnd()
+	}
+	v := jsonvalue.NewObject()
+	v.MustSet("abcdefg").At("request_id")
+	v.MustSet(items).At("items")
+	v.MustSet(200).At("code")
+	return v.MustMarshal()
+}()
+
+func Benchmark_Unmarshal_Large_Jsonvalue(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		v, _ := jsonvalue.Unmarshal(largeText)
+		_, _ = v.GetString("request_id")
+		_, _ = v.GetInt("code")
+	}
+}
+
+func Benchmark_Unmarshal_Large_JsonvalueLazy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		v, _ := jsonvalue.UnmarshalLazy(largeText)
+		_, _ = v.GetString("request_id")
+		_, _ = v.GetInt("code")
+	}
+}
+
+func Benchmark_Unmarshal_Large_JsonvaluePaths(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		v, _ := jsonvalue.UnmarshalPaths(largeText, "request_id", "code")
+		_, _ = v.GetString("request_id")
+		_, _ = v.GetInt("code")
+	}
+}
@@ -33,7 +33,7 @@ func (v *V) RangeObjects(callback func(k string, v *V) bool) {
 		return
 	}
 
-	for k, c := range v.children.object {
+	for k, c := range v.loadedChildren().object {
 		ok := callback(k, c.v)
 		if !ok {
 			break
@@ -60,8 +60,9 @@ func (v *V) RangeObjectsBySetSequence(callback func(k string, v *V) bool) {
 		v  *V
 	}
 
-	kvs := make([]keysAndID, 0, len(v.children.object))
-	for k, child := range v.children.object {
+	c := v.loadedChildren()
+	kvs := make([]keysAndID, 0, len(c.object))
+	for k, child := range c.object {
 		kvs = append(kvs, keysAndID{
 			k:  k,
 			id: child.id,
@@ -82,10 +83,11 @@ func (v *V) RangeObjectsBySetSequence(callback func(k string, v *V) bool) {
 
 // Deprecated: IterObjects is deprecated, please Use ForRangeObj() instead.
 func (v *V) IterObjects() <-chan *ObjectIter {
-	ch := make(chan *ObjectIter, len(v.children.object))
+	object := v.loadedChildren().object
+	ch := make(chan *ObjectIter, len(object))
 
 	go func() {
-		for k, c := range v.children.object {
+		for k, c := range object {
 			ch <- &ObjectIter{
 				K: k,
 				V: c.v,
@@ -100,8 +102,9 @@ func (v *V) IterObjects() <-chan *ObjectIter {
 //
 // ForRangeObj 返回一个 map 类型，用于使用 for - range 块迭代 JSON 对象类型的子成员。
 func (v *V) ForRangeObj() map[string]*V {
-	res := make(map[string]*V, len(v.children.object))
-	for k, c := range v.children.object {
+	object := v.loadedChildren().object
+	res := make(map[string]*V, len(object))
+	for k, c := range object {
 		res[k] = c.v
 	}
 	return res
@@ -122,7 +125,7 @@ func (v *V) RangeArray(callback func(i int, v *V) bool) {
 		return
 	}
 
-	for i, child := range v.children.arr {
+	for i, child := range v.loadedChildren().arr {
 		if ok := callback(i, child); !ok {
 			break
 		}
@@ -131,10 +134,11 @@ func (v *V) RangeArray(callback func(i int, v *V) bool) {
 
 // Deprecated: IterArray is deprecated, please Use ForRangeArr() instead.
 func (v *V) IterArray() <-chan *ArrayIter {
-	c := make(chan *ArrayIter, len(v.children.arr))
+	arr := v.loadedChildren().arr
+	c := make(chan *ArrayIter, len(arr))
 
 	go func() {
-		for i, child := range v.children.arr {
+		for i, child := range arr {
 			c <- &ArrayIter{
 				I: i,
 				V: child,
@@ -149,8 +153,9 @@ func (v *V) IterArray() <-chan *ArrayIter {
 //
 // ForRangeObj 返回一个切片，用于使用 for - range 块迭代 JSON 数组类型的子成员。
 func (v *V) ForRangeArr() []*V {
-	res := make([]*V, 0, len(v.children.arr))
-	return append(res, v.children.arr...)
+	c := v.loadedChildren()
+	res := make([]*V, 0, len(c.arr))
+	return append(res, c.arr...)
 }
 
 // PathItem is used for
@@ -0,0 +1,1046 @@
+package jsonvalue
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ================ JSONPATH ================
+
+// Reference: [RFC 9535 - JSONPath: Query Expressions for JSON](https://www.rfc-editor.org/rfc/rfc9535)
+
+// JSONPathMatch is a value selected by a JSONPath query, along with its path
+// from the queried value.
+//
+// JSONPathMatch 表示一个被 JSONPath 查询选中的值, 以及该值相对于被查询值的路径。
+type JSONPathMatch struct {
+	Path Path
+	V    *V
+}
+
+// JSONPath is a compiled JSONPath (RFC 9535) expression, which could be used
+// to query multiple JSON values repeatedly.
+//
+// JSONPath 表示一个已编译的 JSONPath (RFC 9535) 表达式, 可以重复用于查询多个 JSON 值。
+type JSONPath struct {
+	expr     string
+	segments []jsonPathSegment
+}
+
+// CompileJSONPath parses a JSONPath expression such as
+// `$.items[?(@.price > 10)].name`, `$..id` or `$.list[1:5:2]`.
+//
+// Function extensions length(), count(), match(), search() and value() defined
+// in RFC 9535 are supported. Regular expressions in match() and search() are
+// interpreted by Go regexp package.
+//
+// CompileJSONPath 解析一个 JSONPath 表达式, 如 `$.items[?(@.price > 10)].name`, `$..id`
+// 或 `$.list[1:5:2]`。
+//
+// 支持 RFC 9535 所定义的 length(), count(), match(), search() 和 value() 函数扩展。其中
+// match() 和 search() 中的正则表达式使用 Go 的 regexp 包解析。
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	p := jsonPathParser{s: expr}
+	if p.peek() != '$' {
+		return nil, p.errorf("a JSONPath expression should start with '$'")
+	}
+	p.pos++
+
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected character %q", p.s[p.pos])
+	}
+	return &JSONPath{
+		expr:     expr,
+		segments: segments,
+	}, nil
+}
+
+// String returns the original JSONPath expression.
+//
+// String 返回原始的 JSONPath 表达式。
+func (jp *JSONPath) String() string {
+	return jp.expr
+}
+
+// Find returns all values in v selected by the JSONPath, in document order.
+// For an object, children are visited in the sequence when they are set.
+//
+// Find 按照文档顺序返回 v 中所有被该 JSONPath 选中的值。对于 object, 子成员的遍历顺序为其被
+// set 的顺序。
+func (jp *JSONPath) Find(v *V) []JSONPathMatch {
+	if v == nil || v.valueType == NotExist {
+		return nil
+	}
+	nodes := evalJSONPathSegments(jp.segments, v, []jsonPathNode{{v: v}}, true)
+	res := make([]JSONPathMatch, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, JSONPathMatch{Path: n.path, V: n.v})
+	}
+	return res
+}
+
+// JSONPath queries current value with given JSONPath (RFC 9535) expression and
+// returns all matched values with their paths. Please refer to CompileJSONPath
+// for supported syntax.
+//
+// JSONPath 使用给定的 JSONPath (RFC 9535) 表达式查询当前值, 并返回所有匹配的值及其路径。支持的
+// 语法请参见 CompileJSONPath。
+func (v *V) JSONPath(expr string) ([]JSONPathMatch, error) {
+	jp, err := CompileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return jp.Find(v), nil
+}
+
+// ---------------- AST ----------------
+
+type jsonPathSegment struct {
+	descendant bool
+	selectors  []*jsonPathSelector
+}
+
+type jsonPathSelectorType int
+
+const (
+	jsonPathNameSelector jsonPathSelectorType = iota
+	jsonPathWildcardSelector
+	jsonPathIndexSelector
+	jsonPathSliceSelector
+	jsonPathFilterSelector
+)
+
+type jsonPathSelector struct {
+	typ   jsonPathSelectorType
+	name  string
+	index int
+
+	// slice parameters
+	start, end, step          int
+	hasStart, hasEnd, hasStep bool
+
+	filter jsonPathLogical
+}
+
+// jsonPathQuery is a query embedded in filter expressions, starting with '@'
+// or '$'.
+type jsonPathQuery struct {
+	relative bool
+	segments []jsonPathSegment
+}
+
+func (q *jsonPathQuery) isSingular() bool {
+	for _, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		switch seg.selectors[0].typ {
+		case jsonPathNameSelector, jsonPathIndexSelector:
+			// OK
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (q *jsonPathQuery) eval(root, current *V) []jsonPathNode {
+	start := root
+	if q.relative {
+		start = current
+	}
+	return evalJSONPathSegments(q.segments, root, []jsonPathNode{{v: start}}, false)
+}
+
+// jsonPathLogical is a logical expression in filter selectors.
+type jsonPathLogical interface {
+	test(root, current *V) bool
+}
+
+type jsonPathOr []jsonPathLogical
+
+func (e jsonPathOr) test(root, current *V) bool {
+	for _, sub := range e {
+		if sub.test(root, current) {
+			return true
+		}
+	}
+	return false
+}
+
+type jsonPathAnd []jsonPathLogical
+
+func (e jsonPathAnd) test(root, current *V) bool {
+	for _, sub := range e {
+		if !sub.test(root, current) {
+			return false
+		}
+	}
+	return true
+}
+
+type jsonPathNot struct {
+	jsonPathLogical
+}
+
+func (e jsonPathNot) test(root, current *V) bool {
+	return !e.jsonPathLogical.test(root, current)
+}
+
+// jsonPathExistence tests whether a query selects at least one node.
+type jsonPathExistence struct {
+	query *jsonPathQuery
+}
+
+func (e jsonPathExistence) test(root, current *V) bool {
+	return len(e.query.eval(root, current)) > 0
+}
+
+// jsonPathFunctionTest tests a function returning logical type.
+type jsonPathFunctionTest struct {
+	fn *jsonPathFunction
+}
+
+func (e jsonPathFunctionTest) test(root, current *V) bool {
+	return e.fn.test(root, current)
+}
+
+type jsonPathComparison struct {
+	op          string
+	left, right *jsonPathOperand
+}
+
+func (e jsonPathComparison) test(root, current *V) bool {
+	left, lok := e.left.value(root, current)
+	right, rok := e.right.value(root, current)
+
+	switch e.op {
+	default: // "=="
+		return jsonPathEqual(left, lok, right, rok)
+	case "!=":
+		return !jsonPathEqual(left, lok, right, rok)
+	case "<":
+		return jsonPathLess(left, lok, right, rok)
+	case "<=":
+		return jsonPathLess(left, lok, right, rok) || jsonPathEqual(left, lok, right, rok)
+	case ">":
+		return jsonPathLess(right, rok, left, lok)
+	case ">=":
+		return jsonPathLess(right, rok, left, lok) || jsonPathEqual(left, lok, right, rok)
+	}
+}
+
+func jsonPathEqual(left *V, lok bool, right *V, rok bool) bool {
+	if !lok || !rok {
+		return !lok && !rok
+	}
+	return left.Equal(right)
+}
+
+func jsonPathLess(left *V, lok bool, right *V, rok bool) bool {
+	if !lok || !rok {
+		return false
+	}
+	if left.valueType == Number && right.valueType == Number {
+		res, _ := greaterThan(right, left)
+		return res
+	}
+	if left.valueType == String && right.valueType == String {
+		return left.valueStr < right.valueStr
+	}
+	return false
+}
+
+// jsonPathOperand is one of literal, query or function.
+type jsonPathOperand struct {
+	literal *V
+	query   *jsonPathQuery
+	fn      *jsonPathFunction
+}
+
+// value returns the value of operand. False is returned if the operand
+// results in Nothing.
+func (o *jsonPathOperand) value(root, current *V) (*V, bool) {
+	switch {
+	case o.literal != nil:
+		return o.literal, true
+	case o.query != nil:
+		nodes := o.query.eval(root, current)
+		if len(nodes) != 1 {
+			return nil, false
+		}
+		return nodes[0].v, true
+	default:
+		return o.fn.value(root, current)
+	}
+}
+
+type jsonPathFunction struct {
+	name string
+	args []*jsonPathOperand
+	re   *regexp.Regexp // pre-compiled regular expression for literal pattern
+}
+
+func (f *jsonPathFunction) isLogical() bool {
+	return f.name == "match" || f.name == "search"
+}
+
+func (f *jsonPathFunction) value(root, current *V) (*V, bool) {
+	switch f.name {
+	default: // "length"
+		v, ok := f.args[0].value(root, current)
+		if !ok {
+			return nil, false
+		}
+		switch v.valueType {
+		case String:
+			return NewInt(utf8.RuneCountInString(v.valueStr)), true
+		case Object, Array:
+			return NewInt(v.Len()), true
+		default:
+			return nil, false
+		}
+	case "count":
+		return NewInt(len(f.args[0].query.eval(root, current))), true
+	case "value":
+		return f.args[0].value(root, current)
+	}
+}
+
+func (f *jsonPathFunction) test(root, current *V) bool {
+	s, ok := f.args[0].value(root, current)
+	if !ok || s.valueType != String {
+		return false
+	}
+	re := f.re
+	if re == nil {
+		pattern, ok := f.args[1].value(root, current)
+		if !ok || pattern.valueType != String {
+			return false
+		}
+		var err error
+		re, err = compileJSONPathRegexp(f.name, pattern.valueStr)
+		if err != nil {
+			return false
+		}
+	}
+	return re.MatchString(s.valueStr)
+}
+
+func compileJSONPathRegexp(fn, pattern string) (*regexp.Regexp, error) {
+	if fn == "match" {
+		pattern = "^(?:" + pattern + ")$"
+	}
+	return regexp.Compile(pattern)
+}
+
+// ---------------- evaluation ----------------
+
+type jsonPathNode struct {
+	path Path
+	v    *V
+}
+
+func (n jsonPathNode) child(item PathItem, v *V, withPath bool) jsonPathNode {
+	if !withPath {
+		return jsonPathNode{v: v}
+	}
+	path := make(Path, len(n.path), len(n.path)+1)
+	copy(path, n.path)
+	return jsonPathNode{
+		path: append(path, item),
+		v:    v,
+	}
+}
+
+func (n jsonPathNode) children(withPath bool) []jsonPathNode {
+	switch n.v.valueType {
+	case Object:
+		res := make([]jsonPathNode, 0, len(n.v.loadedChildren().object))
+		n.v.RangeObjectsBySetSequence(func(k string, c *V) bool {
+			res = append(res, n.child(PathItem{Idx: -1, Key: k}, c, withPath))
+			return true
+		})
+		return res
+	case Array:
+		arr := n.v.loadedChildren().arr
+		res := make([]jsonPathNode, 0, len(arr))
+		for i, c := range arr {
+			res = append(res, n.child(PathItem{Idx: i}, c, withPath))
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+func (n jsonPathNode) descendants(withPath bool, res []jsonPathNode) []jsonPathNode {
+	res = append(res, n)
+	for _, c := range n.children(withPath) {
+		res = c.descendants(withPath, res)
+	}
+	return res
+}
+
+func evalJSONPathSegments(segments []jsonPathSegment, root *V, nodes []jsonPathNode, withPath bool) []jsonPathNode {
+	for _, seg := range segments {
+		var next []jsonPathNode
+		for _, n := range nodes {
+			if !seg.descendant {
+				next = seg.apply(root, n, withPath, next)
+				continue
+			}
+			for _, d := range n.descendants(withPath, nil) {
+				next = seg.apply(root, d, withPath, next)
+			}
+		}
+		nodes = next
+		if len(nodes) == 0 {
+			break
+		}
+	}
+	return nodes
+}
+
+func (seg jsonPathSegment) apply(root *V, n jsonPathNode, withPath bool, res []jsonPathNode) []jsonPathNode {
+	for _, sel := range seg.selectors {
+		res = sel.apply(root, n, withPath, res)
+	}
+	return res
+}
+
+func (sel *jsonPathSelector) apply(root *V, n jsonPathNode, withPath bool, res []jsonPathNode) []jsonPathNode {
+	v := n.v
+	switch sel.typ {
+	default: // jsonPathNameSelector
+		if v.valueType != Object {
+			return res
+		}
+		if c, exist := getFromObjectChildren(v, false, sel.name); exist {
+			res = append(res, n.child(PathItem{Idx: -1, Key: sel.name}, c, withPath))
+		}
+		return res
+
+	case jsonPathWildcardSelector:
+		return append(res, n.children(withPath)...)
+
+	case jsonPathIndexSelector:
+		if v.valueType != Array {
+			return res
+		}
+		if pos := posAtIndexForRead(v, sel.index); pos >= 0 {
+			res = append(res, n.child(PathItem{Idx: pos}, v.loadedChildren().arr[pos], withPath))
+		}
+		return res
+
+	case jsonPathSliceSelector:
+		if v.valueType != Array {
+			return res
+		}
+		arr := v.loadedChildren().arr
+		for _, pos := range sel.sliceIndexes(len(arr)) {
+			res = append(res, n.child(PathItem{Idx: pos}, arr[pos], withPath))
+		}
+		return res
+
+	case jsonPathFilterSelector:
+		for _, c := range n.children(withPath) {
+			if sel.filter.test(root, c.v) {
+				res = append(res, c)
+			}
+		}
+		return res
+	}
+}
+
+// sliceIndexes returns selected indexes of an array with given length, as
+// described in RFC 9535 section 2.3.4.2.2.
+func (sel *jsonPathSelector) sliceIndexes(le int) []int {
+	step := 1
+	if sel.hasStep {
+		step = sel.step
+	}
+	if step == 0 {
+		return nil
+	}
+
+	normalize := func(i int) int {
+		if i >= 0 {
+			return i
+		}
+		return le + i
+	}
+	bound := func(i, lower, upper int) int {
+		if i < lower {
+			return lower
+		}
+		if i > upper {
+			return upper
+		}
+		return i
+	}
+
+	var res []int
+	if step > 0 {
+		start, end := 0, le
+		if sel.hasStart {
+			start = normalize(sel.start)
+		}
+		if sel.hasEnd {
+			end = normalize(sel.end)
+		}
+		lower, upper := bound(start, 0, le), bound(end, 0, le)
+		for i := lower; i < upper; i += step {
+			res = append(res, i)
+		}
+		return res
+	}
+
+	start, end := le-1, -le-1
+	if sel.hasStart {
+		start = normalize(sel.start)
+	}
+	if sel.hasEnd {
+		end = normalize(sel.end)
+	}
+	upper, lower := bound(start, -1, le-1), bound(end, -1, le-1)
+	for i := upper; lower < i; i += step {
+		res = append(res, i)
+	}
+	return res
+}
+
+// ---------------- parser ----------------
+
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+func (p *jsonPathParser) errorf(f string, a ...any) error {
+	return fmt.Errorf("%w, %s at position %d", ErrInvalidJSONPath, fmt.Sprintf(f, a...), p.pos)
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(p.s[p.pos:], prefix)
+}
+
+func (p *jsonPathParser) skipBlanks() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonPathParser) expect(chr byte) error {
+	if p.peek() != chr {
+		return p.errorf("expecting %q", chr)
+	}
+	p.pos++
+	return nil
+}
+
+// parseSegments parses segments until a character which could not start a
+// segment is met.
+func (p *jsonPathParser) parseSegments() ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	for {
+		pos := p.pos
+		p.skipBlanks()
+		switch p.peek() {
+		default:
+			p.pos = pos
+			return segments, nil
+		case '.', '[':
+			seg, err := p.parseSegment()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSegment() (jsonPathSegment, error) {
+	seg := jsonPathSegment{}
+	if p.hasPrefix("..") {
+		seg.descendant = true
+		p.pos += 2
+	} else if p.peek() == '.' {
+		p.pos++
+	} else {
+		sels, err := p.parseBracketedSelection()
+		seg.selectors = sels
+		return seg, err
+	}
+
+	switch chr := p.peek(); {
+	case chr == '[' && seg.descendant:
+		sels, err := p.parseBracketedSelection()
+		seg.selectors = sels
+		return seg, err
+	case chr == '*':
+		p.pos++
+		seg.selectors = []*jsonPathSelector{{typ: jsonPathWildcardSelector}}
+		return seg, nil
+	case isJSONPathNameFirst(chr):
+		start := p.pos
+		for p.pos < len(p.s) && isJSONPathNameChar(p.s[p.pos]) {
+			p.pos++
+		}
+		seg.selectors = []*jsonPathSelector{{typ: jsonPathNameSelector, name: p.s[start:p.pos]}}
+		return seg, nil
+	default:
+		return seg, p.errorf("expecting member name or '*'")
+	}
+}
+
+func isJSONPathNameFirst(chr byte) bool {
+	return chr == '_' || (chr >= 'a' && chr <= 'z') || (chr >= 'A' && chr <= 'Z') || chr >= 0x80
+}
+
+func isJSONPathNameChar(chr byte) bool {
+	return isJSONPathNameFirst(chr) || (chr >= '0' && chr <= '9')
+}
+
+func (p *jsonPathParser) parseBracketedSelection() ([]*jsonPathSelector, error) {
+	p.pos++ // '['
+	var sels []*jsonPathSelector
+	for {
+		p.skipBlanks()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+
+		p.skipBlanks()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return sels, nil
+		default:
+			return nil, p.errorf("expecting ',' or ']'")
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (*jsonPathSelector, error) {
+	switch chr := p.peek(); {
+	case chr == '\'' || chr == '"':
+		s, err := p.parseStringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathSelector{typ: jsonPathNameSelector, name: s}, nil
+
+	case chr == '*':
+		p.pos++
+		return &jsonPathSelector{typ: jsonPathWildcardSelector}, nil
+
+	case chr == '?':
+		p.pos++
+		filter, err := p.parseLogicalOr()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathSelector{typ: jsonPathFilterSelector, filter: filter}, nil
+
+	case chr == '-' || chr == ':' || (chr >= '0' && chr <= '9'):
+		return p.parseIndexOrSlice()
+
+	default:
+		return nil, p.errorf("illegal selector")
+	}
+}
+
+func (p *jsonPathParser) parseIndexOrSlice() (*jsonPathSelector, error) {
+	sel := &jsonPathSelector{typ: jsonPathSliceSelector}
+	var err error
+
+	sel.start, sel.hasStart, err = p.parseOptionalInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlanks()
+	if p.peek() != ':' {
+		if !sel.hasStart {
+			return nil, p.errorf("expecting an index")
+		}
+		return &jsonPathSelector{typ: jsonPathIndexSelector, index: sel.start}, nil
+	}
+
+	p.pos++
+	p.skipBlanks()
+	sel.end, sel.hasEnd, err = p.parseOptionalInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlanks()
+	if p.peek() != ':' {
+		return sel, nil
+	}
+
+	p.pos++
+	p.skipBlanks()
+	sel.step, sel.hasStep, err = p.parseOptionalInt()
+	return sel, err
+}
+
+func (p *jsonPathParser) parseOptionalInt() (int, bool, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	digitStart := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+
+	digits := p.s[digitStart:p.pos]
+	switch {
+	case digits == "":
+		return 0, false, p.errorf("expecting digits")
+	case len(digits) > 1 && digits[0] == '0':
+		return 0, false, p.errorf("integer should not start with zero")
+	case digits == "0" && digitStart > start:
+		return 0, false, p.errorf("'-0' is not allowed")
+	}
+
+	i, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		return 0, false, p.errorf("integer out of range")
+	}
+	return i, true, nil
+}
+
+// parseStringLiteral parses a single or double quoted string literal. The
+// literal is converted into a JSON string and parsed by the JSON parser.
+func (p *jsonPathParser) parseStringLiteral() (string, error) {
+	quote := p.s[p.pos]
+	start := p.pos
+
+	buf := strings.Builder{}
+	buf.WriteByte('"')
+	for i := p.pos + 1; i < len(p.s); i++ {
+		chr := p.s[i]
+		switch {
+		case chr == quote:
+			buf.WriteByte('"')
+			p.pos = i + 1
+			v, err := UnmarshalString(buf.String())
+			if err != nil {
+				p.pos = start
+				return "", p.errorf("illegal string literal")
+			}
+			return v.valueStr, nil
+
+		case chr == '\\':
+			if i+1 == len(p.s) {
+				break
+			}
+			i++
+			if p.s[i] == '\'' {
+				buf.WriteByte('\'')
+			} else {
+				buf.WriteByte('\\')
+				buf.WriteByte(p.s[i])
+			}
+
+		case chr == '"':
+			buf.WriteString(`\"`)
+
+		case chr < 0x20:
+			p.pos = i
+			return "", p.errorf("control character in string literal")
+
+		default:
+			buf.WriteByte(chr)
+		}
+	}
+	return "", p.errorf("string literal is not terminated")
+}
+
+func (p *jsonPathParser) parseLogicalOr() (jsonPathLogical, error) {
+	var or jsonPathOr
+	for {
+		and, err := p.parseLogicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, and)
+
+		p.skipBlanks()
+		if !p.hasPrefix("||") {
+			break
+		}
+		p.pos += 2
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *jsonPathParser) parseLogicalAnd() (jsonPathLogical, error) {
+	var and jsonPathAnd
+	for {
+		basic, err := p.parseBasicLogical()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, basic)
+
+		p.skipBlanks()
+		if !p.hasPrefix("&&") {
+			break
+		}
+		p.pos += 2
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *jsonPathParser) parseBasicLogical() (jsonPathLogical, error) {
+	p.skipBlanks()
+
+	switch p.peek() {
+	case '!':
+		p.pos++
+		p.skipBlanks()
+		if p.peek() == '(' {
+			e, err := p.parseParenLogical()
+			return jsonPathNot{e}, err
+		}
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		e, err := p.testExpression(operand)
+		return jsonPathNot{e}, err
+
+	case '(':
+		return p.parseParenLogical()
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlanks()
+	op := p.parseComparisonOperator()
+	if op == "" {
+		return p.testExpression(left)
+	}
+	if err := p.checkComparable(left); err != nil {
+		return nil, err
+	}
+
+	p.skipBlanks()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkComparable(right); err != nil {
+		return nil, err
+	}
+	return jsonPathComparison{op: op, left: left, right: right}, nil
+}
+
+func (p *jsonPathParser) parseParenLogical() (jsonPathLogical, error) {
+	p.pos++ // '('
+	e, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlanks()
+	return e, p.expect(')')
+}
+
+func (p *jsonPathParser) parseComparisonOperator() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.hasPrefix(op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *jsonPathParser) testExpression(o *jsonPathOperand) (jsonPathLogical, error) {
+	switch {
+	case o.query != nil:
+		return jsonPathExistence{query: o.query}, nil
+	case o.fn != nil && o.fn.isLogical():
+		return jsonPathFunctionTest{fn: o.fn}, nil
+	default:
+		return nil, p.errorf("expecting a query or logical function as test expression")
+	}
+}
+
+func (p *jsonPathParser) checkComparable(o *jsonPathOperand) error {
+	switch {
+	case o.query != nil && !o.query.isSingular():
+		return p.errorf("only singular queries could be compared")
+	case o.fn != nil && o.fn.isLogical():
+		return p.errorf("result of %s() could not be compared", o.fn.name)
+	default:
+		return nil
+	}
+}
+
+func (p *jsonPathParser) parseOperand() (*jsonPathOperand, error) {
+	switch chr := p.peek(); {
+	case chr == '@' || chr == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		q := &jsonPathQuery{relative: chr == '@', segments: segments}
+		return &jsonPathOperand{query: q}, nil
+
+	case chr == '\'' || chr == '"':
+		s, err := p.parseStringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathOperand{literal: NewString(s)}, nil
+
+	case chr == '-' || (chr >= '0' && chr <= '9'):
+		return p.parseNumberLiteral()
+
+	case chr >= 'a' && chr <= 'z':
+		start := p.pos
+		for p.pos < len(p.s) && (isJSONPathNameChar(p.s[p.pos]) && p.s[p.pos] < 0x80) {
+			p.pos++
+		}
+		name := p.s[start:p.pos]
+		if p.peek() == '(' {
+			return p.parseFunction(name)
+		}
+		switch name {
+		case "true":
+			return &jsonPathOperand{literal: NewBool(true)}, nil
+		case "false":
+			return &jsonPathOperand{literal: NewBool(false)}, nil
+		case "null":
+			return &jsonPathOperand{literal: NewNull()}, nil
+		}
+		p.pos = start
+		return nil, p.errorf("unknown literal %q", name)
+
+	default:
+		return nil, p.errorf("expecting a literal, query or function")
+	}
+}
+
+func (p *jsonPathParser) parseNumberLiteral() (*jsonPathOperand, error) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		chr := p.s[p.pos]
+		if (chr >= '0' && chr <= '9') || chr == '-' || chr == '+' || chr == '.' || chr == 'e' || chr == 'E' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	v, err := UnmarshalString(p.s[start:p.pos])
+	if err != nil || v.valueType != Number {
+		p.pos = start
+		return nil, p.errorf("illegal number literal")
+	}
+	return &jsonPathOperand{literal: v}, nil
+}
+
+var jsonPathFunctionArgCount = map[string]int{
+	"length": 1,
+	"count":  1,
+	"value":  1,
+	"match":  2,
+	"search": 2,
+}
+
+func (p *jsonPathParser) parseFunction(name string) (*jsonPathOperand, error) {
+	argCount, exist := jsonPathFunctionArgCount[name]
+	if !exist {
+		return nil, p.errorf("unknown function %s()", name)
+	}
+	p.pos++ // '('
+
+	fn := &jsonPathFunction{name: name}
+	for {
+		p.skipBlanks()
+		if p.peek() == ')' && len(fn.args) == 0 {
+			break
+		}
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		fn.args = append(fn.args, arg)
+
+		p.skipBlanks()
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+
+	if len(fn.args) != argCount {
+		return nil, p.errorf("%s() requires %d argument(s) but %d given", name, argCount, len(fn.args))
+	}
+
+	for i, arg := range fn.args {
+		switch {
+		case name == "count" || name == "value":
+			if arg.query == nil {
+				return nil, p.errorf("argument of %s() should be a query", name)
+			}
+		case arg.query != nil && !arg.query.isSingular():
+			return nil, p.errorf("argument %d of %s() should be a singular query", i+1, name)
+		case arg.fn != nil && arg.fn.isLogical():
+			return nil, p.errorf("argument %d of %s() should not be a logical function", i+1, name)
+		}
+	}
+
+	if fn.isLogical() {
+		if pattern := fn.args[1].literal; pattern != nil && pattern.valueType == String {
+			re, err := compileJSONPathRegexp(name, pattern.valueStr)
+			if err != nil {
+				return nil, p.errorf("illegal regular expression %q", pattern.valueStr)
+			}
+			fn.re = re
+		}
+	}
+	return &jsonPathOperand{fn: fn}, nil
+}
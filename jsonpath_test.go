@@ -0,0 +1,299 @@
+package jsonvalue
+
+import (
+	"errors"
+	"testing"
+)
+
+func testJSONPath(t *testing.T) {
+	cv("basic selectors", func() { testJSONPathBasicSelectors(t) })
+	cv("slice selectors", func() { testJSONPathSlice(t) })
+	cv("descendant segments", func() { testJSONPathDescendant(t) })
+	cv("filter selectors", func() { testJSONPathFilter(t) })
+	cv("function extensions", func() { testJSONPathFunctions(t) })
+	cv("invalid expressions", func() { testJSONPathInvalid(t) })
+}
+
+const jsonPathTestData = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95, "id": 1},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99, "id": 2},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99, "id": 3},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99, "id": 4}
+		],
+		"bicycle": {"color": "red", "price": 399, "id": 5}
+	},
+	"a b": "space",
+	"it's": "quote"
+}`
+
+func jsonPathStrings(res []JSONPathMatch) []string {
+	ss := make([]string, 0, len(res))
+	for _, r := range res {
+		ss = append(ss, r.V.MustMarshalString())
+	}
+	return ss
+}
+
+func jsonPathPointers(res []JSONPathMatch) []string {
+	ss := make([]string, 0, len(res))
+	for _, r := range res {
+		ss = append(ss, r.Path.Pointer())
+	}
+	return ss
+}
+
+func testJSONPathBasicSelectors(t *testing.T) {
+	v := MustUnmarshalString(jsonPathTestData)
+
+	res, err := v.JSONPath("$")
+	so(err, isNil)
+	so(len(res), eq, 1)
+	so(res[0].V, eq, v)
+	so(len(res[0].Path), eq, 0)
+
+	res, err = v.JSONPath("$.store.book[0].title")
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`"Sayings of the Century"`})
+	so(jsonPathPointers(res), resemble, []string{"/store/book/0/title"})
+
+	res, err = v.JSONPath(`$['store']["bicycle"].color`)
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`"red"`})
+
+	res, err = v.JSONPath(`$['a b', 'it\'s', "not exist"]`)
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`"space"`, `"quote"`})
+
+	res, err = v.JSONPath(`$.store.book[-1].id`)
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`4`})
+	so(jsonPathPointers(res), resemble, []string{"/store/book/3/id"})
+
+	res, err = v.JSONPath(`$.store.book[0, 2, 10].id`)
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`1`, `3`})
+
+	res, err = v.JSONPath(`$.store.bicycle.*`)
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`"red"`, `399`, `5`})
+
+	res, err = v.JSONPath(`$.store.book[*].author`)
+	so(err, isNil)
+	so(len(res), eq, 4)
+
+	// type mismatches select nothing
+	res, err = v.JSONPath(`$.store.book.title`)
+	so(err, isNil)
+	so(len(res), eq, 0)
+	res, err = v.JSONPath(`$.store[0]`)
+	so(err, isNil)
+	so(len(res), eq, 0)
+
+	// compiled expression
+	jp, err := CompileJSONPath(" $.store.bicycle.id")
+	so(err, isErr)
+	so(jp, isNil)
+	jp, err = CompileJSONPath("$ .store .bicycle .id")
+	so(err, isNil)
+	so(jp.String(), eq, "$ .store .bicycle .id")
+	so(jsonPathStrings(jp.Find(v)), resemble, []string{`5`})
+	so(len(jp.Find(nil)), eq, 0)
+	so(len(jp.Find(&V{})), eq, 0)
+}
+
+func testJSONPathSlice(t *testing.T) {
+	v := MustUnmarshalString(`[0,1,2,3,4,5,6,7,8,9]`)
+	check := func(expr, expected string) {
+		res, err := v.JSONPath(expr)
+		so(err, isNil)
+		arr := NewArray()
+		for _, r := range res {
+			arr.MustAppend(r.V).InTheEnd()
+		}
+		so(arr.MustMarshalString(), eq, expected)
+	}
+
+	check("$[1:5:2]", "[1,3]")
+	check("$[1:3]", "[1,2]")
+	check("$[:3]", "[0,1,2]")
+	check("$[7:]", "[7,8,9]")
+	check("$[-2:]", "[8,9]")
+	check("$[::3]", "[0,3,6,9]")
+	check("$[5:1:-2]", "[5,3]")
+	check("$[::-1]", "[9,8,7,6,5,4,3,2,1,0]")
+	check("$[ 1 : 3 ]", "[1,2]")
+	check("$[::0]", "[]")
+	check("$[100:]", "[]")
+	check("$[-100:2]", "[0,1]")
+
+	res, err := v.JSONPath("$[-3::-1]")
+	so(err, isNil)
+	so(jsonPathPointers(res), resemble, []string{"/7", "/6", "/5", "/4", "/3", "/2", "/1", "/0"})
+}
+
+func testJSONPathDescendant(t *testing.T) {
+	v := MustUnmarshalString(jsonPathTestData)
+
+	res, err := v.JSONPath("$..id")
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`1`, `2`, `3`, `4`, `5`})
+	so(jsonPathPointers(res), resemble, []string{
+		"/store/book/0/id", "/store/book/1/id", "/store/book/2/id", "/store/book/3/id", "/store/bicycle/id",
+	})
+
+	res, err = v.JSONPath("$..book[-1].title")
+	so(err, isNil)
+	so(jsonPathStrings(res), resemble, []string{`"The Lord of the Rings"`})
+
+	res, err = v.JSONPath("$.store..*")
+	so(err, isNil)
+	so(len(res), eq, 2+4+4*5+2+3)
+
+	res, err = v.JSONPath("$..['price','color']")
+	so(err, isNil)
+	so(len(res), eq, 6)
+}
+
+func testJSONPathFilter(t *testing.T) {
+	v := MustUnmarshalString(`{"items":[
+		{"name":"a","price":5,"tags":["x"]},
+		{"name":"b","price":10.0,"tags":[]},
+		{"name":"c","price":15},
+		{"name":"d","price":"20"},
+		{"name":"e","price":1e2,"on_sale":true},
+		{"name":"f","price":null}
+	], "limit": 10}`)
+
+	check := func(expr string, names ...string) {
+		res, err := v.JSONPath(expr)
+		so(err, isNil)
+		got := make([]string, 0, len(res))
+		for _, r := range res {
+			got = append(got, r.V.String())
+		}
+		if len(names) == 0 {
+			names = []string{}
+		}
+		so(got, resemble, names)
+	}
+
+	check(`$.items[?(@.price > 10)].name`, "c", "e")
+	check(`$.items[?@.price >= 10].name`, "b", "c", "e")
+	check(`$.items[?@.price == 10].name`, "b")
+	check(`$.items[?@.price == $.limit].name`, "b")
+	check(`$.items[?@.price < 10].name`, "a")
+	check(`$.items[?@.price <= 1.0e1].name`, "a", "b")
+	check(`$.items[?@.price != 10].name`, "a", "c", "d", "e", "f")
+	check(`$.items[?@.price == null].name`, "f")
+	check(`$.items[?@.price == '20'].name`, "d")
+	check(`$.items[?@.price > '1'].name`, "d")
+	check(`$.items[?@.on_sale].name`, "e")
+	check(`$.items[?!@.on_sale].name`, "a", "b", "c", "d", "f")
+	check(`$.items[?@.on_sale == true].name`, "e")
+	check(`$.items[?@.tags[0] == 'x'].name`, "a")
+	check(`$.items[?@.tags].name`, "a", "b")
+	check(`$.items[?@.price > 5 && @.price < 20 || @.name == 'a'].name`, "a", "b", "c")
+	check(`$.items[?(@.price > 5 || @.name == 'a') && !(@.price == 15)].name`, "a", "b", "e")
+	check(`$.items[?@.missing == @.another].name`, "a", "b", "c", "d", "e", "f")
+	check(`$.items[?@.missing < @.another].name`)
+	check(`$.items[?@ == 1].name`)
+	check(`$.items[?@.name == "c"]["name"]`, "c")
+	check(`$..[?@.name == 'a'].price`, "5")
+
+	res, err := v.JSONPath(`$.items[?@.name == 'b']`)
+	so(err, isNil)
+	so(jsonPathPointers(res), resemble, []string{"/items/1"})
+
+	res, err = v.JSONPath(`$..[?@ == 'x']`)
+	so(err, isNil)
+	so(jsonPathPointers(res), resemble, []string{"/items/0/tags/0"})
+}
+
+func testJSONPathFunctions(t *testing.T) {
+	v := MustUnmarshalString(`[
+		{"name":"Alice","tags":["a","b","c"],"meta":{"x":1}},
+		{"name":"Bob","tags":["a"]},
+		{"name":"中文名","tags":[]}
+	]`)
+
+	check := func(expr string, names ...string) {
+		res, err := v.JSONPath(expr)
+		so(err, isNil)
+		got := make([]string, 0, len(res))
+		for _, r := range res {
+			got = append(got, r.V.String())
+		}
+		if len(names) == 0 {
+			names = []string{}
+		}
+		so(got, resemble, names)
+	}
+
+	check(`$[?length(@.name) == 3].name`, "Bob", "中文名")
+	check(`$[?length(@.tags) >= 1].name`, "Alice", "Bob")
+	check(`$[?length(@.meta) == 1].name`, "Alice")
+	check(`$[?count(@.tags[*]) == 3].name`, "Alice")
+	check(`$[?count(@..*) > 3].name`, "Alice")
+	check(`$[?value(@.tags[0]) == 'a'].name`, "Alice", "Bob")
+	check(`$[?match(@.name, 'B.b')].name`, "Bob")
+	check(`$[?match(@.name, 'B')].name`)
+	check(`$[?search(@.name, '[lo]')].name`, "Alice", "Bob")
+	check(`$[?search(@.name, $[0].name)].name`, "Alice")
+	check(`$[?!search(@.name, 'i')].name`, "Bob", "中文名")
+	check(`$[?search(@.tags, 'a')].name`)
+	check(`$[?search(@.name, @.tags)].name`)
+	check(`$[?search(@.name, $[0].bad_regexp)].name`)
+	check(`$[?length(1) == 1].name`)
+}
+
+func testJSONPathInvalid(t *testing.T) {
+	v := MustUnmarshalString(jsonPathTestData)
+
+	exprs := []string{
+		"",
+		"store",
+		"$.",
+		"$..",
+		"$.store.",
+		"$.1",
+		"$[",
+		"$[0",
+		"$[0,]",
+		"$['a'",
+		"$['a\\",
+		"$['\\x']",
+		"$[01]",
+		"$[-0]",
+		"$[-]",
+		"$[1:2:a]",
+		"$[99999999999999999999]",
+		"$[?]",
+		"$[?@.a ==]",
+		"$[?(@.a == 1]",
+		"$[?@.* == 1]",
+		"$[?@..a == 1]",
+		"$[?1]",
+		"$[?'a' == 1",
+		"$[?@.a == 1.]",
+		"$[?@.a == tru]",
+		"$[?foo(@.a)]",
+		"$[?length(@.a)]",
+		"$[?length(@.*) == 1]",
+		"$[?length(@.a, @.b) == 1]",
+		"$[?length() == 1]",
+		"$[?count(1) == 1]",
+		"$[?match(@.a, '(')]",
+		"$[?match(@.a, 'a') == true]",
+		"$[?length(match(@.a, 'a')) == 1]",
+		"$[?!1]",
+		"$ $",
+	}
+	for _, expr := range exprs {
+		res, err := v.JSONPath(expr)
+		t.Logf("%s - %v", expr, err)
+		so(errors.Is(err, ErrInvalidJSONPath), isTrue)
+		so(res, isNil)
+	}
+}
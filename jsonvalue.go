@@ -42,10 +42,13 @@ package jsonvalue
 
 import (
 	"bytes"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/Andrew-M-C/go.jsonvalue/internal/unsafe"
+	"github.com/shopspring/decimal"
 )
 
 // ValueType identifying JSON value type
@@ -130,6 +133,7 @@ type childWithProperty struct {
 
 type children struct {
 	incrID uint32
+	lazy   uint32 // non-zero if not generated yet, see loadLazy
 	arr    []*V
 	object map[string]childWithProperty
 
@@ -168,24 +172,26 @@ func (c *children) deepCopy() children {
 }
 
 func addCaselessKey(v *V, k string) {
-	if v.children.lowerCaseKeys == nil {
+	c := v.loadedChildren()
+	if c.lowerCaseKeys == nil {
 		return
 	}
 	lowerK := strings.ToLower(k)
-	keys, exist := v.children.lowerCaseKeys[lowerK]
+	keys, exist := c.lowerCaseKeys[lowerK]
 	if !exist {
 		keys = make(map[string]struct{})
-		v.children.lowerCaseKeys[lowerK] = keys
+		c.lowerCaseKeys[lowerK] = keys
 	}
 	keys[k] = struct{}{}
 }
 
 func delCaselessKey(v *V, k string) {
-	if v.children.lowerCaseKeys == nil {
+	c := v.loadedChildren()
+	if c.lowerCaseKeys == nil {
 		return
 	}
 	lowerK := strings.ToLower(k)
-	keys, exist := v.children.lowerCaseKeys[lowerK]
+	keys, exist := c.lowerCaseKeys[lowerK]
 	if !exist {
 		return
 	}
@@ -193,7 +199,7 @@ func delCaselessKey(v *V, k string) {
 	delete(keys, k)
 
 	if len(keys) == 0 {
-		delete(v.children.lowerCaseKeys, lowerK)
+		delete(c.lowerCaseKeys, lowerK)
 	}
 }
 
@@ -221,8 +227,10 @@ func UnmarshalString(s string) (*V, error) {
 	// 	Cap:  sh.Len,
 	// }
 	// b := *(*[]byte)(unsafe.Pointer(&bh))
-	b := []byte(s)
-	return UnmarshalNoCopy(b)
+	if len(s) == 0 {
+		return &V{}, ErrNilParameter
+	}
+	return unmarshalNoCopy([]byte(s), unsafe.StoB(s))
 }
 
 // MustUnmarshal just like Unmarshal(). If error occurs, a JSON value with "NotExist"
@@ -238,8 +246,12 @@ func MustUnmarshal(b []byte) *V {
 }
 
 // Unmarshal parse raw bytes(encoded in UTF-8 or pure AscII) and returns a *V instance.
+// Integers beyond the range of int64 and uint64 are kept exactly as BigInt and
+// Marshal do, while Int64, Uint64 and other getters return saturated values.
 //
-// Unmarshal 解析原始的字节类型数据（以 UTF-8 或纯 AscII 编码），并返回一个 *V 对象。
+// Unmarshal 解析原始的字节类型数据（以 UTF-8 或纯 AscII 编码），并返回一个 *V 对象。超出 int64 和
+// uint64 范围的整数会被精确保留, BigInt 和 Marshal 可以获得精确值, 而 Int64, Uint64 等其他
+// getter 则返回饱和后的值。
 func Unmarshal(b []byte) (ret *V, err error) {
 	le := len(b)
 	if le == 0 {
@@ -248,11 +260,7 @@ func Unmarshal(b []byte) (ret *V, err error) {
 
 	trueB := make([]byte, len(b))
 	copy(trueB, b)
-	it := iter(trueB)
-	p := newPool(len(b))
-	ret, err = unmarshalWithIter(p, it, 0)
-	p.release()
-	return
+	return unmarshalNoCopy(trueB, b)
 }
 
 // MustUnmarshalNoCopy just like UnmarshalNoCopy(). If error occurs, a JSON value
@@ -278,10 +286,19 @@ func UnmarshalNoCopy(b []byte) (ret *V, err error) {
 	if le == 0 {
 		return &V{}, ErrNilParameter
 	}
+	return unmarshalNoCopy(b, b)
+}
+
+// unmarshalNoCopy parses b which could be modified, while src is the original
+// text for locating syntax errors.
+func unmarshalNoCopy(b, src []byte) (*V, error) {
 	p := newPool(len(b))
-	ret, err = unmarshalWithIter(p, iter(b), 0)
+	v, err := unmarshalWithIter(p, iter(b), 0)
 	p.release()
-	return
+	if err != nil {
+		return v, locateSyntaxError(err, src)
+	}
+	return v, nil
 }
 
 // ==== type access ====
@@ -541,6 +558,76 @@ func (v *V) Float32() float32 {
 	return float32(v.num.f64)
 }
 
+// Decimal returns represented exact decimal value, which is parsed from the
+// original text of the number. If value is not a number, or is NaN or ±Inf,
+// returns zero.
+//
+// Decimal 返回精确的十进制值, 该值由数字的原始文本解析而来。如果当前值不是数字类型, 或者是 NaN
+// 或 ±Inf, 则返回 0。
+func (v *V) Decimal() decimal.Decimal {
+	if v == nil {
+		return decimal.Zero
+	}
+	if v.valueType != Number {
+		return getNumberFromNotNumberValue(v).Decimal()
+	}
+	d, err := decimal.NewFromString(string(v.srcByte))
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
+
+// BigInt returns represented integer value as a big.Int, therefore integers
+// beyond int64 and uint64 are exactly returned. The fraction part, if exists,
+// is truncated. If value is not a number, or is NaN or ±Inf, returns zero.
+//
+// BigInt 以 big.Int 类型返回整数值, 因此超出 int64 和 uint64 范围的整数也能精确返回。如果存在
+// 小数部分, 则会被截断。如果当前值不是数字类型, 或者是 NaN 或 ±Inf, 则返回 0。
+func (v *V) BigInt() *big.Int {
+	if v == nil {
+		return &big.Int{}
+	}
+	if v.valueType != Number {
+		return getNumberFromNotNumberValue(v).BigInt()
+	}
+	if i, ok := (&big.Int{}).SetString(string(v.srcByte), 10); ok {
+		return i
+	}
+	return v.Decimal().BigInt()
+}
+
+// BigFloat returns represented number value as a big.Float, whose precision is
+// enough to hold all digits of the number. If value is not a number, or is NaN,
+// returns zero.
+//
+// BigFloat 以 big.Float 类型返回数字值, 其精度足以容纳数字的全部有效位。如果当前值不是数字类型,
+// 或者是 NaN, 则返回 0。
+func (v *V) BigFloat() *big.Float {
+	if v == nil {
+		return &big.Float{}
+	}
+	if v.valueType != Number {
+		return getNumberFromNotNumberValue(v).BigFloat()
+	}
+	if len(v.srcByte) == 0 {
+		if math.IsInf(v.num.f64, 0) {
+			return big.NewFloat(v.num.f64)
+		}
+		return &big.Float{} // NaN
+	}
+
+	prec := uint(len(v.srcByte)) * 4 // more than log2(10) bits per digit
+	if prec < 64 {
+		prec = 64
+	}
+	f, _, err := big.ParseFloat(string(v.srcByte), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return &big.Float{}
+	}
+	return f
+}
+
 // Bytes returns represented binary data which is encoded as Base64 string. []byte{}
 // would be returned if value is
 // not a string type or base64 decode failed.
@@ -580,11 +667,11 @@ func (v *V) deepCopy() *V {
 		return res
 	case Object:
 		res := new(globalPool{}, Object)
-		res.children = v.children.deepCopy()
+		res.children = v.loadedChildren().deepCopy()
 		return res
 	case Array:
 		res := new(globalPool{}, Array)
-		res.children = v.children.deepCopy()
+		res.children = v.loadedChildren().deepCopy()
 		return res
 	case Boolean:
 		return NewBool(v.Bool())
@@ -635,7 +722,7 @@ func packObjChildren(v *V) string {
 func bufObjChildren(v *V, buf *bytes.Buffer) {
 	buf.WriteByte('{')
 	i := 0
-	for k, v := range v.children.object {
+	for k, v := range v.loadedChildren().object {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
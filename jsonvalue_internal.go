@@ -4,6 +4,7 @@ import (
 	"encoding"
 	"encoding/base64"
 	"encoding/json"
+	"math/big"
 	"reflect"
 	"sync/atomic"
 )
@@ -21,6 +22,8 @@ var internal = struct {
 	types struct {
 		JSONMarshaler reflect.Type
 		TextMarshaler reflect.Type
+		BigInt        reflect.Type
+		BigFloat      reflect.Type
 	}
 }{}
 
@@ -31,6 +34,8 @@ func init() {
 
 	internal.types.JSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
 	internal.types.TextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	internal.types.BigInt = reflect.TypeOf(big.Int{})
+	internal.types.BigFloat = reflect.TypeOf(big.Float{})
 }
 
 func internalLoadPredictSizePerValue() int {
@@ -28,6 +28,7 @@ var (
 
 	hasSubStr   = convey.ShouldContainSubstring
 	shouldPanic = convey.ShouldPanic
+	resemble    = convey.ShouldResemble
 )
 
 // go test -v -failfast -cover -coverprofile cover.out && go tool cover -html cover.out -o cover.html
@@ -86,6 +87,26 @@ func TestJsonvalue(t *testing.T) {
 	test(t, "test less than", testLessThan)
 	test(t, "test less than or equal", testLessThanOrEqual)
 	test(t, "test marshaler and unmarshaler", testMarshalerUnmarshaler)
+	test(t, "test decoder", testDecoder)
+	test(t, "test JSON lines", testLines)
+	test(t, "test syntax error", testSyntaxError)
+	test(t, "test JSON pointer", testPointer)
+	test(t, "test JSONPath", testJSONPath)
+	test(t, "test JSON patch", testPatch)
+	test(t, "test JSON merge patch", testMergePatch)
+	test(t, "test diff", testDiff)
+	test(t, "test canonical JSON", testCanonical)
+	test(t, "test hash", testHash)
+	test(t, "test decimal", testDecimal)
+	test(t, "test unmarshal options", testUnmarshalOption)
+	test(t, "test JSON5 unmarshal", testUnmarshalJSON5)
+	test(t, "test document", testDocument)
+	test(t, "test position", testPosition)
+	test(t, "test lazy unmarshal", testLazy)
+	test(t, "test tokenizer", testTokenizer)
+	test(t, "test unmarshal paths", testUnmarshalPaths)
+	test(t, "test parser", testParser)
+	test(t, "test encoder", testEncoder)
 	test(t, "test internal variables", testInternal)
 }
 
@@ -0,0 +1,411 @@
+package jsonvalue
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Andrew-M-C/go.jsonvalue/internal/unsafe"
+)
+
+// ================ LAZY UNMARSHAL ================
+
+// UnmarshalLazy parses raw bytes lazily. The whole text is checked at once, but
+// children of objects and arrays are not generated until they are first
+// accessed by Get, RangeObjects, RangeArray or any other method. It is much
+// faster than Unmarshal when only a few values of a large document are needed.
+//
+// Only standard JSON is accepted. A lazily parsed value could be read by multiple
+// goroutines at the same time, as generating children is guarded. But like
+// values returned by Unmarshal, it should not be modified concurrently.
+//
+// UnmarshalLazy 以懒加载的方式解析原始字节数据。整个文本会被一次性校验, 但是对象和数组中的子成员直到被
+// Get, RangeObjects, RangeArray 或其他方法首次访问时才会被生成。当只需要读取一个大文档中的少数几个值时,
+// 这比 Unmarshal 快得多。
+//
+// 仅接受标准 JSON。由于子成员的生成过程是受保护的, 懒加载解析出来的值可以在多个 goroutine 中同时读取。但与
+// Unmarshal 返回的值一样, 不应并发修改。
+func UnmarshalLazy(b []byte) (*V, error) {
+	le := len(b)
+	if le == 0 {
+		return nil, ErrNilParameter
+	}
+
+	trueB := make([]byte, le)
+	copy(trueB, b)
+	return unmarshalLazyNoCopy(trueB, b)
+}
+
+// UnmarshalLazyString is equivalent to UnmarshalLazy([]byte(s)).
+//
+// UnmarshalLazyString 等效于 UnmarshalLazy([]byte(s))。
+func UnmarshalLazyString(s string) (*V, error) {
+	if len(s) == 0 {
+		return nil, ErrNilParameter
+	}
+	return unmarshalLazyNoCopy([]byte(s), unsafe.StoB(s))
+}
+
+// unmarshalLazyNoCopy parses b which would be modified when children are
+// generated, while src is the original text for locating syntax errors.
+func unmarshalLazyNoCopy(b, src []byte) (*V, error) {
+	it := iter(b)
+	offset, reachEnd := it.skipBlanks(0)
+	if reachEnd {
+		return nil, newSyntaxError(ErrRawBytesUnrecognized, offset, "value", "cannot find any symbol characters")
+	}
+
+	var t ValueType
+	switch it[offset] {
+	default:
+		// scalar values could not be lazy
+		p := newPool(len(b))
+		v, err := unmarshalWithIter(p, it, 0)
+		p.release()
+		if err != nil {
+			return nil, locateSyntaxError(err, src)
+		}
+		return v, nil
+	case '{':
+		t = Object
+	case '[':
+		t = Array
+	}
+
+	end, err := it.checkLazyValue(offset)
+	if err != nil {
+		return nil, locateSyntaxError(err, src)
+	}
+	if end, reachEnd = it.skipBlanks(end); !reachEnd {
+		err = newSyntaxError(ErrRawBytesUnrecognized, end, "end of input", "unnecessary trailing data remains")
+		return nil, locateSyntaxError(err, src)
+	}
+
+	v := new(globalPool{}, t)
+	v.srcByte = it[offset:end]
+	v.children.lazy = atomic.AddUint32(&lazyLockSeq, 1)%uint32(len(lazyLocks)) + 1
+	return v, nil
+}
+
+// lazyLocks guard generating children of lazy values. All lazy containers of
+// one document share the same lock, which is children.lazy - 1.
+var (
+	lazyLocks   [64]sync.Mutex
+	lazyLockSeq uint32
+)
+
+// loadedChildren returns children of v, generating them first if v is a lazily
+// parsed object or array.
+func (v *V) loadedChildren() *children {
+	if atomic.LoadUint32(&v.children.lazy) != 0 {
+		v.loadLazy()
+	}
+	return &v.children
+}
+
+// loadLazy generates direct children of a lazy object or array. Sub objects and
+// arrays are lazy too. The text has been checked, so no error is expected.
+func (v *V) loadLazy() {
+	lock := atomic.LoadUint32(&v.children.lazy)
+	if lock == 0 {
+		return // generated by another goroutine
+	}
+	mu := &lazyLocks[lock-1]
+	mu.Lock()
+	defer mu.Unlock()
+	if atomic.LoadUint32(&v.children.lazy) == 0 {
+		return // generated by another goroutine while waiting
+	}
+
+	it := iter(v.srcByte)
+	v.srcByte = nil
+	defer atomic.StoreUint32(&v.children.lazy, 0)
+
+	c := &v.children
+	isObject := v.valueType == Object
+	if isObject {
+		c.object = make(map[string]childWithProperty)
+	}
+
+	offset := 1
+	for {
+		offset, _ = it.skipBlanks(offset)
+		switch it[offset] {
+		case '}', ']':
+			return
+		case ',':
+			offset++
+			continue
+		}
+
+		if !isObject {
+			var child *V
+			child, offset = it.loadLazyValue(offset, lock)
+			c.arr = append(c.arr, child)
+			continue
+		}
+
+		le, end, _ := it.parseStrFromBytesForwardWithQuote(offset)
+		key := unsafe.BtoS(it[offset+1 : offset+1+le])
+		offset, _ = it.skipBlanks(end) // ':'
+		offset, _ = it.skipBlanks(offset + 1)
+
+		var child *V
+		child, offset = it.loadLazyValue(offset, lock)
+		c.incrID++
+		c.object[key] = childWithProperty{id: c.incrID, v: child}
+	}
+}
+
+// loadLazyValue generates a checked value at offset and returns its end. Lazy
+// containers share the given lock with their parent.
+func (it iter) loadLazyValue(offset int, lock uint32) (*V, int) {
+	switch it[offset] {
+	case '{', '[':
+		t := Object
+		if it[offset] == '[' {
+			t = Array
+		}
+		end := it.skipLazyContainer(offset)
+		v := new(globalPool{}, t)
+		v.srcByte = it[offset:end]
+		v.children.lazy = lock
+		return v, end
+	case '"':
+		le, end, _ := it.parseStrFromBytesForwardWithQuote(offset)
+		return NewString(unsafe.BtoS(it[offset+1 : offset+1+le])), end
+	case 't':
+		return NewBool(true), offset + 4
+	case 'f':
+		return NewBool(false), offset + 5
+	case 'n':
+		return NewNull(), offset + 4
+	default:
+		v, end, _, _ := it.parseNumber(globalPool{}, offset)
+		return v, end
+	}
+}
+
+// skipLazyContainer returns the end of a checked object or array by matching
+// brackets only.
+func (it iter) skipLazyContainer(offset int) int {
+	depth := 0
+	for i := offset; i < len(it); i++ {
+		switch it[i] {
+		case '"':
+			for i++; it[i] != '"'; i++ {
+				if it[i] == '\\' {
+					i++
+				}
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(it)
+}
+
+// ================ LAZY CHECKING ================
+
+// checkLazyValue checks the value at offset without generating anything and
+// returns its end. A value accepted here must be accepted by the full parser.
+func (it iter) checkLazyValue(offset int) (end int, err error) {
+	switch chr := it[offset]; chr {
+	case '{':
+		return it.checkLazyObject(offset)
+	case '[':
+		return it.checkLazyArray(offset)
+	case '"':
+		return it.checkLazyString(offset)
+	case 't':
+		return it.parseTrue(offset)
+	case 'f':
+		return it.parseFalse(offset)
+	case 'n':
+		return it.parseNull(offset)
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-':
+		return it.checkLazyNumber(offset)
+	default:
+		return -1, newSyntaxError(ErrRawBytesUnrecognized, offset, "value", "invalid character \\u%04X", chr)
+	}
+}
+
+func (it iter) checkLazyObject(offset int) (end int, err error) {
+	offset, reachEnd := it.skipBlanks(offset + 1)
+	if reachEnd {
+		return -1, newSyntaxError(ErrNotObjectValue, offset, "'}'", "cannot find '}'")
+	}
+	if it[offset] == '}' {
+		return offset + 1, nil
+	}
+
+	for {
+		if it[offset] != '"' {
+			return -1, newSyntaxError(ErrNotObjectValue, offset, "string key", "invalid character \\u%04X", it[offset])
+		}
+		if offset, err = it.checkLazyString(offset); err != nil {
+			return -1, err
+		}
+
+		if offset, reachEnd = it.skipBlanks(offset); reachEnd || it[offset] != ':' {
+			return -1, newSyntaxError(ErrNotObjectValue, offset, "':'", "missing colon for key")
+		}
+		if offset, reachEnd = it.skipBlanks(offset + 1); reachEnd {
+			return -1, newSyntaxError(ErrNotObjectValue, offset, "value", "missing value for key")
+		}
+		if offset, err = it.checkLazyValue(offset); err != nil {
+			return -1, err
+		}
+
+		if offset, reachEnd = it.skipBlanks(offset); reachEnd {
+			return -1, newSyntaxError(ErrNotObjectValue, offset, "'}'", "cannot find '}'")
+		}
+		switch it[offset] {
+		case '}':
+			return offset + 1, nil
+		case ',':
+			if offset, reachEnd = it.skipBlanks(offset + 1); reachEnd {
+				return -1, newSyntaxError(ErrNotObjectValue, offset, "string key", "cannot find '}'")
+			}
+		default:
+			return -1, newSyntaxError(ErrNotObjectValue, offset, "',' or '}'", "invalid character \\u%04X", it[offset])
+		}
+	}
+}
+
+func (it iter) checkLazyArray(offset int) (end int, err error) {
+	offset, reachEnd := it.skipBlanks(offset + 1)
+	if reachEnd {
+		return -1, newSyntaxError(ErrNotArrayValue, offset, "']'", "cannot find ']'")
+	}
+	if it[offset] == ']' {
+		return offset + 1, nil
+	}
+
+	for {
+		if offset, err = it.checkLazyValue(offset); err != nil {
+			return -1, err
+		}
+
+		if offset, reachEnd = it.skipBlanks(offset); reachEnd {
+			return -1, newSyntaxError(ErrNotArrayValue, offset, "']'", "cannot find ']'")
+		}
+		switch it[offset] {
+		case ']':
+			return offset + 1, nil
+		case ',':
+			if offset, reachEnd = it.skipBlanks(offset + 1); reachEnd {
+				return -1, newSyntaxError(ErrNotArrayValue, offset, "value", "cannot find ']'")
+			}
+		default:
+			return -1, newSyntaxError(ErrNotArrayValue, offset, "',' or ']'", "invalid character \\u%04X", it[offset])
+		}
+	}
+}
+
+// checkLazyString checks a string like parseStrFromBytesForwardWithQuote but does
+// not unescape it.
+func (it iter) checkLazyString(offset int) (end int, err error) {
+	end = len(it)
+	for i := offset + 1; i < end; {
+		chr := it[i]
+
+		switch {
+		case chr == '\\':
+			le, err := it.checkLazyEscape(i)
+			if err != nil {
+				return -1, err
+			}
+			i += le
+		case chr == '"':
+			return i + 1, nil
+		case chr <= 0x7F:
+			i++
+		case runeIdentifyingBytes2(chr):
+			i += 2
+		case runeIdentifyingBytes3(chr):
+			i += 3
+		case runeIdentifyingBytes4(chr):
+			i += 4
+		default:
+			return -1, newSyntaxError(ErrIllegalString, i, "", "illegal UTF8 string")
+		}
+	}
+
+	return -1, newSyntaxError(ErrIllegalString, offset, "'\"'", "ending double quote of a string is not found")
+}
+
+// checkLazyEscape checks the escaped sequence at offset and returns its length.
+func (it iter) checkLazyEscape(offset int) (le int, err error) {
+	end := len(it) - 1
+	if end-offset < 1 {
+		return -1, newSyntaxError(ErrIllegalString, offset, "escaped character", "escape symbol not followed by another character")
+	}
+
+	switch chr := it[offset+1]; chr {
+	default:
+		return -1, newSyntaxError(ErrIllegalString, offset, "escaped character", "unrecognized character 0x%02X after escape symbol", chr)
+	case '"', '\'', '/', '\\', 'b', 'f', 'r', 'n', 't':
+		return 2, nil
+	case 'u':
+		// go on
+	}
+
+	if end-offset <= 5 {
+		return -1, newSyntaxError(ErrIllegalString, offset, "4 hexadecimal digits", "insufficient unicode escaping characters")
+	}
+	r := it.hexRune(offset+2, &err)
+	if err != nil {
+		return -1, newSyntaxError(ErrIllegalString, offset, "4 hexadecimal digits", "%v", err)
+	}
+	if r <= 0xD7FF || r >= 0xE000 {
+		return 6, nil
+	}
+
+	if end-offset <= 11 {
+		return -1, newSyntaxError(ErrIllegalString, offset, "UTF-16 low surrogate", "insufficient UTF-16 data")
+	}
+	if it[offset+6] != '\\' || it[offset+7] != 'u' {
+		return -1, newSyntaxError(ErrIllegalString, offset+6, "UTF-16 low surrogate", "expect unicode escape character but not")
+	}
+	ex := it.hexRune(offset+8, &err)
+	if err != nil {
+		return -1, newSyntaxError(ErrIllegalString, offset+6, "4 hexadecimal digits", "%v", err)
+	}
+	if ex < 0xDC00 || ex > 0xDFFF {
+		return -1, newSyntaxError(
+			ErrIllegalString, offset+6, "UTF-16 low surrogate",
+			"expect second UTF-16 encoding but got 0x%04X", ex,
+		)
+	}
+	return 12, nil
+}
+
+func (it iter) hexRune(offset int, errOut *error) rune {
+	b3 := chrToHex(it[offset], errOut)
+	b2 := chrToHex(it[offset+1], errOut)
+	b1 := chrToHex(it[offset+2], errOut)
+	b0 := chrToHex(it[offset+3], errOut)
+	return (rune(b3) << 12) + (rune(b2) << 8) + (rune(b1) << 4) + rune(b0)
+}
+
+// checkLazyNumber checks a number like parseNumber but does not generate it.
+func (it iter) checkLazyNumber(offset int) (end int, err error) {
+	end, floated, _, _, err := it.scanNumber(offset)
+	if err != nil {
+		return -1, err
+	}
+
+	// integers are always accepted, as those beyond uint64 are saturated
+	if floated {
+		if _, err := strconv.ParseFloat(unsafe.BtoS(it[offset:end]), 64); err != nil {
+			return -1, it.numErrorf(offset, "%v", err)
+		}
+	}
+	return end, nil
+}
@@ -0,0 +1,189 @@
+package jsonvalue
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+func testLazy(t *testing.T) {
+	cv("same as full unmarshal", func() { testLazySameAsUnmarshal(t) })
+	cv("load on demand", func() { testLazyLoadOnDemand(t) })
+	cv("modification", func() { testLazyModification(t) })
+	cv("scalar values", func() { testLazyScalar(t) })
+	cv("invalid text", func() { testLazyInvalid(t) })
+	cv("concurrent reading", func() { testLazyConcurrentReading(t) })
+}
+
+const testLazyText = `{
+	"int": -123, "float": 1.5e3, "uint": 18446744073709551615,
+	"str": "a\"b\\c中😀/",
+	"bool": [true, false, null],
+	"obj": {"arr": [{"k": "v}]"}, [], {}], "str]": "[{"},
+	"dup": 1, "dup": 2
+}`
+
+func testLazySameAsUnmarshal(t *testing.T) {
+	full, err := UnmarshalString(testLazyText)
+	so(err, isNil)
+
+	v, err := UnmarshalLazyString(testLazyText)
+	so(err, isNil)
+	so(v.Equal(full), isTrue)
+
+	v, err = UnmarshalLazy([]byte(testLazyText))
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, full.MustMarshalString(OptDefaultStringSequence()))
+
+	v, err = UnmarshalLazyString(testLazyText)
+	so(err, isNil)
+	so(v.Len(), eq, 7)
+	so(v.MustGet("str").String(), eq, "a\"b\\c中\U0001F600/")
+	so(v.MustGet("uint").Uint64(), eq, uint64(18446744073709551615))
+	so(v.MustGet("dup").Int(), eq, 2)
+	so(v.MustGet("obj", "arr", 0, "k").String(), eq, "v}]")
+	so(v.MustGet("obj", "str]").String(), eq, "[{")
+	so(v.MustGet("obj", "arr", 1).IsArray(), isTrue)
+	so(v.MustGet("obj", "arr", 2).Len(), eq, 0)
+
+	cnt := 0
+	v.MustGet("bool").RangeArray(func(i int, c *V) bool {
+		cnt++
+		return true
+	})
+	so(cnt, eq, 3)
+
+	v, err = UnmarshalLazyString(testLazyText)
+	so(err, isNil)
+	keys := map[string]bool{}
+	v.RangeObjects(func(k string, c *V) bool {
+		keys[k] = true
+		return true
+	})
+	so(len(keys), eq, 7)
+}
+
+func testLazyLoadOnDemand(t *testing.T) {
+	v, err := UnmarshalLazyString(`{"a": {"b": [1, {"c": 2}]}, "d": [3]}`)
+	so(err, isNil)
+	so(v.IsObject(), isTrue)
+	so(string(v.srcByte), eq, `{"a": {"b": [1, {"c": 2}]}, "d": [3]}`)
+
+	a := v.MustGet("a")
+	so(v.srcByte, isNil)
+	so(string(a.srcByte), eq, `{"b": [1, {"c": 2}]}`)
+	d := v.children.object["d"].v
+	so(string(d.srcByte), eq, `[3]`)
+
+	so(a.MustGet("b", 1, "c").Int(), eq, 2)
+	so(a.srcByte, isNil)
+	so(string(d.srcByte), eq, `[3]`)
+
+	// deep copy does not share the text
+	c := v.deepCopy()
+	so(d.srcByte, isNil)
+	so(c.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":{"b":[1,{"c":2}]},"d":[3]}`)
+}
+
+func testLazyConcurrentReading(t *testing.T) {
+	const goroutines = 16
+	const rounds = 20
+	results := make([]string, goroutines)
+
+	for r := 0; r < rounds; r++ {
+		v, err := UnmarshalLazyString(`{"a": {"b": ["\u4F60\u597D", {"c": "\"d\""}]}, "e": [1, [2, 3]]}`)
+		so(err, isNil)
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				b0, _ := v.GetString("a", "b", 0)
+				c, _ := v.GetString("a", "b", 1, "c")
+				e, _ := v.Get("e", 1)
+				results[i] = b0 + c + e.MustMarshalString()
+			}(i)
+		}
+		wg.Wait()
+
+		for _, res := range results {
+			so(res, eq, `你好"d"[2,3]`)
+		}
+	}
+}
+
+func testLazyModification(t *testing.T) {
+	v, err := UnmarshalLazyString(`{"a": [1, 2], "b": {"c": "d"}, "e": "f"}`)
+	so(err, isNil)
+
+	v.MustAppend(3).InTheEnd("a")
+	v.MustSet("x").At("b", "c")
+	v.MustDelete("e")
+	v.MustSet(true).At("g")
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":[1,2,3],"b":{"c":"x"},"g":true}`)
+
+	v, err = UnmarshalLazyString(`[{"A": 1}, [2]]`)
+	so(err, isNil)
+	so(v.MustGet(0).Caseless().MustGet("a").Int(), eq, 1)
+	v.MustInsert(0).Before(1, 0)
+	so(v.MustMarshalString(), eq, `[{"A":1},[0,2]]`)
+}
+
+func testLazyScalar(t *testing.T) {
+	v, err := UnmarshalLazyString(` "str" `)
+	so(err, isNil)
+	so(v.String(), eq, "str")
+
+	v, err = UnmarshalLazy([]byte(`12.5`))
+	so(err, isNil)
+	so(v.Float64(), eq, 12.5)
+	so(v.srcByte, notNil)
+	so(v.MustMarshalString(), eq, `12.5`)
+
+	v, err = UnmarshalLazyString(`null`)
+	so(err, isNil)
+	so(v.IsNull(), isTrue)
+}
+
+func testLazyInvalid(t *testing.T) {
+	_, err := UnmarshalLazy(nil)
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = UnmarshalLazyString("")
+	so(errors.Is(err, ErrNilParameter), isTrue)
+
+	invalid := []string{
+		` `, `{`, `[`, `{"a"}`, `{"a":}`, `{"a" 1}`, `{1:1}`, `[1}`, `{"a":1]`, `[01]`, `[1.]`, `[-]`,
+		`[1e400]`, `[1e]`, `[1e-]`, `[tru]`, `[nul]`, `[fals]`,
+		`["a]`, `["\x"]`, `["\u12"]`, `["\uZZZZ"]`, `["\uD800"]`, `["\uD800A"]`, "[\"\xff\"]",
+		`[1] 2`, `[+1]`, `[.5]`,
+	}
+	for _, s := range invalid {
+		_, err := UnmarshalLazyString(s)
+		so(err, isErr)
+		_, err = UnmarshalString(s)
+		so(err, isErr)
+	}
+
+	// integers beyond uint64 are accepted as Unmarshal does
+	v, err := UnmarshalLazyString(`[18446744073709551616, -9223372036854775809]`)
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `[18446744073709551616,-9223372036854775809]`)
+	so(v.MustGet(1).Int64(), eq, int64(math.MinInt64))
+
+	// lazy parsing only accepts standard JSON, while Unmarshal is looser
+	loose := []string{`{"a":1,}`, `[1,]`, `[1 2]`, `{"a":1 "b":2}`}
+	for _, s := range loose {
+		_, err := UnmarshalLazyString(s)
+		so(err, isErr)
+		_, err = UnmarshalString(s)
+		so(err, isNil)
+	}
+
+	_, err = UnmarshalLazyString("{\n  \"a\": [1, 2 3]\n}")
+	so(errors.Is(err, ErrNotArrayValue), isTrue)
+	var synErr *SyntaxError
+	so(errors.As(err, &synErr), isTrue)
+	so(synErr.Line, eq, 2)
+}
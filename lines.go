@@ -0,0 +1,220 @@
+package jsonvalue
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// BadLineHandleType tells how LinesReader deals with lines those could not be
+// parsed.
+//
+// BadLineHandleType 表示 LinesReader 遇到无法解析的行时的处理方式。
+type BadLineHandleType uint8
+
+const (
+	// BadLineTreatAsError indicates that a *LineError will be returned by Read()
+	// when a line could not be parsed. This is the default option. Reading could
+	// still go on after the error.
+	//
+	// BadLineTreatAsError 表示遇到无法解析的行时, Read() 返回一个 *LineError 错误。这是默认
+	// 选项。返回错误之后, 依然可以继续读取后续的行。
+	BadLineTreatAsError BadLineHandleType = 0
+	// BadLineSkip indicates that bad lines will be skipped silently.
+	//
+	// BadLineSkip 表示静默地跳过无法解析的行。
+	BadLineSkip BadLineHandleType = 1
+	// BadLineCollect indicates that bad lines will be skipped, and their errors
+	// will be collected and could be retrieved by Errors().
+	//
+	// BadLineCollect 表示跳过无法解析的行, 但是会收集相应的错误, 可以通过 Errors() 获取。
+	BadLineCollect BadLineHandleType = 2
+)
+
+// LineError describes a parsing error in a specified line of JSON Lines text.
+//
+// LineError 表示 JSON Lines 文本中指定行的解析错误。
+type LineError struct {
+	Line int // line number, starting from 1
+	Err  error
+}
+
+// Error implements error interface.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying parsing error.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LinesReader reads JSON Lines (also known as NDJSON, newline-delimited JSON)
+// text, yielding one *V per line. Blank lines are ignored.
+//
+// LinesReader 读取 JSON Lines (也称为 NDJSON, 以换行符分隔的 JSON) 文本, 每一行返回一个
+// *V 值。空行会被忽略。
+type LinesReader struct {
+	// BadLineHandleType tells how to deal with lines those could not be parsed.
+	//
+	// BadLineHandleType 表示遇到无法解析的行时要如何处理。
+	BadLineHandleType BadLineHandleType
+
+	// MaxLineSize is the max bytes of a line, excluding the trailing '\n'. A
+	// longer line is discarded without being buffered as a whole, and treated as
+	// a bad line with ErrLineTooLong. If it is not positive, DefaultMaxLineSize
+	// is used.
+	//
+	// MaxLineSize 表示一行的最大字节数, 不包括末尾的 '\n'。更长的行不会被完整地缓存, 而是被丢弃,
+	// 并视为错误为 ErrLineTooLong 的无法解析的行。如果不是正数, 则使用 DefaultMaxLineSize。
+	MaxLineSize int
+
+	r    *bufio.Reader
+	line int
+	errs []*LineError
+}
+
+// DefaultMaxLineSize is the default MaxLineSize of LinesReader.
+//
+// DefaultMaxLineSize 是 LinesReader 默认的 MaxLineSize。
+const DefaultMaxLineSize = 4 << 20
+
+// NewLinesReader returns a JSON Lines reader reading from r.
+//
+// NewLinesReader 返回一个从 r 中读取 JSON Lines 的读取器。
+func NewLinesReader(r io.Reader) *LinesReader {
+	return &LinesReader{
+		r: bufio.NewReader(r),
+	}
+}
+
+// Read returns the value in next non-blank line. io.EOF will be returned when
+// there are no more lines.
+//
+// Read 返回下一个非空行中的值。如果没有更多的行了, 则返回 io.EOF。
+func (r *LinesReader) Read() (*V, error) {
+	for {
+		b, tooLong, err := r.readLine()
+		if err != nil && (err != io.EOF || (len(b) == 0 && !tooLong)) {
+			return &V{}, err
+		}
+		r.line++
+
+		var parseErr error
+		if tooLong {
+			parseErr = fmt.Errorf("%w, exceeds %d bytes", ErrLineTooLong, r.maxLineSize())
+		} else {
+			b = bytes.TrimSpace(b)
+			if len(b) == 0 {
+				continue
+			}
+			v, err := UnmarshalNoCopy(b)
+			if err == nil {
+				return v, nil
+			}
+			parseErr = err
+		}
+
+		lineErr := &LineError{
+			Line: r.line,
+			Err:  parseErr,
+		}
+		switch r.BadLineHandleType {
+		default:
+			return &V{}, lineErr
+		case BadLineSkip:
+			// continue
+		case BadLineCollect:
+			r.errs = append(r.errs, lineErr)
+		}
+	}
+}
+
+// readLine reads next line. If the line exceeds MaxLineSize, the remaining
+// bytes are discarded and tooLong is true.
+func (r *LinesReader) readLine() (line []byte, tooLong bool, err error) {
+	maxSize := r.maxLineSize()
+	for {
+		b, err := r.r.ReadSlice('\n')
+		if !tooLong {
+			n := len(line) + len(b)
+			if bytes.HasSuffix(b, []byte{'\n'}) {
+				n-- // line ending
+			}
+			if n > maxSize {
+				line, tooLong = nil, true
+			} else {
+				line = append(line, b...)
+			}
+		}
+		if err != bufio.ErrBufferFull {
+			return line, tooLong, err
+		}
+	}
+}
+
+func (r *LinesReader) maxLineSize() int {
+	if r.MaxLineSize > 0 {
+		return r.MaxLineSize
+	}
+	return DefaultMaxLineSize
+}
+
+// Line returns the line number of the latest read line.
+//
+// Line 返回最近一次读取的行的行号。
+func (r *LinesReader) Line() int {
+	return r.line
+}
+
+// Errors returns all collected errors if BadLineHandleType is BadLineCollect.
+//
+// Errors 返回 BadLineHandleType 为 BadLineCollect 时收集到的所有错误。
+func (r *LinesReader) Errors() []*LineError {
+	return r.errs
+}
+
+// LinesWriter writes values in JSON Lines format, one compact value per line.
+//
+// LinesWriter 以 JSON Lines 格式写入值, 每行一个紧凑格式的值。
+type LinesWriter struct {
+	w    io.Writer
+	opts []Option
+}
+
+// NewLinesWriter returns a JSON Lines writer writing to w. Options are the
+// same as Marshal(), excepting that OptIndent will be ignored.
+//
+// NewLinesWriter 返回一个向 w 写入 JSON Lines 的写入器。参数与 Marshal() 相同, 但是
+// OptIndent 会被忽略。
+func NewLinesWriter(w io.Writer, opts ...Option) *LinesWriter {
+	o := make([]Option, 0, len(opts)+1)
+	o = append(o, opts...)
+	o = append(o, optNoIndent{})
+
+	return &LinesWriter{
+		w:    w,
+		opts: o,
+	}
+}
+
+// Write marshals v and writes it as a line.
+//
+// Write 序列化 v 并写入一行。
+func (w *LinesWriter) Write(v *V) error {
+	if w.w == nil {
+		return ErrNilParameter
+	}
+	if err := v.MarshalWrite(w.w, w.opts...); err != nil {
+		return err
+	}
+	_, err := w.w.Write([]byte{'\n'})
+	return err
+}
+
+type optNoIndent struct{}
+
+func (optNoIndent) mergeTo(opt *Opt) {
+	opt.indent.enabled = false
+}
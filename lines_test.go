@@ -0,0 +1,196 @@
+package jsonvalue
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func testLines(t *testing.T) {
+	cv("read JSON lines", func() { testLinesReader(t) })
+	cv("bad lines", func() { testLinesReaderBadLines(t) })
+	cv("too long lines", func() { testLinesReaderTooLongLines(t) })
+	cv("write JSON lines", func() { testLinesWriter(t) })
+}
+
+func testLinesReader(t *testing.T) {
+	raw := "{\"a\":1}\n\n  [1,2,3]  \r\n\"string\"\r\n\t\n123"
+	r := NewLinesReader(strings.NewReader(raw))
+
+	v, err := r.Read()
+	so(err, isNil)
+	so(v.MustGet("a").Int(), eq, 1)
+	so(r.Line(), eq, 1)
+
+	v, err = r.Read()
+	so(err, isNil)
+	so(v.Len(), eq, 3)
+	so(r.Line(), eq, 3)
+
+	v, err = r.Read()
+	so(err, isNil)
+	so(v.String(), eq, "string")
+	so(r.Line(), eq, 4)
+
+	v, err = r.Read()
+	so(err, isNil)
+	so(v.Int(), eq, 123)
+	so(r.Line(), eq, 6)
+
+	_, err = r.Read()
+	so(err, eq, io.EOF)
+}
+
+func testLinesReaderBadLines(t *testing.T) {
+	raw := "{\"a\":1}\n{\"b\":\n[2]\n{]\n"
+
+	cv("treat as error", func() {
+		r := NewLinesReader(strings.NewReader(raw))
+		_, err := r.Read()
+		so(err, isNil)
+
+		_, err = r.Read()
+		so(err, isErr)
+		lineErr := &LineError{}
+		so(errors.As(err, &lineErr), isTrue)
+		so(lineErr.Line, eq, 2)
+		so(err.Error(), hasSubStr, "line 2")
+		so(errors.Is(err, ErrNotObjectValue), isTrue)
+
+		v, err := r.Read()
+		so(err, isNil)
+		so(v.MustGet(0).Int(), eq, 2)
+
+		_, err = r.Read()
+		so(err, isErr)
+		so(errors.As(err, &lineErr), isTrue)
+		so(lineErr.Line, eq, 4)
+
+		_, err = r.Read()
+		so(err, eq, io.EOF)
+	})
+
+	cv("skip", func() {
+		r := NewLinesReader(strings.NewReader(raw))
+		r.BadLineHandleType = BadLineSkip
+
+		cnt := 0
+		for {
+			_, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			so(err, isNil)
+			cnt++
+		}
+		so(cnt, eq, 2)
+		so(len(r.Errors()), eq, 0)
+	})
+
+	cv("collect", func() {
+		r := NewLinesReader(strings.NewReader(raw))
+		r.BadLineHandleType = BadLineCollect
+
+		cnt := 0
+		for {
+			_, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			so(err, isNil)
+			cnt++
+		}
+		so(cnt, eq, 2)
+
+		errs := r.Errors()
+		so(len(errs), eq, 2)
+		so(errs[0].Line, eq, 2)
+		so(errs[1].Line, eq, 4)
+	})
+}
+
+func testLinesReaderTooLongLines(t *testing.T) {
+	long := "\"" + strings.Repeat("x", 10000) + "\""
+	raw := "[1234567]\n" + long + "\n[12345678]\r\n" + long
+
+	cv("treat as error", func() {
+		r := NewLinesReader(strings.NewReader(raw))
+		r.MaxLineSize = 10
+
+		v, err := r.Read()
+		so(err, isNil)
+		so(v.MustGet(0).Int(), eq, 1234567)
+
+		_, err = r.Read()
+		so(errors.Is(err, ErrLineTooLong), isTrue)
+		lineErr := &LineError{}
+		so(errors.As(err, &lineErr), isTrue)
+		so(lineErr.Line, eq, 2)
+
+		// reading goes on with next line
+		_, err = r.Read()
+		so(errors.Is(err, ErrLineTooLong), isTrue)
+		so(r.Line(), eq, 3)
+
+		_, err = r.Read()
+		so(errors.Is(err, ErrLineTooLong), isTrue)
+		so(r.Line(), eq, 4)
+
+		_, err = r.Read()
+		so(err, eq, io.EOF)
+	})
+
+	cv("collect", func() {
+		r := NewLinesReader(strings.NewReader(raw))
+		r.MaxLineSize = 11
+		r.BadLineHandleType = BadLineCollect
+
+		cnt := 0
+		for {
+			_, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			so(err, isNil)
+			cnt++
+		}
+		so(cnt, eq, 2)
+		so(len(r.Errors()), eq, 2)
+		so(r.Errors()[1].Line, eq, 4)
+	})
+
+	cv("default size", func() {
+		r := NewLinesReader(strings.NewReader(long))
+		v, err := r.Read()
+		so(err, isNil)
+		so(len(v.String()), eq, 10000)
+	})
+}
+
+func testLinesWriter(t *testing.T) {
+	buf := bytes.Buffer{}
+	w := NewLinesWriter(&buf, OptIndent("", "  "), OptDefaultStringSequence(), OptOmitNull(true))
+
+	err := w.Write(MustUnmarshalString(`{"b":2,"a":[1, 2],"n":null}`))
+	so(err, isNil)
+	err = w.Write(NewString("<html>"))
+	so(err, isNil)
+	err = w.Write(&V{})
+	so(err, isErr)
+
+	so(buf.String(), eq, "{\"a\":[1,2],\"b\":2}\n\"\\u003Chtml\\u003E\"\n")
+
+	// read back
+	r := NewLinesReader(&buf)
+	v, err := r.Read()
+	so(err, isNil)
+	so(v.MustGet("a", 1).Int(), eq, 2)
+	v, err = r.Read()
+	so(err, isNil)
+	so(v.String(), eq, "<html>")
+
+	err = NewLinesWriter(nil).Write(NewNull())
+	so(errors.Is(err, ErrNilParameter), isTrue)
+}
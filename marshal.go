@@ -1,14 +1,17 @@
 package jsonvalue
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math"
+	"strconv"
 	"strings"
 
 	"github.com/Andrew-M-C/go.jsonvalue/internal/buffer"
 	"github.com/Andrew-M-C/go.jsonvalue/internal/unsafe"
+	"github.com/shopspring/decimal"
 )
 
 // MustMarshal is the same as Marshal. If error occurs, an empty byte slice will be returned.
@@ -114,6 +117,9 @@ func marshalBoolean(v *V, buf io.Writer) {
 
 func marshalNumber(v *V, buf io.Writer, opt *Opt) error {
 	if b := v.srcByte; len(b) > 0 {
+		if opt.numberFormat.enabled() {
+			b = normalizeNumber(b, v.num.f64, opt)
+		}
 		_, _ = buf.Write(b)
 		return nil
 	}
@@ -128,6 +134,165 @@ func marshalNumber(v *V, buf io.Writer, opt *Opt) error {
 	return marshalNaN(buf, opt)
 }
 
+// maxSafeInteger is the max integer that JavaScript could represent exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// maxExpandedNumberDigits is the max digits of a number written without
+// exponent by number normalization options. Any float64 fits in it, while a
+// short text like 1e-10000000 would not be expanded into megabytes.
+const maxExpandedNumberDigits = 1024
+
+func normalizeNumber(b []byte, f float64, opt *Opt) []byte {
+	nf := &opt.numberFormat
+
+	if places, ok := nf.decimalPlaces(); ok {
+		high, low, zero := numberExtent(b)
+		if zero || high < -int64(places)-1 {
+			b = zeroWithDecimalPlaces(places) // too small to affect any decimal place
+		} else if integerDigits(high)+maxInt64(-low, int64(places)) <= maxExpandedNumberDigits {
+			if d, err := decimal.NewFromString(unsafe.BtoS(b)); err == nil {
+				b = []byte(d.StringFixed(places))
+			}
+		}
+	} else {
+		if nf.shortest && !isPlainIntegerBytes(b) {
+			b = []byte(formatECMAScriptNumber(f))
+		}
+		if nf.integralAsInteger || (nf.noExponent && bytes.ContainsAny(b, "eE")) {
+			if high, low, _ := numberExtent(b); integerDigits(high)+maxInt64(-low, 0) <= maxExpandedNumberDigits {
+				if d, err := decimal.NewFromString(unsafe.BtoS(b)); err == nil {
+					if nf.noExponent || d.IsInteger() {
+						b = []byte(d.String())
+					}
+				}
+			}
+		}
+	}
+
+	if nf.largeIntAsString && isPlainIntegerBytes(b) && !isSafeInteger(b) {
+		res := make([]byte, 0, len(b)+2)
+		res = append(res, '"')
+		res = append(res, b...)
+		return append(res, '"')
+	}
+	return b
+}
+
+// numberExtent returns the powers of ten of the most significant non-zero digit
+// and the last digit of a number text, without expanding it. For example,
+// 0.0125e2 gives 0 and -2. zero is true if all digits are zero.
+func numberExtent(b []byte) (high, low int64, zero bool) {
+	if b[0] == '-' {
+		b = b[1:]
+	}
+	exp := int64(0)
+	if idx := bytes.IndexAny(b, "eE"); idx >= 0 {
+		// an exponent beyond int32 is clamped, which is still far beyond the limit
+		exp, _ = strconv.ParseInt(unsafe.BtoS(b[idx+1:]), 10, 32)
+		b = b[:idx]
+	}
+	intLen := bytes.IndexByte(b, '.')
+	if intLen < 0 {
+		intLen = len(b)
+	}
+
+	zero = true
+	digitIndex := int64(0)
+	for _, c := range b {
+		if c == '.' {
+			continue
+		}
+		pow := int64(intLen) - 1 - digitIndex + exp
+		if zero && c != '0' {
+			high, zero = pow, false
+		}
+		low = pow
+		digitIndex++
+	}
+	return high, low, zero
+}
+
+// integerDigits returns digits of the integer part of a number whose most
+// significant digit is at given power of ten.
+func integerDigits(high int64) int64 {
+	return maxInt64(high, 0) + 1
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func zeroWithDecimalPlaces(places int32) []byte {
+	if places <= 0 {
+		return []byte{'0'}
+	}
+	b := make([]byte, places+2)
+	for i := range b {
+		b[i] = '0'
+	}
+	b[1] = '.'
+	return b
+}
+
+// isSafeInteger tells whether a plain integer text is within ±(2^53-1).
+func isSafeInteger(b []byte) bool {
+	if b[0] == '-' {
+		b = b[1:]
+	}
+	u, err := strconv.ParseUint(unsafe.BtoS(b), 10, 64)
+	return err == nil && u <= maxSafeInteger
+}
+
+func (nf *numberFormat) enabled() bool {
+	return nf.shortest || nf.noExponent || nf.integralAsInteger || nf.largeIntAsString || len(nf.places) > 0
+}
+
+// decimalPlaces returns decimal places of current marshaling number by
+// OptNumberDecimalPlaces options.
+func (nf *numberFormat) decimalPlaces() (int32, bool) {
+	for i := len(nf.places) - 1; i >= 0; i-- {
+		p := nf.places[i]
+		if p.tokens == nil {
+			return p.places, true
+		}
+		for _, tokens := range p.tokens {
+			if pointerTokensMatch(tokens, nf.path) {
+				return p.places, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (nf *numberFormat) pushPath(token string) {
+	if nf.trackPath {
+		nf.path = append(nf.path, token)
+	}
+}
+
+func (nf *numberFormat) popPath() {
+	if nf.trackPath {
+		nf.path = nf.path[:len(nf.path)-1]
+	}
+}
+
+// pointerTokensMatch checks whether JSON Pointer tokens with "*" wildcards
+// match given path exactly.
+func pointerTokensMatch(tokens, path []string) bool {
+	if len(tokens) != len(path) {
+		return false
+	}
+	for i, t := range tokens {
+		if t != "*" && t != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func marshalNaN(buf io.Writer, opt *Opt) error {
 	switch opt.FloatNaNHandleType {
 	default:
@@ -236,7 +401,7 @@ func marshalNull(buf io.Writer) {
 }
 
 func marshalObject(v *V, parentInfo *ParentInfo, buf io.Writer, opt *Opt) {
-	if len(v.children.object) == 0 {
+	if len(v.loadedChildren().object) == 0 {
 		_, _ = buf.Write([]byte{'{', '}'})
 		return
 	}
@@ -270,8 +435,10 @@ func marshalObject(v *V, parentInfo *ParentInfo, buf io.Writer, opt *Opt) {
 
 func writeObjectKVInRandomizedSequence(v *V, buf io.Writer, opt *Opt) {
 	firstWritten := false
-	for k, child := range v.children.object {
-		firstWritten = writeObjectChildren(nil, buf, !firstWritten, k, child.v, opt)
+	for k, child := range v.loadedChildren().object {
+		if writeObjectChildren(nil, buf, !firstWritten, k, child.v, opt) {
+			firstWritten = true
+		}
 	}
 }
 
@@ -299,7 +466,9 @@ func writeObjectChildren(
 		_, _ = buf.Write([]byte{'"', ':'})
 	}
 
+	opt.numberFormat.pushPath(key)
 	_ = marshalToBuffer(child, parentInfo, buf, opt)
+	opt.numberFormat.popPath()
 	return true
 }
 
@@ -311,7 +480,7 @@ func writeIndent(buf io.Writer, opt *Opt) {
 }
 
 func marshalArray(v *V, parentInfo *ParentInfo, buf io.Writer, opt *Opt) {
-	if len(v.children.arr) == 0 {
+	if len(v.loadedChildren().arr) == 0 {
 		_, _ = buf.Write([]byte{'[', ']'})
 		return
 	}
@@ -327,11 +496,15 @@ func marshalArray(v *V, parentInfo *ParentInfo, buf io.Writer, opt *Opt) {
 			_, _ = buf.Write([]byte{'\n'})
 			writeIndent(buf, opt)
 		}
+		if opt.numberFormat.trackPath {
+			opt.numberFormat.pushPath(strconv.Itoa(i))
+		}
 		if opt.MarshalLessFunc == nil {
 			_ = marshalToBuffer(child, nil, buf, opt)
 		} else {
 			_ = marshalToBuffer(child, newParentInfo(v, parentInfo, intKey(i)), buf, opt)
 		}
+		opt.numberFormat.popPath()
 		return true
 	})
 
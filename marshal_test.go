@@ -20,6 +20,7 @@ func testMarshal(t *testing.T) {
 	cv("test JSONP and control ASCII for UTF-8", func() { testMarshalJSONPAndControlAsciiForUTF8(t) })
 	cv("Issue #30", func() { testIssue30(t) })
 	cv("MarshalWrite", func() { testMarshalWrite(t) })
+	cv("omit null with key sequence", func() { testMarshalOmitNullWithKeySequence(t) })
 }
 
 func testMarshalFloat64NaN(*testing.T) {
@@ -895,3 +896,20 @@ const issue30Raw = `{
 		}
 	]
 }`
+
+func testMarshalOmitNullWithKeySequence(t *testing.T) {
+	v := MustUnmarshalString(`{"a":1,"b":null,"c":2,"d":null}`)
+
+	s := v.MustMarshalString(OptOmitNull(true), OptSetSequence())
+	so(s, eq, `{"a":1,"c":2}`)
+
+	s = v.MustMarshalString(OptOmitNull(true), OptDefaultStringSequence())
+	so(s, eq, `{"a":1,"c":2}`)
+
+	s = v.MustMarshalString(OptOmitNull(true), OptKeySequence([]string{"d", "c", "b", "a"}))
+	so(s, eq, `{"c":2,"a":1}`)
+
+	s = v.MustMarshalString(OptOmitNull(true))
+	so(len(s), eq, len(`{"a":1,"c":2}`))
+	so(MustUnmarshalString(s).Equal(MustUnmarshalString(`{"a":1,"c":2}`)), isTrue)
+}
@@ -0,0 +1,114 @@
+package jsonvalue
+
+// ================ JSON MERGE PATCH ================
+
+// Reference: [RFC 7386 - JSON Merge Patch](https://www.rfc-editor.org/rfc/rfc7386)
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to current value. Object
+// members in patch are merged into current value recursively, while a null
+// member deletes the key. If patch is not an object, current value is replaced
+// by it.
+//
+// Values in patch are copied, so that patch and current value do not share any
+// sub value after applying.
+//
+// ApplyMergePatch 将一个 JSON Merge Patch (RFC 7386) 应用到当前值上。patch 中的 object
+// 成员会被递归地合并到当前值中, 而值为 null 的成员则表示删除对应的键。如果 patch 不是 object,
+// 则当前值会被 patch 替换。
+//
+// patch 中的值会被复制, 因此应用完毕之后, patch 与当前值不会共享任何子成员。
+func (v *V) ApplyMergePatch(patch *V) error {
+	if v == nil || v.valueType == NotExist {
+		return ErrValueUninitialized
+	}
+	if patch == nil {
+		return ErrNilParameter
+	}
+	if patch.valueType == NotExist {
+		return ErrValueUninitialized
+	}
+
+	if patch.valueType != Object {
+		*v = *patch.deepCopy()
+		return nil
+	}
+	if v.valueType != Object {
+		*v = *NewObject()
+	}
+	applyMergePatchToObject(v, patch)
+	return nil
+}
+
+func applyMergePatchToObject(target, patch *V) {
+	patch.RangeObjectsBySetSequence(func(k string, p *V) bool {
+		switch p.valueType {
+		case Null:
+			_ = target.Delete(k)
+		case Object:
+			child, exist := getFromObjectChildren(target, false, k)
+			if !exist || child.valueType != Object {
+				child = NewObject()
+				target.MustSet(child).At(k)
+			}
+			applyMergePatchToObject(child, p)
+		default:
+			target.MustSet(p.deepCopy()).At(k)
+		}
+		return true
+	})
+}
+
+// CreateMergePatch generates a JSON Merge Patch (RFC 7386), which turns original
+// value into modified one when applied by ApplyMergePatch.
+//
+// As null stands for deleting in merge patch, a null member in modified object
+// could not be expressed, no matter whether the key exists in original object
+// or not. Such member is absent after applying the generated patch, unless it
+// is already null in original object.
+//
+// CreateMergePatch 生成一个 JSON Merge Patch (RFC 7386), 使用 ApplyMergePatch 应用该 patch
+// 之后, original 会变为 modified。
+//
+// 由于 null 在 merge patch 中表示删除, 因此无论 original 中是否存在对应的键, modified 中值为 null
+// 的成员都是无法表达的。除非该成员在 original 中已经是 null, 否则应用所生成的 patch 后该成员将不存在。
+func CreateMergePatch(original, modified *V) (*V, error) {
+	if original == nil || modified == nil {
+		return &V{}, ErrNilParameter
+	}
+	if original.valueType == NotExist || modified.valueType == NotExist {
+		return &V{}, ErrValueUninitialized
+	}
+
+	if original.valueType != Object || modified.valueType != Object {
+		return modified.deepCopy(), nil
+	}
+	return createObjectMergePatch(original, modified), nil
+}
+
+func createObjectMergePatch(original, modified *V) *V {
+	patch := NewObject()
+
+	original.RangeObjectsBySetSequence(func(k string, _ *V) bool {
+		if _, exist := getFromObjectChildren(modified, false, k); !exist {
+			patch.MustSetNull().At(k)
+		}
+		return true
+	})
+
+	modified.RangeObjectsBySetSequence(func(k string, m *V) bool {
+		o, exist := getFromObjectChildren(original, false, k)
+		switch {
+		case !exist:
+			patch.MustSet(m.deepCopy()).At(k)
+		case o.valueType == Object && m.valueType == Object:
+			if sub := createObjectMergePatch(o, m); sub.Len() > 0 {
+				patch.MustSet(sub).At(k)
+			}
+		case !o.Equal(m):
+			patch.MustSet(m.deepCopy()).At(k)
+		}
+		return true
+	})
+
+	return patch
+}
@@ -0,0 +1,114 @@
+package jsonvalue
+
+import (
+	"errors"
+	"testing"
+)
+
+func testMergePatch(t *testing.T) {
+	cv("apply merge patch", func() { testApplyMergePatch(t) })
+	cv("create merge patch", func() { testCreateMergePatch(t) })
+}
+
+func testApplyMergePatch(t *testing.T) {
+	cv("RFC 7386 examples", func() {
+		// Appendix A
+		cases := [][3]string{
+			{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+			{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+			{`{"a":"b"}`, `{"a":null}`, `{}`},
+			{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+			{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+			{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+			{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+			{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+			{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+			{`{"a":"b"}`, `["c"]`, `["c"]`},
+			{`{"a":"foo"}`, `null`, `null`},
+			{`{"a":"foo"}`, `"bar"`, `"bar"`},
+			{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+			{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+			{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		}
+		for _, c := range cases {
+			v := MustUnmarshalString(c[0])
+			err := v.ApplyMergePatch(MustUnmarshalString(c[1]))
+			so(err, isNil)
+			so(v.MustMarshalString(OptDefaultStringSequence()), eq, c[2])
+		}
+	})
+
+	cv("no sharing", func() {
+		v := MustUnmarshalString(`{"a":{"x":1}}`)
+		child := v.MustGet("a")
+		patch := MustUnmarshalString(`{"a":{"y":{"z":2}},"b":[1]}`)
+
+		err := v.ApplyMergePatch(patch)
+		so(err, isNil)
+		so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":{"x":1,"y":{"z":2}},"b":[1]}`)
+		so(v.MustGet("a"), eq, child)
+
+		v.MustSet(3).At("a", "y", "z")
+		v.MustAppend(2).InTheEnd("b")
+		so(patch.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":{"y":{"z":2}},"b":[1]}`)
+	})
+
+	cv("errors", func() {
+		v := MustUnmarshalString(`{"a":1}`)
+		so(errors.Is(v.ApplyMergePatch(nil), ErrNilParameter), isTrue)
+		so(errors.Is(v.ApplyMergePatch(&V{}), ErrValueUninitialized), isTrue)
+		so(errors.Is((&V{}).ApplyMergePatch(NewObject()), ErrValueUninitialized), isTrue)
+		so(v.MustMarshalString(), eq, `{"a":1}`)
+	})
+}
+
+func testCreateMergePatch(t *testing.T) {
+	cv("general", func() {
+		cases := [][3]string{
+			{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+			{`{"a":"b"}`, `{"a":"b","b":"c"}`, `{"b":"c"}`},
+			{`{"a":"b","b":"c"}`, `{"b":"c"}`, `{"a":null}`},
+			{`{"a":{"b":"c","d":1.0}}`, `{"a":{"b":"d","d":1}}`, `{"a":{"b":"d"}}`},
+			{`{"a":{"b":"c"}}`, `{"a":{"b":"c"}}`, `{}`},
+			{`{"a":[1,2]}`, `{"a":[1,3]}`, `{"a":[1,3]}`},
+			{`{"a":[1,2]}`, `{"a":{"b":1}}`, `{"a":{"b":1}}`},
+			{`["a"]`, `{"a":1}`, `{"a":1}`},
+			{`{"a":1}`, `[1]`, `[1]`},
+			{`{"a":1}`, `null`, `null`},
+			{`{}`, `{"a":{"b":null}}`, `{"a":{"b":null}}`},
+		}
+		for _, c := range cases {
+			original := MustUnmarshalString(c[0])
+			modified := MustUnmarshalString(c[1])
+			patch, err := CreateMergePatch(original, modified)
+			so(err, isNil)
+			so(patch.MustMarshalString(OptDefaultStringSequence()), eq, c[2])
+			so(original.MustMarshalString(OptDefaultStringSequence()), eq, MustUnmarshalString(c[0]).MustMarshalString(OptDefaultStringSequence()))
+		}
+	})
+
+	cv("round trip", func() {
+		original := MustUnmarshalString(`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`)
+		modified := MustUnmarshalString(`{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`)
+
+		patch, err := CreateMergePatch(original, modified)
+		so(err, isNil)
+		so(patch.MustMarshalString(OptDefaultStringSequence()), eq,
+			`{"author":{"familyName":null},"phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`)
+
+		err = original.ApplyMergePatch(patch)
+		so(err, isNil)
+		so(original.Equal(modified), isTrue)
+	})
+
+	cv("errors", func() {
+		_, err := CreateMergePatch(nil, NewObject())
+		so(errors.Is(err, ErrNilParameter), isTrue)
+		_, err = CreateMergePatch(NewObject(), nil)
+		so(errors.Is(err, ErrNilParameter), isTrue)
+		_, err = CreateMergePatch(&V{}, NewObject())
+		so(errors.Is(err, ErrValueUninitialized), isTrue)
+		_, err = CreateMergePatch(NewObject(), &V{})
+		so(errors.Is(err, ErrValueUninitialized), isTrue)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 )
@@ -24,9 +25,7 @@ func New(value any) *V {
 //
 // NewString 用给定的 string 返回一个初始化好的字符串类型的 jsonvalue 值
 func NewString(s string) *V {
-	v := new(globalPool{}, String)
-	v.valueStr = s
-	return v
+	return newString(globalPool{}, s)
 }
 
 // NewBytes returns an initialized string with Base64 string by given bytes
@@ -97,21 +96,108 @@ func NewUint32(u uint32) *V {
 	return NewUint64(uint64(u))
 }
 
+// NewBigInt returns an initialized num jsonvalue object by big.Int type. Integers
+// beyond the range of int64 and uint64 are supported and marshaled as exact
+// digits. A nil b returns a null value.
+//
+// NewBigInt 用给定的 big.Int 返回一个初始化好的数字类型的 jsonvalue 值。支持超出 int64 和
+// uint64 范围的整数, 并且会序列化为精确的数字。如果 b 为 nil, 则返回 null 值。
+func NewBigInt(b *big.Int) *V {
+	if b == nil {
+		return NewNull()
+	}
+	if b.IsInt64() {
+		return NewInt64(b.Int64())
+	}
+	if b.IsUint64() {
+		return NewUint64(b.Uint64())
+	}
+
+	v := new(globalPool{}, Number)
+	v.num.floated = false
+	v.num.negative = b.Sign() < 0
+	v.num.f64, _ = (&big.Float{}).SetInt(b).Float64()
+	saturateNum(v)
+	v.srcByte = []byte(b.String())
+	return v
+}
+
+// NewBigFloat returns an initialized num jsonvalue object by big.Float type. The
+// number is marshaled as the shortest decimal text which represents f exactly in
+// its precision. A nil f returns a null value, while an infinite f is marshaled
+// in the same way as ±Inf float64.
+//
+// NewBigFloat 用给定的 big.Float 返回一个初始化好的数字类型的 jsonvalue 值。序列化文本为在 f
+// 的精度下能够精确表示 f 的最短十进制文本。如果 f 为 nil, 则返回 null 值; 而无穷大的 f 则按照
+// float64 的 ±Inf 进行序列化。
+func NewBigFloat(f *big.Float) *V {
+	if f == nil {
+		return NewNull()
+	}
+
+	v := new(globalPool{}, Number)
+	v.num.negative = f.Signbit()
+	v.num.f64, _ = f.Float64()
+	if f.IsInf() {
+		v.num.floated = true
+		return v
+	}
+
+	v.num.floated = !f.IsInt()
+	i, _ := f.Int(nil)
+	setSaturatedNum(v, i)
+	v.srcByte = []byte(bigFloatText(f))
+	return v
+}
+
+// setSaturatedNum sets i64 and u64 of number v by its integer part i. They are
+// saturated by saturateNum if i is beyond their range.
+func setSaturatedNum(v *V, i *big.Int) {
+	switch {
+	case i.IsInt64():
+		v.num.i64 = i.Int64()
+		v.num.u64 = uint64(v.num.i64)
+	case i.IsUint64():
+		v.num.u64 = i.Uint64()
+		v.num.i64 = int64(v.num.u64)
+	default:
+		saturateNum(v)
+	}
+}
+
+// saturateNum sets i64 and u64 of number v to their limits according to the
+// sign of v, which is used when the integer part of v is beyond their range.
+func saturateNum(v *V) {
+	if v.num.negative {
+		v.num.i64 = math.MinInt64
+		v.num.u64 = uint64(v.num.i64)
+	} else {
+		v.num.i64 = math.MaxInt64
+		v.num.u64 = math.MaxUint64
+	}
+}
+
+// bigFloatText returns JSON number text of a finite big.Float. Integers less than
+// 2^128 are written without exponent.
+func bigFloatText(f *big.Float) string {
+	if f.IsInt() && f.MantExp(nil) <= 128 {
+		return f.Text('f', -1)
+	}
+	return f.Text('g', -1)
+}
+
 // NewBool returns an initialized boolean jsonvalue object
 //
 // NewBool 用给定的 bool 返回一个初始化好的布尔类型的 jsonvalue 值
 func NewBool(b bool) *V {
-	v := new(globalPool{}, Boolean)
-	v.valueBool = b
-	return v
+	return newBool(globalPool{}, b)
 }
 
 // NewNull returns an initialized null jsonvalue object
 //
 // NewNull 返回一个初始化好的 null 类型的 jsonvalue 值
 func NewNull() *V {
-	v := new(globalPool{}, Null)
-	return v
+	return newNull(globalPool{})
 }
 
 // NewObject returns an object-typed jsonvalue object. If keyValues is specified, it will also create some key-values in
@@ -237,7 +323,10 @@ func new(p pool, t ValueType) *V {
 
 func newObject(p pool) *V {
 	v := new(p, Object)
-	v.children.object = make(map[string]childWithProperty)
+	if v.children.object == nil {
+		// a recycled value may have an empty map already
+		v.children.object = make(map[string]childWithProperty)
+	}
 	v.children.lowerCaseKeys = nil
 	return v
 }
@@ -247,6 +336,33 @@ func newArray(p pool) *V {
 	return v
 }
 
+// leafPool returns the pool for string, boolean and null values. Only Parser
+// puts them into its pool, as it recycles all values on next parsing. For other
+// pools, one retained leaf would otherwise pin the whole slab.
+func leafPool(p pool) pool {
+	if rp, ok := p.(*reusePool); ok {
+		return rp
+	}
+	return globalPool{}
+}
+
+func newString(p pool, s string) *V {
+	v := new(leafPool(p), String)
+	v.valueStr = s
+	return v
+}
+
+func newBool(p pool, b bool) *V {
+	v := new(leafPool(p), Boolean)
+	v.valueBool = b
+	return v
+}
+
+func newNull(p pool) *V {
+	v := new(leafPool(p), Null)
+	return v
+}
+
 func newFloat64f(p pool, f float64, format byte, prec, bitSize int) *V {
 	v := new(p, Number)
 	// v.num = &num{}
@@ -1,6 +1,8 @@
 package jsonvalue
 
 import (
+	"math"
+	"math/big"
 	"testing"
 )
 
@@ -10,6 +12,7 @@ func testNewXxx(t *testing.T) {
 	cv("NewNull", func() { testNewNull(t) })
 	cv("NewIntXxx/UintXxx", func() { testNewInteger(t) })
 	cv("NewFloat64/32", func() { testNewFloat(t) })
+	cv("NewBigInt/BigFloat", func() { testNewBigNumber(t) })
 	cv("empty object/array", func() { testEmptyObjectArray(t) })
 	cv("misc value", func() { testMiscValue(t) })
 	cv("MustMarshal error", func() { testMustMarshalError(t) })
@@ -103,6 +106,93 @@ func testNewFloat(*testing.T) {
 	so(v.ValueType(), eq, Number)
 }
 
+func testNewBigNumber(*testing.T) {
+	cv("big.Int", func() {
+		s := "-123456789012345678901234567890"
+		b, _ := (&big.Int{}).SetString(s, 10)
+		v := NewBigInt(b)
+		so(v.ValueType(), eq, Number)
+		so(v.MustMarshalString(), eq, s)
+		so(v.BigInt().String(), eq, s)
+		so(v.IsNegative(), isTrue)
+		so(v.IsInteger(), isTrue)
+		so(v.Int64(), eq, int64(math.MinInt64))
+
+		b, _ = (&big.Int{}).SetString("18446744073709551616", 10) // 2^64
+		v = NewBigInt(b)
+		so(v.MustMarshalString(), eq, "18446744073709551616")
+		so(v.GreaterThanInt64Max(), isTrue)
+		so(v.Uint64(), eq, uint64(math.MaxUint64))
+		so(v.Float64(), eq, 18446744073709551616.0)
+
+		v = NewBigInt(big.NewInt(-100))
+		so(v.MustMarshalString(), eq, "-100")
+		so(v.Int(), eq, -100)
+		so(v.Equal(NewInt(-100)), isTrue)
+
+		v = NewBigInt(nil)
+		so(v.IsNull(), isTrue)
+	})
+
+	cv("big.Int round trip", func() {
+		for _, s := range []string{
+			"123456789012345678901234567890", "-123456789012345678901234567890",
+			"18446744073709551616", "-9223372036854775809",
+		} {
+			b, _ := (&big.Int{}).SetString(s, 10)
+			o := NewObject()
+			o.MustSet(NewBigInt(b)).At("n")
+			raw := o.MustMarshalString()
+			so(raw, eq, `{"n":`+s+`}`)
+
+			v, err := UnmarshalString(raw)
+			so(err, isNil)
+			n := v.MustGet("n")
+			so(n.BigInt().String(), eq, s)
+			so(n.MustMarshalString(), eq, s)
+			so(n.Equal(NewBigInt(b)), isTrue)
+			so(n.IsInteger(), isTrue)
+			so(n.Float64(), eq, NewBigInt(b).Float64())
+			if b.Sign() < 0 {
+				so(n.IsNegative(), isTrue)
+				so(n.Int64(), eq, int64(math.MinInt64))
+			} else {
+				so(n.GreaterThanInt64Max(), isTrue)
+				so(n.Uint64(), eq, uint64(math.MaxUint64))
+				so(n.Int64(), eq, int64(math.MaxInt64))
+			}
+		}
+	})
+
+	cv("big.Float", func() {
+		f, _, _ := big.ParseFloat("3.14159265358979323846264338327950288", 10, 200, big.ToNearestEven)
+		v := NewBigFloat(f)
+		so(v.MustMarshalString(), eq, f.Text('g', -1))
+		so(v.IsFloat(), isTrue)
+		so(v.Int(), eq, 3)
+		so(v.BigFloat().Text('g', 36), eq, "3.14159265358979323846264338327950288")
+
+		v = NewBigFloat(big.NewFloat(1e30))
+		so(v.MustMarshalString(), eq, "1000000000000000000000000000000")
+		so(v.IsInteger(), isTrue)
+
+		v = NewBigFloat(big.NewFloat(1e300))
+		so(v.MustMarshalString(), eq, "1e+300")
+
+		v = NewBigFloat(big.NewFloat(-0.5))
+		so(v.MustMarshalString(), eq, "-0.5")
+		so(v.IsNegative(), isTrue)
+
+		v = NewBigFloat(big.NewFloat(math.Inf(1)))
+		so(math.IsInf(v.Float64(), 1), isTrue)
+		_, err := v.Marshal()
+		so(err, isErr)
+
+		v = NewBigFloat(nil)
+		so(v.IsNull(), isTrue)
+	})
+}
+
 func testEmptyObjectArray(*testing.T) {
 	v := NewObject()
 	b, _ := v.Marshal()
@@ -615,9 +705,11 @@ func testValueError(*testing.T) {
 		so(err, isErr)
 	})
 
-	cv("another illegal number in array without ]", func() {
-		_, err = UnmarshalString(`[-18446744073709551615 ]`)
-		so(err, isErr)
+	cv("integer beyond int64 in array", func() {
+		v, err := UnmarshalString(`[-18446744073709551615 ]`)
+		so(err, isNil)
+		so(v.MustGet(0).Int64(), eq, int64(math.MinInt64))
+		so(v.MustMarshalString(), eq, `[-18446744073709551615]`)
 	})
 
 	cv("illegal false in array ", func() {
@@ -711,8 +803,9 @@ func testValueError(*testing.T) {
 		_, err = UnmarshalString(`{12345}`) // missing key
 		so(err, isErr)
 
-		_, err = UnmarshalString(`{"big_int":-18446744073709551615}`) // missing key
-		so(err, isErr)
+		v, err := UnmarshalString(`{"big_int":-18446744073709551615}`) // saturated
+		so(err, isNil)
+		so(v.MustGet("big_int").Int64(), eq, int64(math.MinInt64))
 
 		_, err = UnmarshalString(`{"key" "value"}`) // missing colon
 		so(err, isErr)
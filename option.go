@@ -124,6 +124,27 @@ type Opt struct {
 		indent  string
 		cnt     int
 	}
+
+	// numberFormat defines how to normalize numbers
+	numberFormat numberFormat
+}
+
+type numberFormat struct {
+	shortest          bool
+	noExponent        bool
+	integralAsInteger bool
+	largeIntAsString  bool
+	places            []numberDecimalPlaces
+
+	trackPath bool
+	path      []string // tokens of current marshaling value
+}
+
+// numberDecimalPlaces is generated by OptNumberDecimalPlaces. Nil tokens match
+// all numbers.
+type numberDecimalPlaces struct {
+	tokens [][]string
+	places int32
 }
 
 type FloatNaNHandleType uint8
@@ -634,3 +655,120 @@ func (o optionIndent) mergeTo(opt *Opt) {
 	opt.indent.prefix = o[0]
 	opt.indent.indent = o[1]
 }
+
+// ==== number normalization ====
+
+// OptNumberShortest marshals numbers which are not integer texts in the shortest
+// form that parses back to the same float64 value, formatted as ECMAScript does.
+// For example, 1.50 becomes 1.5 and 100e-2 becomes 1. Integer texts are kept
+// to avoid losing precision.
+//
+// OptNumberShortest 表示对于非整数文本的数字, 按照能够解析回相同 float64 值的最短形式进行序列化,
+// 格式与 ECMAScript 相同。比如 1.50 会变为 1.5, 100e-2 会变为 1。整数文本保持不变, 以免丢失精度。
+func OptNumberShortest() Option {
+	return optNumberShortest{}
+}
+
+type optNumberShortest struct{}
+
+func (optNumberShortest) mergeTo(opt *Opt) {
+	opt.numberFormat.shortest = true
+}
+
+// OptNumberDecimalPlaces marshals numbers located by given JSON Pointers with
+// exactly given decimal places, rounding half away from zero. A "*" token
+// matches any key or index, such as "/items/*/price". If no pointer is given,
+// all numbers are affected. Illegal pointers are ignored. If multiple options
+// match a number, the last one takes effect. A number too small to affect any
+// decimal place, such as 1e-10000000, becomes zero, while a number which needs
+// more than 1024 digits to be written in this way keeps its original form.
+//
+// OptNumberDecimalPlaces 表示将给定 JSON Pointer 所指定的数字按照指定的小数位数进行序列化,
+// 采用四舍五入 (远离零方向)。"*" 可以匹配任意键或下标, 如 "/items/*/price"。如果未指定
+// pointer, 则影响所有数字。不合法的 pointer 会被忽略。如果多个选项匹配同一个数字, 则最后一个生效。
+// 小到不影响任何小数位的数字, 如 1e-10000000, 会变为零; 而需要超过 1024 位才能以这种方式表示的数字则保持原样。
+func OptNumberDecimalPlaces(places int, pointers ...string) Option {
+	o := optNumberDecimalPlaces{places: int32(places)}
+	if places < 0 {
+		o.places = 0
+	}
+	for _, ptr := range pointers {
+		if tokens, err := parsePointer(ptr); err == nil {
+			o.tokens = append(o.tokens, tokens)
+		}
+	}
+	if len(pointers) > 0 && len(o.tokens) == 0 {
+		o.ignored = true
+	}
+	return o
+}
+
+type optNumberDecimalPlaces struct {
+	tokens  [][]string
+	places  int32
+	ignored bool
+}
+
+func (o optNumberDecimalPlaces) mergeTo(opt *Opt) {
+	if o.ignored {
+		return
+	}
+	nf := &opt.numberFormat
+	nf.places = append(nf.places[:len(nf.places):len(nf.places)], numberDecimalPlaces{
+		tokens: o.tokens,
+		places: o.places,
+	})
+	if len(o.tokens) > 0 {
+		nf.trackPath = true
+	}
+}
+
+// OptNumberNoExponent marshals numbers without exponent notation, for example
+// 1.5e3 becomes 1500 and 1E-7 becomes 0.0000001. The value is kept exactly. A
+// number which needs more than 1024 digits without exponent, such as
+// 1e-10000000, keeps its exponent.
+//
+// OptNumberNoExponent 表示序列化数字时不使用指数形式, 比如 1.5e3 会变为 1500, 1E-7 会变为
+// 0.0000001。数值会被精确保留。如果数字不使用指数形式时需要超过 1024 位, 如 1e-10000000,
+// 则保留其指数形式。
+func OptNumberNoExponent() Option {
+	return optNumberNoExponent{}
+}
+
+type optNumberNoExponent struct{}
+
+func (optNumberNoExponent) mergeTo(opt *Opt) {
+	opt.numberFormat.noExponent = true
+}
+
+// OptNumberIntegralAsInteger marshals numbers with integral values as integers,
+// for example 100.0 and 1e2 become 100. Like OptNumberNoExponent, integers
+// with more than 1024 digits keep their exponents.
+//
+// OptNumberIntegralAsInteger 表示将值为整数的数字序列化为整数形式, 比如 100.0 和 1e2 都会
+// 变为 100。与 OptNumberNoExponent 相同, 超过 1024 位的整数会保留其指数形式。
+func OptNumberIntegralAsInteger() Option {
+	return optNumberIntegralAsInteger{}
+}
+
+type optNumberIntegralAsInteger struct{}
+
+func (optNumberIntegralAsInteger) mergeTo(opt *Opt) {
+	opt.numberFormat.integralAsInteger = true
+}
+
+// OptNumberLargeIntAsString marshals integers beyond the safe integer range of
+// JavaScript (±(2^53-1)) as strings, for example 9007199254740993 becomes
+// "9007199254740993", so that JavaScript clients would not lose precision.
+//
+// OptNumberLargeIntAsString 表示将超出 JavaScript 安全整数范围 (±(2^53-1)) 的整数序列化为
+// 字符串, 比如 9007199254740993 会变为 "9007199254740993", 从而避免 JavaScript 客户端丢失精度。
+func OptNumberLargeIntAsString() Option {
+	return optNumberLargeIntAsString{}
+}
+
+type optNumberLargeIntAsString struct{}
+
+func (optNumberLargeIntAsString) mergeTo(opt *Opt) {
+	opt.numberFormat.largeIntAsString = true
+}
@@ -2,7 +2,9 @@ package jsonvalue
 
 import (
 	"encoding/json"
+	"math"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -17,6 +19,7 @@ func testOption(t *testing.T) {
 	cv("test OptSetSequence", func() { testOption_OptSetSequence(t) })
 	cv("test OptIgnoreOmitempty", func() { testOption_OptIgnoreOmitempty(t) })
 	cv("test Issue #29", func() { testOption_Issue29(t) })
+	cv("test number normalization", func() { testOption_NumberNormalization(t) })
 }
 
 func testOptionOverwriting(*testing.T) {
@@ -166,3 +169,104 @@ func (s issue29Struct) MarshalJSON() ([]byte, error) {
 }
 
 type issue29StructWrapper issue29Struct
+
+func testOption_NumberNormalization(t *testing.T) {
+	raw := `[1e2, 100, 100.0, 1.50, 0.1e1, -2.5E-7, 123456789012345678, 1e21]`
+	v := MustUnmarshalString(raw)
+
+	cv("no option", func() {
+		so(v.MustMarshalString(), eq, `[1e2,100,100.0,1.50,0.1e1,-2.5E-7,123456789012345678,1e21]`)
+	})
+
+	cv("OptNumberShortest", func() {
+		s := v.MustMarshalString(OptNumberShortest())
+		so(s, eq, `[100,100,100,1.5,1,-2.5e-7,123456789012345678,1e+21]`)
+	})
+
+	cv("OptNumberNoExponent", func() {
+		s := v.MustMarshalString(OptNumberNoExponent())
+		so(s, eq, `[100,100,100.0,1.50,1,-0.00000025,123456789012345678,1000000000000000000000]`)
+
+		s = v.MustMarshalString(OptNumberShortest(), OptNumberNoExponent())
+		so(s, eq, `[100,100,100,1.5,1,-0.00000025,123456789012345678,1000000000000000000000]`)
+	})
+
+	cv("OptNumberIntegralAsInteger", func() {
+		s := v.MustMarshalString(OptNumberIntegralAsInteger())
+		so(s, eq, `[100,100,100,1.50,1,-2.5E-7,123456789012345678,1000000000000000000000]`)
+
+		f := NewFloat64f(3, 'f', 2)
+		so(f.MustMarshalString(), eq, "3.00")
+		so(f.MustMarshalString(OptNumberIntegralAsInteger()), eq, "3")
+	})
+
+	cv("OptNumberLargeIntAsString", func() {
+		s := v.MustMarshalString(OptNumberLargeIntAsString())
+		so(s, eq, `[1e2,100,100.0,1.50,0.1e1,-2.5E-7,"123456789012345678",1e21]`)
+
+		s = v.MustMarshalString(OptNumberLargeIntAsString(), OptNumberIntegralAsInteger())
+		so(s, eq, `[100,100,100,1.50,1,-2.5E-7,"123456789012345678","1000000000000000000000"]`)
+
+		arr := NewArray()
+		arr.MustAppendInt64(9007199254740991).InTheEnd()
+		arr.MustAppendInt64(9007199254740992).InTheEnd()
+		arr.MustAppendInt64(-9007199254740991).InTheEnd()
+		arr.MustAppendInt64(-9007199254740992).InTheEnd()
+		arr.MustAppendUint64(18446744073709551615).InTheEnd()
+		s = arr.MustMarshalString(OptNumberLargeIntAsString())
+		so(s, eq, `[9007199254740991,"9007199254740992",-9007199254740991,"-9007199254740992","18446744073709551615"]`)
+	})
+
+	cv("OptNumberDecimalPlaces", func() {
+		s := v.MustMarshalString(OptNumberDecimalPlaces(2))
+		so(s, eq, `[100.00,100.00,100.00,1.50,1.00,0.00,123456789012345678.00,1000000000000000000000.00]`)
+
+		o := MustUnmarshalString(`{"items":[{"price":1.005,"qty":2.0},{"price":2,"qty":1}],"total":5.0149,"rate":0.125}`)
+		opts := []Option{
+			OptNumberDecimalPlaces(2, "/items/*/price", "/total"),
+			OptNumberDecimalPlaces(0, "/items/*/qty"),
+			OptKeySequence([]string{"items", "price", "qty", "total", "rate"}),
+		}
+		s = o.MustMarshalString(opts...)
+		so(s, eq, `{"items":[{"price":1.01,"qty":2},{"price":2.00,"qty":1}],"total":5.01,"rate":0.125}`)
+
+		// the latter option takes effect
+		s = o.MustMarshalString(append(opts, OptNumberDecimalPlaces(1, "/rate", "/total"))...)
+		so(s, eq, `{"items":[{"price":1.01,"qty":2},{"price":2.00,"qty":1}],"total":5.0,"rate":0.1}`)
+
+		// set sequence and less func also track paths
+		s = o.MustMarshalString(OptNumberDecimalPlaces(1, "/items/1/qty"), OptDefaultStringSequence())
+		so(s, eq, `{"items":[{"price":1.005,"qty":2.0},{"price":2,"qty":1.0}],"rate":0.125,"total":5.0149}`)
+
+		// illegal pointers are ignored
+		s = o.MustMarshalString(OptNumberDecimalPlaces(1, "rate"), OptDefaultStringSequence())
+		so(s, eq, `{"items":[{"price":1.005,"qty":2.0},{"price":2,"qty":1}],"rate":0.125,"total":5.0149}`)
+	})
+
+	cv("extreme exponents are not expanded", func() {
+		v := MustUnmarshalString(`[1e-10000000,-2.5e-10000000,1e-1023,1e-1024]`)
+
+		s := v.MustMarshalString(OptNumberNoExponent())
+		so(s, eq, `[1e-10000000,-2.5e-10000000,0.`+strings.Repeat("0", 1022)+`1,1e-1024]`)
+
+		s = v.MustMarshalString(OptNumberIntegralAsInteger())
+		so(s, eq, `[1e-10000000,-2.5e-10000000,1e-1023,1e-1024]`)
+
+		s = v.MustMarshalString(OptNumberDecimalPlaces(2))
+		so(s, eq, `[0.00,0.00,0.00,0.00]`)
+
+		s = MustUnmarshalString(`[-4e-3,5e-3,4e-4,1.5]`).MustMarshalString(OptNumberDecimalPlaces(2))
+		so(s, eq, `[0.00,0.01,0.00,1.50]`)
+
+		s = MustUnmarshalString(`[1.5]`).MustMarshalString(OptNumberDecimalPlaces(2000))
+		so(s, eq, `[1.5]`)
+	})
+
+	cv("NaN and Inf are not affected", func() {
+		arr := NewArray()
+		arr.MustAppendFloat64(math.NaN()).InTheEnd()
+		arr.MustAppendFloat64(1.25).InTheEnd()
+		s := arr.MustMarshalString(OptFloatNaNToNull(), OptNumberDecimalPlaces(1), OptNumberShortest())
+		so(s, eq, `[null,1.3]`)
+	})
+}
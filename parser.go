@@ -0,0 +1,159 @@
+package jsonvalue
+
+import (
+	"sync"
+
+	"github.com/Andrew-M-C/go.jsonvalue/internal/unsafe"
+)
+
+// ================ REUSABLE PARSER ================
+
+// Parser is a reusable JSON parser. Each unmarshal call recycles values, maps
+// and slices of the tree returned by the previous call, so a long-lived Parser
+// could parse lots of messages with nearly no allocation.
+//
+// Values returned by a Parser, including all their sub values, are only valid
+// until the next unmarshal call or Release. Strings read from those values are
+// not affected. A Parser is not safe for concurrent use.
+//
+// Parser 是一个可重复使用的 JSON 解析器。每次解析时都会回收上一次解析所返回的值树中的值、map 和切片, 因此
+// 长期存在的 Parser 可以在几乎不分配内存的情况下解析大量的消息。
+//
+// Parser 返回的值, 包括其中的所有子值, 仅在下一次解析或调用 Release 之前有效。从这些值中读取到的字符串则不受
+// 影响。Parser 不能并发使用。
+type Parser struct {
+	pool reusePool
+}
+
+var parserPool = sync.Pool{
+	New: func() any {
+		return &Parser{}
+	},
+}
+
+// NewParser returns a Parser from a global pool. Call Release to put it back
+// after use.
+//
+// NewParser 从全局池中取出一个 Parser。使用完毕后请调用 Release 将其放回。
+func NewParser() *Parser {
+	return parserPool.Get().(*Parser)
+}
+
+// Release recycles values returned by the Parser and puts the Parser back to
+// the global pool. Neither the Parser nor values returned by it should be used
+// any more after Release.
+//
+// Release 回收 Parser 返回的值, 并将 Parser 放回全局池中。调用 Release 之后, Parser 及其返回的值都不应
+// 再被使用。
+func (p *Parser) Release() {
+	p.pool.reset()
+	parserPool.Put(p)
+}
+
+// Unmarshal parses raw bytes like the package function Unmarshal, but with
+// values recycled from the previous call.
+//
+// Unmarshal 与包函数 Unmarshal 一样解析原始字节数据, 但是会复用上一次调用所回收的值。
+func (p *Parser) Unmarshal(b []byte) (*V, error) {
+	le := len(b)
+	if le == 0 {
+		return nil, ErrNilParameter
+	}
+
+	trueB := make([]byte, le)
+	copy(trueB, b)
+	v, err := p.unmarshalNoCopy(trueB)
+	if err != nil {
+		return v, locateSyntaxError(err, b)
+	}
+	return v, nil
+}
+
+// UnmarshalString is equivalent to Unmarshal([]byte(s)).
+//
+// UnmarshalString 等效于 Unmarshal([]byte(s))。
+func (p *Parser) UnmarshalString(s string) (*V, error) {
+	if len(s) == 0 {
+		return nil, ErrNilParameter
+	}
+	v, err := p.unmarshalNoCopy([]byte(s))
+	if err != nil {
+		return v, locateSyntaxError(err, unsafe.StoB(s))
+	}
+	return v, nil
+}
+
+// UnmarshalNoCopy is same as Unmarshal, but the input bytes are used as buffer
+// directly and may be modified, just like the package function UnmarshalNoCopy.
+// With this function, no allocation is needed once the Parser is warmed up.
+//
+// UnmarshalNoCopy 与 Unmarshal 相同, 但是与包函数 UnmarshalNoCopy 一样, 传入的字节数据会被直接用作缓冲区
+// 并可能会被修改。使用这个函数时, Parser 在预热之后就不再需要分配内存了。
+func (p *Parser) UnmarshalNoCopy(b []byte) (*V, error) {
+	if len(b) == 0 {
+		return &V{}, ErrNilParameter
+	}
+	v, err := p.unmarshalNoCopy(b)
+	if err != nil {
+		return v, locateSyntaxError(err, b)
+	}
+	return v, nil
+}
+
+func (p *Parser) unmarshalNoCopy(b []byte) (*V, error) {
+	p.pool.reset()
+	return unmarshalWithIter(&p.pool, iter(b), 0)
+}
+
+// reusePoolMinBlockSize is the size of the first block of reusePool.
+const reusePoolMinBlockSize = 64
+
+// reusePool hands out values from blocks. All values are recycled by reset,
+// while maps and slices of them are kept for next use.
+type reusePool struct {
+	blocks [][]V
+	block  int // index of current block
+	used   int // used values in current block
+}
+
+func (p *reusePool) get() *V {
+	for p.block < len(p.blocks) {
+		b := p.blocks[p.block]
+		if p.used < len(b) {
+			v := &b[p.used]
+			p.used++
+			v.recycle()
+			return v
+		}
+		p.block++
+		p.used = 0
+	}
+
+	size := reusePoolMinBlockSize
+	if n := len(p.blocks); n > 0 {
+		size = 2 * len(p.blocks[n-1])
+	}
+	p.blocks = append(p.blocks, make([]V, size))
+	p.used = 1
+	return &p.blocks[p.block][0]
+}
+
+func (p *reusePool) reset() {
+	p.block = 0
+	p.used = 0
+}
+
+// recycle resets v to zero value, but keeps its empty map and slice.
+func (v *V) recycle() {
+	arr, obj := v.children.arr[:cap(v.children.arr)], v.children.object
+	for i := range arr {
+		arr[i] = nil
+	}
+	for k := range obj {
+		delete(obj, k)
+	}
+
+	*v = V{}
+	v.children.arr = arr[:0]
+	v.children.object = obj
+}
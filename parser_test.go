@@ -0,0 +1,133 @@
+package jsonvalue
+
+import (
+	"errors"
+	"testing"
+)
+
+func testParser(t *testing.T) {
+	cv("same as unmarshal", func() { testParserSameAsUnmarshal(t) })
+	cv("reuse values", func() { testParserReuse(t) })
+	cv("allocations", func() { testParserAllocations(t) })
+	cv("errors", func() { testParserErrors(t) })
+}
+
+func testParserSameAsUnmarshal(t *testing.T) {
+	raws := []string{
+		`{"a":[1,-2,3.5,{"b":"c\n中"}],"d":true,"e":null,"f":{},"g":[]}`,
+		`[{"k":false},[[null]],"str",18446744073709551615]`,
+		`"only string"`, `123`, `true`, `null`,
+	}
+	p := NewParser()
+	defer p.Release()
+
+	for _, raw := range raws {
+		full := MustUnmarshalString(raw)
+
+		v, err := p.Unmarshal([]byte(raw))
+		so(err, isNil)
+		so(v.Equal(full), isTrue)
+
+		v, err = p.UnmarshalString(raw)
+		so(err, isNil)
+		so(v.Equal(full), isTrue)
+
+		v, err = p.UnmarshalNoCopy([]byte(raw))
+		so(err, isNil)
+		so(v.Equal(full), isTrue)
+		so(v.MustMarshalString(OptSetSequence()), eq, full.MustMarshalString(OptSetSequence()))
+	}
+}
+
+func testParserReuse(t *testing.T) {
+	p := &Parser{}
+
+	v1, err := p.UnmarshalString(`{"a":{"b":[1,2,3]},"c":"str"}`)
+	so(err, isNil)
+	s := v1.MustGet("c").String()
+	v1.MustGet("a").Caseless().Get("B")
+	v1.MustSet(NewObject(map[string]any{"x": 1})).At("added")
+
+	v2, err := p.UnmarshalString(`[{"d":1},"e",[4]]`)
+	so(err, isNil)
+	so(v2 == v1, isTrue) // the root value is recycled
+	so(v2.MustMarshalString(), eq, `[{"d":1},"e",[4]]`)
+	so(s, eq, "str")
+
+	// recycled maps and slices are cleared
+	v3, err := p.UnmarshalString(`{"b":[]}`)
+	so(err, isNil)
+	so(v3.MustMarshalString(), eq, `{"b":[]}`)
+	so(v3.MustGet("b").Len(), eq, 0)
+	v3.MustAppend(1).InTheEnd("b")
+	so(v3.MustMarshalString(), eq, `{"b":[1]}`)
+
+	// values are recycled as different types
+	v4, err := p.UnmarshalString(`[[],{},1,"a",null]`)
+	so(err, isNil)
+	so(v4.MustMarshalString(), eq, `[[],{},1,"a",null]`)
+	so(v4.MustGet(0).Len(), eq, 0)
+	so(v4.MustGet(1).Len(), eq, 0)
+
+	// more values than the first block
+	raw := "[" + repeatString(`{"k":[1,"v"]},`, 200) + `0]`
+	v5, err := p.UnmarshalString(raw)
+	so(err, isNil)
+	so(v5.Equal(MustUnmarshalString(raw)), isTrue)
+	so(len(p.pool.blocks) >= 2, isTrue)
+
+	p.Release()
+	p = NewParser()
+	v6, err := p.UnmarshalString(`{"z":0}`)
+	so(err, isNil)
+	so(v6.MustMarshalString(), eq, `{"z":0}`)
+	p.Release()
+}
+
+func repeatString(s string, n int) string {
+	res := ""
+	for i := 0; i < n; i++ {
+		res += s
+	}
+	return res
+}
+
+func testParserAllocations(t *testing.T) {
+	raw := `{"a":[1,2,3,{"b":"c\n"}],"d":true,"e":null,"f":1.5,"g":{"h":"i"}}`
+	p := &Parser{}
+	buf := make([]byte, len(raw))
+
+	n := testing.AllocsPerRun(100, func() {
+		copy(buf, raw)
+		v, _ := p.UnmarshalNoCopy(buf)
+		v.MustGet("g", "h")
+	})
+	so(n, eq, 0)
+}
+
+func testParserErrors(t *testing.T) {
+	p := NewParser()
+	defer p.Release()
+
+	_, err := p.Unmarshal(nil)
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = p.UnmarshalString("")
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = p.UnmarshalNoCopy(nil)
+	so(errors.Is(err, ErrNilParameter), isTrue)
+
+	_, err = p.UnmarshalString("{\n\"a\":}")
+	so(errors.Is(err, ErrNotObjectValue), isTrue)
+	var se *SyntaxError
+	so(errors.As(err, &se), isTrue)
+	so(se.Line, eq, 2)
+	_, err = p.Unmarshal([]byte(`[1,`))
+	so(err, isErr)
+	_, err = p.UnmarshalNoCopy([]byte(`tru`))
+	so(err, isErr)
+
+	// parser is still usable after errors
+	v, err := p.UnmarshalString(`{"a":1}`)
+	so(err, isNil)
+	so(v.MustGet("a").Int(), eq, 1)
+}
@@ -0,0 +1,555 @@
+package jsonvalue
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ================ JSON PATCH ================
+
+// Reference: [RFC 6902 - JavaScript Object Notation (JSON) Patch](https://www.rfc-editor.org/rfc/rfc6902)
+
+// PatchError describes a failed operation in a JSON Patch document.
+//
+// PatchError 表示 JSON Patch 文档中一个执行失败的操作。
+type PatchError struct {
+	Index int    // index of the operation in patch document, starting from 0
+	Op    string // operation name, such as "add"
+	Err   error
+}
+
+// Error implements error interface.
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s): %v", e.Index, e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) document to current value.
+// Operations "add", "remove", "replace", "move", "copy" and "test" are
+// supported.
+//
+// Patch is applied atomically on a copy of current value. If any operation
+// fails, a *PatchError will be returned and current value is left untouched.
+// Otherwise, the copy takes the place of current value, therefore children got
+// from current value before patching are no longer parts of it.
+//
+// ApplyPatch 将一个 JSON Patch (RFC 6902) 文档应用到当前值上。支持 "add", "remove",
+// "replace", "move", "copy" 和 "test" 操作。
+//
+// Patch 是原子性地应用在当前值的副本上的。如果任何一个操作失败, 则返回 *PatchError, 并且当前值不会被修改;
+// 否则该副本会取代当前值, 因此在应用 patch 之前从当前值获取的子成员将不再属于当前值。
+func (v *V) ApplyPatch(patch *V) error {
+	if v == nil || v.valueType == NotExist {
+		return ErrValueUninitialized
+	}
+	if patch == nil {
+		return ErrNilParameter
+	}
+
+	ops, err := parsePatch(patch)
+	if err != nil {
+		return err
+	}
+
+	// Operations are applied on a copy, so that the original value keeps
+	// untouched if any operation fails. The result then takes the place of the
+	// original value.
+	doc := v.deepCopy()
+	if err := applyPatchOperations(doc, ops); err != nil {
+		return err
+	}
+	*v = *doc
+	return nil
+}
+
+type patchOperation struct {
+	op    string
+	path  []string
+	from  []string
+	value *V
+}
+
+func parsePatch(patch *V) ([]*patchOperation, error) {
+	if patch.valueType != Array {
+		return nil, fmt.Errorf("%w, patch document should be an array", ErrInvalidPatch)
+	}
+
+	c := patch.loadedChildren()
+	ops := make([]*patchOperation, 0, len(c.arr))
+	for i, item := range c.arr {
+		op, err := parsePatchOperation(item)
+		if err != nil {
+			return nil, &PatchError{Index: i, Op: op.op, Err: err}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func parsePatchOperation(item *V) (*patchOperation, error) {
+	op := &patchOperation{}
+	if item.valueType != Object {
+		return op, fmt.Errorf("%w, operation should be an object", ErrInvalidPatch)
+	}
+
+	name, err := item.GetString("op")
+	if err != nil {
+		return op, fmt.Errorf("%w, missing string member 'op'", ErrInvalidPatch)
+	}
+	op.op = name
+
+	path, err := item.GetString("path")
+	if err != nil {
+		return op, fmt.Errorf("%w, missing string member 'path'", ErrInvalidPatch)
+	}
+	if op.path, err = parsePointer(path); err != nil {
+		return op, err
+	}
+
+	switch name {
+	default:
+		return op, fmt.Errorf("%w, unknown operation '%s'", ErrInvalidPatch, name)
+
+	case "remove":
+		// nothing more is needed
+
+	case "add", "replace", "test":
+		value, exist := getFromObjectChildren(item, false, "value")
+		if !exist {
+			return op, fmt.Errorf("%w, missing member 'value'", ErrInvalidPatch)
+		}
+		op.value = value
+
+	case "move", "copy":
+		from, err := item.GetString("from")
+		if err != nil {
+			return op, fmt.Errorf("%w, missing string member 'from'", ErrInvalidPatch)
+		}
+		if op.from, err = parsePointer(from); err != nil {
+			return op, err
+		}
+		if name == "move" && isProperPointerPrefix(op.from, op.path) {
+			return op, fmt.Errorf("%w, cannot move a value into one of its children", ErrInvalidPatch)
+		}
+	}
+	return op, nil
+}
+
+func isProperPointerPrefix(prefix, tokens []string) bool {
+	if len(prefix) >= len(tokens) {
+		return false
+	}
+	for i, t := range prefix {
+		if tokens[i] != t {
+			return false
+		}
+	}
+	return true
+}
+
+func applyPatchOperations(doc *V, ops []*patchOperation) error {
+	for i, op := range ops {
+		if err := op.apply(doc); err != nil {
+			return &PatchError{Index: i, Op: op.op, Err: err}
+		}
+	}
+	return nil
+}
+
+func (op *patchOperation) apply(doc *V) error {
+	switch op.op {
+	default: // "add"
+		return patchAdd(doc, op.path, op.value.deepCopy())
+
+	case "remove":
+		_, err := patchRemove(doc, op.path)
+		return err
+
+	case "replace":
+		return patchReplace(doc, op.path, op.value.deepCopy())
+
+	case "move":
+		if pointerTokensEqual(op.from, op.path) {
+			_, err := getByPointerTokens(doc, op.from)
+			return err
+		}
+		v, err := patchRemove(doc, op.from)
+		if err != nil {
+			return err
+		}
+		if len(op.path) == 0 {
+			// the root takes over the content of v, which should not be shared
+			// with v itself
+			v = v.deepCopy()
+		}
+		return patchAdd(doc, op.path, v)
+
+	case "copy":
+		v, err := getByPointerTokens(doc, op.from)
+		if err != nil {
+			return err
+		}
+		return patchAdd(doc, op.path, v.deepCopy())
+
+	case "test":
+		v, err := getByPointerTokens(doc, op.path)
+		if err != nil {
+			return err
+		}
+		if !v.Equal(op.value) {
+			return ErrPatchTestFailed
+		}
+		return nil
+	}
+}
+
+func pointerTokensEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, t := range a {
+		if b[i] != t {
+			return false
+		}
+	}
+	return true
+}
+
+// patchAdd adds a value at given location. For an array, the value is inserted
+// before the element at given index.
+func patchAdd(doc *V, path []string, v *V) error {
+	if len(path) == 0 {
+		*doc = *v
+		return nil
+	}
+
+	parent, err := getByPointerTokens(doc, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	last := path[len(path)-1]
+	switch parent.valueType {
+	case Object:
+		_, err := parent.Set(v).At(last)
+		return err
+	case Array:
+		pos, err := pointerTokenToIndex(parent, last, true)
+		if err != nil {
+			return err
+		}
+		if pos == len(parent.loadedChildren().arr) {
+			_, err = parent.Append(v).InTheEnd()
+		} else {
+			_, err = parent.Insert(v).Before(pos)
+		}
+		return err
+	default:
+		return fmt.Errorf("%v type does not supports adding children", parent.valueType)
+	}
+}
+
+// patchReplace replaces an existing value at given location.
+func patchReplace(doc *V, path []string, v *V) error {
+	if _, err := getByPointerTokens(doc, path); err != nil {
+		return err
+	}
+	if len(path) == 0 {
+		*doc = *v
+		return nil
+	}
+
+	parent, _ := getByPointerTokens(doc, path[:len(path)-1])
+	last := path[len(path)-1]
+	if parent.valueType == Object {
+		_, err := parent.Set(v).At(last)
+		return err
+	}
+	pos, _ := pointerTokenToIndex(parent, last, false)
+	_, err := parent.Set(v).At(pos)
+	return err
+}
+
+// patchRemove removes and returns the value at given location.
+func patchRemove(doc *V, path []string) (*V, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w, cannot remove the root value", ErrInvalidPatch)
+	}
+
+	v, err := getByPointerTokens(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	parent, _ := getByPointerTokens(doc, path[:len(path)-1])
+
+	last := path[len(path)-1]
+	if parent.valueType == Object {
+		return v, parent.Delete(last)
+	}
+	pos, _ := pointerTokenToIndex(parent, last, false)
+	return v, parent.Delete(pos)
+}
+
+// CreatePatch generates a JSON Patch (RFC 6902) document which turns from into
+// to. Arrays are compared with LCS (longest common subsequence) algorithm, so
+// that only changed elements are added, removed or moved, instead of replacing
+// the whole array.
+//
+// CreatePatch 生成一个 JSON Patch (RFC 6902) 文档, 该文档可以将 from 转换为 to。数组使用 LCS
+// (最长公共子序列) 算法进行比较, 因此只会对变化了的成员进行添加、删除或移动操作, 而不是替换整个数组。
+func CreatePatch(from, to *V) (*V, error) {
+	if from == nil || to == nil {
+		return &V{}, ErrNilParameter
+	}
+	if from.valueType == NotExist || to.valueType == NotExist {
+		return &V{}, ErrValueUninitialized
+	}
+
+	g := patchGenerator{
+		patch: NewArray(),
+	}
+	g.diff("", from, to)
+	return g.patch, nil
+}
+
+type patchGenerator struct {
+	patch *V
+}
+
+func (g *patchGenerator) addOperation(op, from, path string, value *V) {
+	o := NewObject()
+	o.MustSetString(op).At("op")
+	if op == "move" {
+		o.MustSetString(from).At("from")
+	}
+	o.MustSetString(path).At("path")
+	if value != nil {
+		o.MustSet(value.deepCopy()).At("value")
+	}
+	g.patch.MustAppend(o).InTheEnd()
+}
+
+func (g *patchGenerator) diff(path string, from, to *V) {
+	switch {
+	case from.valueType != to.valueType:
+		g.addOperation("replace", "", path, to)
+	case from.valueType == Object:
+		g.diffObject(path, from, to)
+	case from.valueType == Array:
+		g.diffArray(path, from, to)
+	case !from.Equal(to):
+		g.addOperation("replace", "", path, to)
+	}
+}
+
+func (g *patchGenerator) diffObject(path string, from, to *V) {
+	from.RangeObjectsBySetSequence(func(k string, f *V) bool {
+		subPath := path + "/" + pointerTokenEscaper.Replace(k)
+		if t, exist := getFromObjectChildren(to, false, k); exist {
+			g.diff(subPath, f, t)
+		} else {
+			g.addOperation("remove", "", subPath, nil)
+		}
+		return true
+	})
+
+	to.RangeObjectsBySetSequence(func(k string, t *V) bool {
+		if _, exist := getFromObjectChildren(from, false, k); !exist {
+			g.addOperation("add", "", path+"/"+pointerTokenEscaper.Replace(k), t)
+		}
+		return true
+	})
+}
+
+// diffArray generates operations for arrays as following steps:
+//
+//  1. Elements in LCS of the two arrays keep untouched.
+//  2. Other elements equal to each other are moved.
+//  3. Remaining elements in the same gap between LCS elements are paired in
+//     sequence and modified in place.
+//  4. Remaining elements in from are removed, while those in to are added.
+func (g *patchGenerator) diffArray(path string, from, to *V) {
+	fromArr, toArr := from.loadedChildren().arr, to.loadedChildren().arr
+	fromPair, toPair := arrayLCS(fromArr, toArr)
+
+	// gap indexes of elements, namely how many LCS elements are before it
+	fromGap, toGap := arrayGaps(fromPair), arrayGaps(toPair)
+
+	// step 2: moves
+	moveFrom := make([]int, len(toArr)) // to index -> from index of moved elements
+	moved := make([]bool, len(fromArr))
+	for j, t := range toArr {
+		moveFrom[j] = -1
+		if toPair[j] >= 0 {
+			continue
+		}
+		for i, f := range fromArr {
+			if fromPair[i] < 0 && !moved[i] && f.Equal(t) {
+				moveFrom[j], moved[i] = i, true
+				break
+			}
+		}
+	}
+
+	// step 3: modifications, which are paired as LCS elements
+	type modification struct{ from, to int }
+	var modifications []modification
+	fromByGap := map[int][]int{}
+	for i := range fromArr {
+		if fromPair[i] < 0 && !moved[i] {
+			fromByGap[fromGap[i]] = append(fromByGap[fromGap[i]], i)
+		}
+	}
+	for j := range toArr {
+		if toPair[j] >= 0 || moveFrom[j] >= 0 {
+			continue
+		}
+		candidates := fromByGap[toGap[j]]
+		if len(candidates) == 0 {
+			continue
+		}
+		i := candidates[0]
+		fromByGap[toGap[j]] = candidates[1:]
+		fromPair[i], toPair[j] = j, i
+		modifications = append(modifications, modification{i, j})
+	}
+
+	// step 4: removing in descending order, so that indexes keep valid
+	for i := len(fromArr) - 1; i >= 0; i-- {
+		if fromPair[i] < 0 && !moved[i] {
+			g.addOperation("remove", "", path+"/"+strconv.Itoa(i), nil)
+		}
+	}
+
+	// Each element in to is placed right after the previous one. Elements in
+	// current array are identified by index in from, or len(from) + index in to
+	// for added ones.
+	var cur []int
+	for i := range fromArr {
+		if fromPair[i] >= 0 || moved[i] {
+			cur = append(cur, i)
+		}
+	}
+	indexOf := func(id int) int {
+		for idx, c := range cur {
+			if c == id {
+				return idx
+			}
+		}
+		return -1
+	}
+
+	prevID := -1
+	for j, t := range toArr {
+		if toPair[j] >= 0 {
+			prevID = toPair[j]
+			continue
+		}
+
+		id := len(fromArr) + j
+		src := -1
+		if i := moveFrom[j]; i >= 0 {
+			id = i
+			src = indexOf(i)
+			cur = append(cur[:src], cur[src+1:]...)
+		}
+
+		dst := 0
+		if prevID >= 0 {
+			dst = indexOf(prevID) + 1
+		}
+		cur = append(cur, 0)
+		copy(cur[dst+1:], cur[dst:])
+		cur[dst] = id
+		prevID = id
+
+		if src < 0 {
+			g.addOperation("add", "", path+"/"+strconv.Itoa(dst), t)
+		} else if src != dst {
+			g.addOperation("move", path+"/"+strconv.Itoa(src), path+"/"+strconv.Itoa(dst), nil)
+		}
+	}
+
+	// Now the array is in the same layout with to.
+	for _, m := range modifications {
+		g.diff(path+"/"+strconv.Itoa(m.to), fromArr[m.from], toArr[m.to])
+	}
+}
+
+// arrayLCS returns pairs of elements in longest common subsequence of two arrays.
+// fromPair[i] is the index in to of from[i], or -1 if from[i] is not in the LCS,
+// and vice versa.
+func arrayLCS(from, to []*V) (fromPair, toPair []int) {
+	fromPair, toPair = make([]int, len(from)), make([]int, len(to))
+	for i := range fromPair {
+		fromPair[i] = -1
+	}
+	for j := range toPair {
+		toPair[j] = -1
+	}
+
+	// common prefix and suffix are trimmed to reduce calculation
+	head := 0
+	for head < len(from) && head < len(to) && from[head].Equal(to[head]) {
+		fromPair[head], toPair[head] = head, head
+		head++
+	}
+	tail := 0
+	for tail < len(from)-head && tail < len(to)-head && from[len(from)-1-tail].Equal(to[len(to)-1-tail]) {
+		i, j := len(from)-1-tail, len(to)-1-tail
+		fromPair[i], toPair[j] = j, i
+		tail++
+	}
+
+	f, t := from[head:len(from)-tail], to[head:len(to)-tail]
+	if len(f) == 0 || len(t) == 0 {
+		return
+	}
+
+	// lengths[i][j] is the LCS length of f[i:] and t[j:]
+	lengths := make([][]int, len(f)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(t)+1)
+	}
+	for i := len(f) - 1; i >= 0; i-- {
+		for j := len(t) - 1; j >= 0; j-- {
+			if f[i].Equal(t[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < len(f) && j < len(t); {
+		switch {
+		case f[i].Equal(t[j]):
+			fromPair[head+i], toPair[head+j] = head+j, head+i
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return
+}
+
+func arrayGaps(pair []int) []int {
+	gaps := make([]int, len(pair))
+	gap := 0
+	for i, p := range pair {
+		if p >= 0 {
+			gap++
+		}
+		gaps[i] = gap
+	}
+	return gaps
+}
@@ -0,0 +1,350 @@
+package jsonvalue
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func testPatch(t *testing.T) {
+	cv("RFC 6902 examples", func() { testPatchRFCExamples(t) })
+	cv("general operations", func() { testPatchOperations(t) })
+	cv("atomic rollback", func() { testPatchRollback(t) })
+	cv("invalid patch", func() { testPatchInvalid(t) })
+	cv("create patch", func() { testCreatePatch(t) })
+}
+
+func testPatchRFCExamples(t *testing.T) {
+	cases := []struct {
+		doc, patch, expected string
+	}{
+		// A.1 - A.7
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{
+			`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		// A.8
+		{
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		// A.10, A.16
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		// A.14
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		// A.11
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"baz":"qux","foo":"bar"}`},
+	}
+
+	for _, c := range cases {
+		v := MustUnmarshalString(c.doc)
+		err := v.ApplyPatch(MustUnmarshalString(c.patch))
+		so(err, isNil)
+		so(v.MustMarshalString(OptDefaultStringSequence(), OptEscapeSlash(false)), eq, c.expected)
+	}
+
+	// A.9, A.12, A.13, A.15
+	failures := []struct {
+		doc, patch string
+		err        error
+	}{
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ErrPatchTestFailed},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ErrNotFound},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":"10"}]`, ErrPatchTestFailed},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","op":"remove"}]`, ErrNotFound},
+	}
+	for _, c := range failures {
+		v := MustUnmarshalString(c.doc)
+		err := v.ApplyPatch(MustUnmarshalString(c.patch))
+		so(errors.Is(err, c.err), isTrue)
+		so(v.MustMarshalString(OptDefaultStringSequence(), OptEscapeSlash(false)), eq, c.doc)
+	}
+}
+
+func testPatchOperations(t *testing.T) {
+	cv("root operations", func() {
+		v := MustUnmarshalString(`{"a":{"b":[1,2]}}`)
+		child := v.MustGet("a")
+
+		err := v.ApplyPatch(MustUnmarshalString(`[
+			{"op":"move","from":"/a","path":""},
+			{"op":"test","path":"","value":{"b":[1,2]}},
+			{"op":"copy","from":"/b","path":"/c"},
+			{"op":"add","path":"/c/0","value":0},
+			{"op":"replace","path":"/b/-1","value":null}
+		]`))
+		so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+		err = v.ApplyPatch(MustUnmarshalString(`[
+			{"op":"move","from":"/a","path":""},
+			{"op":"test","path":"","value":{"b":[1,2]}},
+			{"op":"copy","from":"/b","path":"/c"},
+			{"op":"add","path":"/c/0","value":0},
+			{"op":"replace","path":"/b/1","value":null},
+			{"op":"move","from":"/c","path":"/c"}
+		]`))
+		so(err, isNil)
+		so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"b":[1,null],"c":[0,1,2]}`)
+		so(child.MustMarshalString(), eq, `{"b":[1,2]}`)
+
+		err = v.ApplyPatch(MustUnmarshalString(`[{"op":"replace","path":"","value":[true]}]`))
+		so(err, isNil)
+		so(v.MustMarshalString(), eq, `[true]`)
+
+		err = v.ApplyPatch(MustUnmarshalString(`[{"op":"add","path":"","value":"str"}]`))
+		so(err, isNil)
+		so(v.MustMarshalString(), eq, `"str"`)
+
+		err = v.ApplyPatch(MustUnmarshalString(`[{"op":"remove","path":""}]`))
+		so(errors.Is(err, ErrInvalidPatch), isTrue)
+	})
+
+	cv("patch values are not shared", func() {
+		v := MustUnmarshalString(`{}`)
+		patch := MustUnmarshalString(`[{"op":"add","path":"/a","value":{"b":1}}]`)
+		err := v.ApplyPatch(patch)
+		so(err, isNil)
+
+		v.MustSet(2).At("a", "b")
+		so(patch.MustMarshalString(OptDefaultStringSequence()), eq, `[{"op":"add","path":"\/a","value":{"b":1}}]`)
+	})
+
+	cv("copy does not share", func() {
+		v := MustUnmarshalString(`{"a":{"b":1}}`)
+		err := v.ApplyPatch(MustUnmarshalString(`[{"op":"copy","from":"/a","path":"/c"}]`))
+		so(err, isNil)
+		v.MustSet(2).At("c", "b")
+		so(v.MustGet("a", "b").Int(), eq, 1)
+	})
+
+	cv("test with number equality", func() {
+		v := MustUnmarshalString(`{"a":[1.0,{"x":null}]}`)
+		err := v.ApplyPatch(MustUnmarshalString(`[{"op":"test","path":"/a","value":[1,{"x":null}]}]`))
+		so(err, isNil)
+	})
+
+	cv("errors in operations", func() {
+		v := MustUnmarshalString(`{"arr":[1,2],"str":"s"}`)
+		errs := []struct {
+			patch string
+			err   error
+		}{
+			{`[{"op":"add","path":"/arr/3","value":1}]`, ErrOutOfRange},
+			{`[{"op":"add","path":"/str/a","value":1}]`, nil},
+			{`[{"op":"remove","path":"/arr/-"}]`, ErrOutOfRange},
+			{`[{"op":"remove","path":"/xxx"}]`, ErrNotFound},
+			{`[{"op":"replace","path":"/arr/2","value":1}]`, ErrOutOfRange},
+			{`[{"op":"replace","path":"/xxx","value":1}]`, ErrNotFound},
+			{`[{"op":"move","from":"/xxx","path":"/a"}]`, ErrNotFound},
+			{`[{"op":"move","from":"/xxx","path":"/xxx"}]`, ErrNotFound},
+			{`[{"op":"copy","from":"/xxx","path":"/a"}]`, ErrNotFound},
+			{`[{"op":"test","path":"/xxx","value":1}]`, ErrNotFound},
+		}
+		for _, e := range errs {
+			err := v.ApplyPatch(MustUnmarshalString(e.patch))
+			so(err, isErr)
+			pErr := &PatchError{}
+			so(errors.As(err, &pErr), isTrue)
+			so(pErr.Index, eq, 0)
+			if e.err != nil {
+				so(errors.Is(err, e.err), isTrue)
+			}
+		}
+		so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"arr":[1,2],"str":"s"}`)
+	})
+}
+
+func testPatchRollback(t *testing.T) {
+	v := MustUnmarshalString(`{"list":[1,2,3],"obj":{"a":1}}`)
+	list := v.MustGet("list")
+
+	err := v.ApplyPatch(MustUnmarshalString(`[
+		{"op":"add","path":"/list/-","value":4},
+		{"op":"remove","path":"/obj/a"},
+		{"op":"replace","path":"/list/0","value":"zero"},
+		{"op":"move","from":"/list","path":"/moved"},
+		{"op":"test","path":"/moved/3","value":5}
+	]`))
+	so(err, isErr)
+	so(errors.Is(err, ErrPatchTestFailed), isTrue)
+	so(err.Error(), hasSubStr, "patch operation 4 (test)")
+
+	pErr := &PatchError{}
+	so(errors.As(err, &pErr), isTrue)
+	so(pErr.Index, eq, 4)
+	so(pErr.Op, eq, "test")
+
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"list":[1,2,3],"obj":{"a":1}}`)
+	so(v.MustGet("list"), eq, list)
+	// on success, the patched copy takes the place of v
+	err = v.ApplyPatch(MustUnmarshalString(`[{"op":"add","path":"/list/-","value":4}]`))
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"list":[1,2,3,4],"obj":{"a":1}}`)
+	so(v.MustGet("list"), ne, list)
+	so(list.MustMarshalString(), eq, `[1,2,3]`)
+}
+
+func testPatchInvalid(t *testing.T) {
+	v := MustUnmarshalString(`{"a":1}`)
+
+	err := v.ApplyPatch(nil)
+	so(errors.Is(err, ErrNilParameter), isTrue)
+
+	err = (&V{}).ApplyPatch(NewArray())
+	so(errors.Is(err, ErrValueUninitialized), isTrue)
+
+	err = v.ApplyPatch(NewArray())
+	so(err, isNil)
+
+	patches := []string{
+		`{}`,
+		`[1]`,
+		`[{"path":"/a"}]`,
+		`[{"op":"add"}]`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"xxx","path":"/a","value":1}]`,
+		`[{"op":"move","path":"/a"}]`,
+		`[{"op":"copy","path":"/b","from":1}]`,
+		`[{"op":"move","from":"/a","path":"/a/b"}]`,
+	}
+	for _, p := range patches {
+		err := v.ApplyPatch(MustUnmarshalString(p))
+		so(errors.Is(err, ErrInvalidPatch), isTrue)
+	}
+
+	err = v.ApplyPatch(MustUnmarshalString(`[{"op":"test","path":"/a","value":1},{"op":"add","path":"a","value":1}]`))
+	so(errors.Is(err, ErrInvalidPointer), isTrue)
+	pErr := &PatchError{}
+	so(errors.As(err, &pErr), isTrue)
+	so(pErr.Index, eq, 1)
+	so(pErr.Op, eq, "add")
+
+	err = v.ApplyPatch(MustUnmarshalString(`[{"op":"copy","from":"~","path":"/b"}]`))
+	so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+	so(v.MustMarshalString(), eq, `{"a":1}`)
+}
+
+func testCreatePatch(t *testing.T) {
+	cv("specified operations", func() { testCreatePatchOperations(t) })
+	cv("round trip", func() { testCreatePatchRoundTrip(t) })
+	cv("errors", func() { testCreatePatchErrors(t) })
+}
+
+func testCreatePatchOperations(t *testing.T) {
+	check := func(from, to string, expected string) {
+		f, to2 := MustUnmarshalString(from), MustUnmarshalString(to)
+		patch, err := CreatePatch(f, to2)
+		so(err, isNil)
+		so(patch.MustMarshalString(OptKeySequence([]string{"op", "from", "path", "value"}), OptEscapeSlash(false)), eq, expected)
+
+		err = f.ApplyPatch(patch)
+		so(err, isNil)
+		so(f.Equal(to2), isTrue)
+	}
+
+	check(`{"a":1}`, `{"a":1}`, `[]`)
+	check(`1`, `"1"`, `[{"op":"replace","path":"","value":"1"}]`)
+	check(`{"a":1,"b":{"c":[1]},"d/e":true}`, `{"a":2,"b":{"c":[1],"x":null},"f~":0}`,
+		`[{"op":"replace","path":"/a","value":2},{"op":"add","path":"/b/x","value":null},`+
+			`{"op":"remove","path":"/d~1e"},{"op":"add","path":"/f~0","value":0}]`)
+
+	// array operations
+	check(`[1,2,3]`, `[1,2,3,4]`, `[{"op":"add","path":"/3","value":4}]`)
+	check(`[1,2,3]`, `[0,1,2,3]`, `[{"op":"add","path":"/0","value":0}]`)
+	check(`[1,2,3,4]`, `[1,4]`, `[{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`)
+	check(`["a","b","c","d"]`, `["b","c","d","a"]`, `[{"op":"move","from":"/0","path":"/3"}]`)
+	check(`["a","b","c","d"]`, `["d","a","b","c"]`, `[{"op":"move","from":"/3","path":"/0"}]`)
+	check(`["a","b","c","d","e"]`, `["a","d","c","b","e"]`,
+		`[{"op":"move","from":"/2","path":"/3"},{"op":"move","from":"/1","path":"/3"}]`)
+	check(`[1,{"id":1,"v":"a"},3]`, `[1,{"id":1,"v":"b"},3]`, `[{"op":"replace","path":"/1/v","value":"b"}]`)
+	check(`[1,2,3]`, `[1,5,3]`, `[{"op":"replace","path":"/1","value":5}]`)
+	check(`[{"id":1},{"id":2},{"id":3},{"id":4}]`, `[{"id":4},{"id":1},{"id":2,"x":1},{"id":3}]`,
+		`[{"op":"move","from":"/3","path":"/0"},{"op":"add","path":"/2/x","value":1}]`)
+	check(`[[1,2],[3]]`, `[[2,1],[3,4]]`,
+		`[{"op":"move","from":"/0/0","path":"/0/1"},{"op":"add","path":"/1/1","value":4}]`)
+	check(`[]`, `[1,2]`, `[{"op":"add","path":"/0","value":1},{"op":"add","path":"/1","value":2}]`)
+	check(`[1,2]`, `[]`, `[{"op":"remove","path":"/1"},{"op":"remove","path":"/0"}]`)
+
+	cv("values are copied", func() {
+		to := MustUnmarshalString(`{"a":{"b":1}}`)
+		patch, err := CreatePatch(NewObject(), to)
+		so(err, isNil)
+		to.MustSet(2).At("a", "b")
+		so(patch.MustGet(0, "value", "b").Int(), eq, 1)
+	})
+}
+
+func testCreatePatchRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(20240101))
+
+	var randValue func(depth int) *V
+	randValue = func(depth int) *V {
+		n := r.Intn(10)
+		switch {
+		case depth > 2 || n < 4:
+			return NewInt(r.Intn(5))
+		case n < 5:
+			return NewString(fmt.Sprint(r.Intn(3)))
+		case n < 7:
+			obj := NewObject()
+			for i := r.Intn(4); i > 0; i-- {
+				obj.MustSet(randValue(depth + 1)).At(fmt.Sprint(r.Intn(5)))
+			}
+			return obj
+		default:
+			arr := NewArray()
+			for i := r.Intn(8); i > 0; i-- {
+				arr.MustAppend(randValue(depth + 1)).InTheEnd()
+			}
+			return arr
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		from, to := randValue(0), randValue(0)
+		if i%2 == 0 {
+			from, to = NewArray(), NewArray()
+			for j := r.Intn(12); j > 0; j-- {
+				from.MustAppend(randValue(1)).InTheEnd()
+			}
+			for j := r.Intn(12); j > 0; j-- {
+				to.MustAppend(randValue(1)).InTheEnd()
+			}
+		}
+
+		patch, err := CreatePatch(from, to)
+		so(err, isNil)
+
+		res := from.deepCopy()
+		err = res.ApplyPatch(patch)
+		if err != nil || !res.Equal(to) {
+			t.Logf("from:  %s", from.MustMarshalString())
+			t.Logf("to:    %s", to.MustMarshalString())
+			t.Logf("patch: %s", patch.MustMarshalString())
+		}
+		so(err, isNil)
+		so(res.Equal(to), isTrue)
+	}
+}
+
+func testCreatePatchErrors(t *testing.T) {
+	_, err := CreatePatch(nil, NewObject())
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = CreatePatch(NewObject(), nil)
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = CreatePatch(&V{}, NewObject())
+	so(errors.Is(err, ErrValueUninitialized), isTrue)
+	_, err = CreatePatch(NewObject(), &V{})
+	so(errors.Is(err, ErrValueUninitialized), isTrue)
+}
@@ -0,0 +1,242 @@
+package jsonvalue
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ================ JSON POINTER ================
+
+// Reference: [RFC 6901 - JavaScript Object Notation (JSON) Pointer](https://www.rfc-editor.org/rfc/rfc6901)
+
+var pointerTokenEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// GetByPointer returns JSON value located by given JSON Pointer (RFC 6901),
+// such as "/spec/containers/0/image". Empty string refers to the value itself.
+//
+// GetByPointer 返回 JSON Pointer (RFC 6901) 所指定的 JSON 值, 如 "/spec/containers/0/image"。
+// 空字符串表示当前值本身。
+func (v *V) GetByPointer(ptr string) (*V, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return &V{}, err
+	}
+	return getByPointerTokens(v, tokens)
+}
+
+// MustGetByPointer is same as GetByPointer(), but does not return error. If
+// error occurs, a JSON value with NotExist type will be returned.
+//
+// MustGetByPointer 与 GetByPointer() 函数相同, 不过不返回错误。如果发生错误了, 那么会返回一个
+// ValueType() 返回值为 NotExist 的 JSON 值对象。
+func (v *V) MustGetByPointer(ptr string) *V {
+	res, _ := v.GetByPointer(ptr)
+	return res
+}
+
+// DeleteByPointer deletes the JSON value located by given JSON Pointer. The "-"
+// token is not allowed as it refers to a nonexistent element.
+//
+// DeleteByPointer 删除 JSON Pointer 所指定的 JSON 值。由于 "-" 指向一个不存在的数组成员, 因此
+// 不能使用。
+func (v *V) DeleteByPointer(ptr string) error {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("%w, cannot delete the root value", ErrParameterError)
+	}
+
+	parent, err := getByPointerTokens(v, tokens[:len(tokens)-1])
+	if err != nil {
+		return err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch parent.valueType {
+	case Object:
+		return deleteInCurrentObject(parent, false, last)
+	case Array:
+		pos, err := pointerTokenToIndex(parent, last, false)
+		if err != nil {
+			return err
+		}
+		deleteInArr(parent, pos)
+		return nil
+	default:
+		return fmt.Errorf("%v type does not supports Delete()", parent.valueType)
+	}
+}
+
+// AtPointer sets the value of Set() at the position located by given JSON
+// Pointer, and returns the value set. It implements Setter.
+//
+// AtPointer 将 Set() 的值设置到给定 JSON Pointer 所指定的位置上, 并返回被设置的值。它实现了 Setter 接口。
+func (s *setter) AtPointer(ptr string) (*V, error) {
+	if s.err != nil {
+		return &V{}, s.err
+	}
+	if s.v == nil || s.v.valueType == NotExist {
+		return &V{}, ErrValueUninitialized
+	}
+	if s.c == nil || s.c.valueType == NotExist {
+		return &V{}, ErrValueUninitialized
+	}
+
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return &V{}, err
+	}
+	if len(tokens) == 0 {
+		return &V{}, fmt.Errorf("%w, cannot set the root value", ErrParameterError)
+	}
+
+	parent, err := getByPointerTokens(s.v, tokens[:len(tokens)-1])
+	if err != nil {
+		return &V{}, err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch parent.valueType {
+	case Object:
+		setToObjectChildren(parent, last, s.c)
+		return s.c, nil
+	case Array:
+		pos, err := pointerTokenToIndex(parent, last, true)
+		if err != nil {
+			return &V{}, err
+		}
+		if err := setAtIndex(parent, s.c, pos); err != nil {
+			return &V{}, err
+		}
+		return s.c, nil
+	default:
+		return &V{}, fmt.Errorf("%v type does not supports Set()", parent.valueType)
+	}
+}
+
+// AtPointer is the same as setter.AtPointer, but ignores the returned value and
+// error. It implements MustSetter.
+//
+// AtPointer 与 setter.AtPointer 相同, 但是忽略返回值和错误。它实现了 MustSetter 接口。
+func (s mSetter) AtPointer(ptr string) {
+	_, _ = s.setter.AtPointer(ptr)
+}
+
+// Pointer returns the JSON Pointer (RFC 6901) representation of the path.
+//
+// Pointer 返回当前路径的 JSON Pointer (RFC 6901) 表示。
+func (p Path) Pointer() string {
+	buf := bytes.Buffer{}
+	for _, item := range p {
+		buf.WriteByte('/')
+		if item.Idx >= 0 {
+			buf.WriteString(strconv.Itoa(item.Idx))
+		} else {
+			buf.WriteString(pointerTokenEscaper.Replace(item.Key))
+		}
+	}
+	return buf.String()
+}
+
+// Pointer returns the JSON Pointer (RFC 6901) representation of the key path.
+//
+// Pointer 返回当前键路径的 JSON Pointer (RFC 6901) 表示。
+func (p KeyPath) Pointer() string {
+	buf := bytes.Buffer{}
+	for _, k := range p {
+		buf.WriteByte('/')
+		if k.IsInt() {
+			buf.WriteString(strconv.Itoa(k.Int()))
+		} else {
+			buf.WriteString(pointerTokenEscaper.Replace(k.String()))
+		}
+	}
+	return buf.String()
+}
+
+// parsePointer splits a JSON pointer into unescaped reference tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("%w, '%s' does not start with '/'", ErrInvalidPointer, ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		if !strings.Contains(t, "~") {
+			continue
+		}
+		// check escaping sequences, '~' must be followed by '0' or '1'
+		for j := 0; j < len(t); j++ {
+			if t[j] != '~' {
+				continue
+			}
+			if j == len(t)-1 || (t[j+1] != '0' && t[j+1] != '1') {
+				return nil, fmt.Errorf("%w, illegal escaping in token '%s'", ErrInvalidPointer, t)
+			}
+		}
+		// '~1' should be replaced firstly, so that "~01" could be "~1" instead of "/"
+		t = strings.Replace(t, "~1", "/", -1)
+		tokens[i] = strings.Replace(t, "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+func getByPointerTokens(v *V, tokens []string) (*V, error) {
+	for _, t := range tokens {
+		switch v.valueType {
+		case Object:
+			child, exist := getFromObjectChildren(v, false, t)
+			if !exist {
+				return &V{}, ErrNotFound
+			}
+			v = child
+		case Array:
+			pos, err := pointerTokenToIndex(v, t, false)
+			if err != nil {
+				return &V{}, err
+			}
+			v = v.loadedChildren().arr[pos]
+		default:
+			return &V{}, fmt.Errorf("%v type does not supports Get()", v.valueType)
+		}
+	}
+	return v, nil
+}
+
+// pointerTokenToIndex converts a reference token to array index. If
+// allowAppend is true, "-" or length of the array are also allowed, which
+// stands for the position after the last element.
+func pointerTokenToIndex(arr *V, t string, allowAppend bool) (int, error) {
+	le := len(arr.loadedChildren().arr)
+	if t == "-" {
+		if allowAppend {
+			return le, nil
+		}
+		return -1, fmt.Errorf("%w, '-' refers to a nonexistent element", ErrOutOfRange)
+	}
+
+	if t == "" || (len(t) > 1 && t[0] == '0') {
+		return -1, fmt.Errorf("%w, illegal array index '%s'", ErrInvalidPointer, t)
+	}
+	for _, c := range []byte(t) {
+		if c < '0' || c > '9' {
+			return -1, fmt.Errorf("%w, illegal array index '%s'", ErrInvalidPointer, t)
+		}
+	}
+
+	pos, err := strconv.Atoi(t)
+	if err != nil {
+		return -1, fmt.Errorf("%w, array index '%s' is too large", ErrOutOfRange, t)
+	}
+	if pos < le || (allowAppend && pos == le) {
+		return pos, nil
+	}
+	return -1, ErrOutOfRange
+}
@@ -0,0 +1,189 @@
+package jsonvalue
+
+import (
+	"errors"
+	"testing"
+)
+
+func testPointer(t *testing.T) {
+	cv("get by pointer", func() { testGetByPointer(t) })
+	cv("set by pointer", func() { testSetByPointer(t) })
+	cv("delete by pointer", func() { testDeleteByPointer(t) })
+	cv("path to pointer", func() { testPathToPointer(t) })
+}
+
+func testGetByPointer(t *testing.T) {
+	raw := `{"spec":{"containers":[{"image":"nginx"},{"image":"redis"}]},` +
+		`"a/b":1,"m~n":2,"":3,"~01":4}`
+	v := MustUnmarshalString(raw)
+
+	cv("normal", func() {
+		c, err := v.GetByPointer("/spec/containers/1/image")
+		so(err, isNil)
+		so(c.String(), eq, "redis")
+
+		c, err = v.GetByPointer("")
+		so(err, isNil)
+		so(c, eq, v)
+
+		so(v.MustGetByPointer("/a~1b").Int(), eq, 1)
+		so(v.MustGetByPointer("/m~0n").Int(), eq, 2)
+		so(v.MustGetByPointer("/").Int(), eq, 3)
+		so(v.MustGetByPointer("/~001").Int(), eq, 4)
+	})
+
+	cv("errors", func() {
+		_, err := v.GetByPointer("spec")
+		so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+		_, err = v.GetByPointer("/m~2n")
+		so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+		_, err = v.GetByPointer("/m~")
+		so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+		_, err = v.GetByPointer("/spec/containers/01")
+		so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+		_, err = v.GetByPointer("/spec/containers/-1")
+		so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+		_, err = v.GetByPointer("/spec/containers/-")
+		so(errors.Is(err, ErrOutOfRange), isTrue)
+
+		_, err = v.GetByPointer("/spec/containers/2")
+		so(errors.Is(err, ErrOutOfRange), isTrue)
+
+		_, err = v.GetByPointer("/spec/volumes")
+		so(errors.Is(err, ErrNotFound), isTrue)
+
+		c, err := v.GetByPointer("/spec/containers/0/image/name")
+		so(err, isErr)
+		so(c.ValueType(), eq, NotExist)
+
+		so(v.MustGetByPointer("/xxx").ValueType(), eq, NotExist)
+	})
+}
+
+func testSetByPointer(t *testing.T) {
+	cv("normal", func() {
+		v := MustUnmarshalString(`{"arr":[1,2],"obj":{}}`)
+
+		c, err := v.SetString("hello").AtPointer("/obj/a~1b")
+		so(err, isNil)
+		so(c.String(), eq, "hello")
+
+		_, err = v.SetInt(3).AtPointer("/arr/-")
+		so(err, isNil)
+		_, err = v.SetInt(4).AtPointer("/arr/3")
+		so(err, isNil)
+		_, err = v.SetInt(10).AtPointer("/arr/0")
+		so(err, isNil)
+
+		v.MustSetBool(true).AtPointer("/obj/m~0n")
+
+		so(v.MustMarshalString(OptDefaultStringSequence()), eq,
+			`{"arr":[10,2,3,4],"obj":{"a\/b":"hello","m~n":true}}`)
+	})
+
+	cv("errors", func() {
+		v := MustUnmarshalString(`{"arr":[1,2],"str":"s"}`)
+
+		_, err := v.SetInt(1).AtPointer("")
+		so(errors.Is(err, ErrParameterError), isTrue)
+
+		_, err = v.SetInt(1).AtPointer("arr")
+		so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+		_, err = v.SetInt(1).AtPointer("/arr/3")
+		so(errors.Is(err, ErrOutOfRange), isTrue)
+
+		_, err = v.SetInt(1).AtPointer("/obj/a")
+		so(errors.Is(err, ErrNotFound), isTrue)
+
+		_, err = v.SetInt(1).AtPointer("/str/a")
+		so(err, isErr)
+
+		_, err = (&V{}).SetInt(1).AtPointer("/a")
+		so(errors.Is(err, ErrValueUninitialized), isTrue)
+
+		_, err = v.Set(&V{}).AtPointer("/a")
+		so(errors.Is(err, ErrValueUninitialized), isTrue)
+
+		_, err = v.Set(make(chan int)).AtPointer("/a")
+		so(err, isErr)
+
+		so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"arr":[1,2],"str":"s"}`)
+	})
+}
+
+func testDeleteByPointer(t *testing.T) {
+	v := MustUnmarshalString(`{"arr":[1,2,3],"obj":{"a/b":1,"c":2},"str":"s"}`)
+
+	err := v.DeleteByPointer("/arr/1")
+	so(err, isNil)
+	err = v.DeleteByPointer("/obj/a~1b")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"arr":[1,3],"obj":{"c":2},"str":"s"}`)
+
+	err = v.DeleteByPointer("")
+	so(errors.Is(err, ErrParameterError), isTrue)
+
+	err = v.DeleteByPointer("/arr/-")
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+
+	err = v.DeleteByPointer("/arr/2")
+	so(errors.Is(err, ErrOutOfRange), isTrue)
+
+	err = v.DeleteByPointer("/obj/a~1b")
+	so(errors.Is(err, ErrNotFound), isTrue)
+
+	err = v.DeleteByPointer("/xxx/a")
+	so(errors.Is(err, ErrNotFound), isTrue)
+
+	err = v.DeleteByPointer("/str/a")
+	so(err, isErr)
+
+	err = v.DeleteByPointer("~")
+	so(errors.Is(err, ErrInvalidPointer), isTrue)
+}
+
+func testPathToPointer(t *testing.T) {
+	cv("Path", func() {
+		so(Path{}.Pointer(), eq, "")
+
+		p := Path{
+			{Idx: -1, Key: "spec"},
+			{Idx: -1, Key: "a/b~c"},
+			{Idx: 2, Key: ""},
+		}
+		so(p.Pointer(), eq, "/spec/a~1b~0c/2")
+
+		v := MustUnmarshalString(`{"a/b":[{"m~n":true}]}`)
+		pointers := []string{}
+		v.Walk(func(path Path, c *V) bool {
+			ptr := path.Pointer()
+			pointers = append(pointers, ptr)
+			so(v.MustGetByPointer(ptr), eq, c)
+			return true
+		})
+		so(len(pointers), ne, 0)
+		so(pointers[len(pointers)-1], eq, "/a~1b/0/m~0n")
+	})
+
+	cv("KeyPath", func() {
+		so(KeyPath{}.Pointer(), eq, "")
+
+		k1, k2, k3 := stringKey("spec"), stringKey("a/b~c"), intKey(2)
+		p := KeyPath{&k1, &k2, &k3}
+		so(p.Pointer(), eq, "/spec/a~1b~0c/2")
+
+		v := MustUnmarshalString(`{"a/b":[{"m~n":true,"x":1}]}`)
+		pointers := map[string]bool{}
+		_ = v.MustMarshal(OptKeySequenceWithLessFunc(func(parent *ParentInfo, key1, key2 string, _, _ *V) bool {
+			pointers[parent.KeyPath.Pointer()] = true
+			return key1 < key2
+		}))
+		so(pointers["/a~1b/0"], isTrue)
+	})
+}
@@ -0,0 +1,188 @@
+package jsonvalue
+
+import (
+	"bytes"
+	"sort"
+	"unicode/utf8"
+)
+
+// ================ SOURCE POSITION ================
+
+// Position describes where a value is in the source text.
+//
+// Position 描述了一个值在源文本中的位置。
+type Position struct {
+	// Start is the byte offset of the first byte of the value, starting from 0.
+	//
+	// Start 表示值的第一个字节的偏移量, 从 0 开始。
+	Start int
+	// End is the byte offset after the last byte of the value.
+	//
+	// End 表示值的最后一个字节之后的偏移量。
+	End int
+	// Line is the line number of Start, starting from 1.
+	//
+	// Line 表示 Start 所在的行号, 从 1 开始。
+	Line int
+	// Column is the column number of Start, counted in characters and starting
+	// from 1.
+	//
+	// Column 表示 Start 所在的列号, 以字符为单位, 从 1 开始。
+	Column int
+	// EndLine is the line number of End, starting from 1.
+	//
+	// EndLine 表示 End 所在的行号, 从 1 开始。
+	EndLine int
+	// EndColumn is the column number of End, counted in characters and starting
+	// from 1.
+	//
+	// EndColumn 表示 End 所在的列号, 以字符为单位, 从 1 开始。
+	EndColumn int
+}
+
+// Positions is a lookup table of source positions of parsed values. It is
+// filled by parsing with UnmarshalOptKeepPositions, or held by a Document.
+//
+// Positions 是已解析的值在源文本中位置的查询表。可以通过 UnmarshalOptKeepPositions 选项在解析时填充,
+// Document 也持有一个这样的表。
+type Positions struct {
+	src   []byte
+	spans map[*V]nodeSpan
+	lines *lineIndex // built on demand
+}
+
+// UnmarshalOptKeepPositions records source positions of all parsed values into
+// given table, which is cleared at the beginning of each parsing and left empty
+// if parsing fails. The table refers to the raw text, so the text should not be
+// modified afterwards.
+//
+// UnmarshalOptKeepPositions 表示将所有已解析的值在源文本中的位置记录到给定的表中。每次解析开始时会清空该表,
+// 解析失败时该表为空。该表会引用原始文本, 因此之后不应修改原始文本。
+func UnmarshalOptKeepPositions(p *Positions) UnmarshalOption {
+	return unmarshalOptKeepPositions{p}
+}
+
+type unmarshalOptKeepPositions struct {
+	p *Positions
+}
+
+func (o unmarshalOptKeepPositions) mergeToUnmarshalOpt(opt *unmarshalOptions) {
+	opt.positions = o.p
+}
+
+// Position returns source position of v, which should be got from the parsed
+// value, either directly or by Get, Walk and other accessors. The second return
+// value is false otherwise. Values set after parsing have no position.
+//
+// Position 返回 v 在源文本中的位置, v 应当是解析得到的值本身, 或是通过 Get, Walk 以及其他访问方法从中
+// 得到的值, 否则第二个返回值为 false。解析之后设置的值没有位置。
+func (p *Positions) Position(v *V) (Position, bool) {
+	if p == nil || v == nil {
+		return Position{}, false
+	}
+	span, exist := p.spans[v]
+	if !exist {
+		return Position{}, false
+	}
+	if p.lines == nil {
+		p.lines = newLineIndex(p.src)
+	}
+	pos := Position{Start: span.start, End: span.end}
+	pos.Line, pos.Column = p.lines.locate(span.start)
+	pos.EndLine, pos.EndColumn = p.lines.locate(span.end)
+	return pos, true
+}
+
+func (p *Positions) reset(src []byte) {
+	p.src, p.lines = src, nil
+	if src == nil {
+		p.spans = nil
+	} else {
+		p.spans = map[*V]nodeSpan{}
+	}
+}
+
+// Position returns source position of v, which should be got from Value of the
+// Document, either directly or by Get, Walk and other accessors. The second
+// return value is false otherwise. Positions describe current text only, values
+// set by modifying Value have no position.
+//
+// Position 返回 v 在源文本中的位置, v 应当是 Document 的 Value 本身, 或是通过 Get, Walk 以及其他访问
+// 方法从中得到的值, 否则第二个返回值为 false。位置仅描述当前文本, 通过修改 Value 设置的值没有位置。
+func (d *Document) Position(v *V) (Position, bool) {
+	if d.Value() == nil {
+		return Position{}, false
+	}
+	return d.valuePositions.Position(v)
+}
+
+// lineIndexCheckpoint is the interval of rune count checkpoints.
+const lineIndexCheckpoint = 64
+
+// lineIndex converts byte offsets to lines and columns. It should be built with
+// the original text, because parsing unescapes strings in place.
+type lineIndex struct {
+	src        []byte
+	lineStarts []int
+	ascii      bool
+	runes      []int // runes[i] is count of runes in src[:i*lineIndexCheckpoint]
+}
+
+func newLineIndex(src []byte) *lineIndex {
+	idx := &lineIndex{
+		src:        src,
+		lineStarts: []int{0},
+		ascii:      true,
+	}
+	for i, c := range src {
+		if c == '\n' {
+			idx.lineStarts = append(idx.lineStarts, i+1)
+		} else if c >= utf8.RuneSelf {
+			idx.ascii = false
+		}
+	}
+	if !idx.ascii {
+		idx.runes = make([]int, 0, len(src)/lineIndexCheckpoint+1)
+		cnt := 0
+		for i := 0; i < len(src); i += lineIndexCheckpoint {
+			idx.runes = append(idx.runes, cnt)
+			end := i + lineIndexCheckpoint
+			if end > len(src) {
+				end = len(src)
+			}
+			cnt += countRuneStarts(src[i:end])
+		}
+	}
+	return idx
+}
+
+// locate returns line and column of offset, both starting from 1.
+func (idx *lineIndex) locate(offset int) (line, column int) {
+	line = sort.SearchInts(idx.lineStarts, offset+1)
+	lineStart := idx.lineStarts[line-1]
+	if idx.ascii {
+		return line, offset - lineStart + 1
+	}
+	return line, idx.runesBefore(offset) - idx.runesBefore(lineStart) + 1
+}
+
+func (idx *lineIndex) runesBefore(offset int) int {
+	i := offset / lineIndexCheckpoint
+	if i >= len(idx.runes) {
+		i = len(idx.runes) - 1
+	}
+	return idx.runes[i] + countRuneStarts(idx.src[i*lineIndexCheckpoint:offset])
+}
+
+func countRuneStarts(b []byte) int {
+	if bytes.IndexFunc(b, func(r rune) bool { return r >= utf8.RuneSelf }) < 0 {
+		return len(b)
+	}
+	cnt := 0
+	for _, c := range b {
+		if utf8.RuneStart(c) {
+			cnt++
+		}
+	}
+	return cnt
+}
@@ -0,0 +1,226 @@
+package jsonvalue
+
+import (
+	"testing"
+)
+
+func testPosition(t *testing.T) {
+	cv("only parsed values", func() { testPositionOnlyParsedValues(t) })
+	cv("single line", func() { testPositionSingleLine(t) })
+	cv("multiple lines", func() { testPositionMultipleLines(t) })
+	cv("multi-byte characters", func() { testPositionMultiByte(t) })
+	cv("walk", func() { testPositionWalk(t) })
+	cv("with other options", func() { testPositionWithOtherOptions(t) })
+	cv("UnmarshalOptKeepPositions", func() { testPositionKeepPositionsOption(t) })
+}
+
+func testPositionOnlyParsedValues(t *testing.T) {
+	doc, err := UnmarshalDocumentString(`{"a":1}`)
+	so(err, isNil)
+	_, ok := doc.Position(doc.Value())
+	so(ok, isTrue)
+
+	_, ok = doc.Position(MustUnmarshalString(`{"a":1}`))
+	so(ok, isFalse)
+	_, ok = doc.Position(NewInt(1))
+	so(ok, isFalse)
+	_, ok = doc.Position(nil)
+	so(ok, isFalse)
+}
+
+// valueWithPositions parses raw as a Document and returns its Value.
+func valueWithPositions(raw string, opts ...UnmarshalOption) (*Document, *V) {
+	doc, err := UnmarshalDocumentString(raw, opts...)
+	so(err, isNil)
+	return doc, doc.Value()
+}
+
+func testPositionSingleLine(t *testing.T) {
+	raw := ` {"a": [1, true, null], "b": "str\n"} `
+	doc, v := valueWithPositions(raw)
+
+	check := func(v *V, text string, start int) {
+		pos, ok := doc.Position(v)
+		so(ok, isTrue)
+		so(pos.Start, eq, start)
+		so(pos.End, eq, start+len(text))
+		so(doc.String()[pos.Start:pos.End], eq, text)
+		so(pos.Line, eq, 1)
+		so(pos.Column, eq, start+1)
+		so(pos.EndLine, eq, 1)
+		so(pos.EndColumn, eq, start+len(text)+1)
+	}
+	check(v, `{"a": [1, true, null], "b": "str\n"}`, 1)
+	check(v.MustGet("a"), `[1, true, null]`, 7)
+	check(v.MustGet("a", 0), `1`, 8)
+	check(v.MustGet("a", 1), `true`, 11)
+	check(v.MustGet("a", 2), `null`, 17)
+	check(v.MustGet("b"), `"str\n"`, 29)
+
+	// modified values have no position
+	v.MustSet(2).At("a", 0)
+	_, ok := doc.Position(v.MustGet("a", 0))
+	so(ok, isFalse)
+	pos, _ := doc.Position(v.MustGet("b"))
+	so(pos.Start, eq, 29)
+
+	// positions follow the text after editing the document
+	so(doc.Set(12345, "a", 0), isNil)
+	_, ok = doc.Position(v.MustGet("b"))
+	so(ok, isFalse)
+	check(doc.Value().MustGet("b"), `"str\n"`, 33)
+}
+
+func testPositionMultipleLines(t *testing.T) {
+	raw := "{\n  \"a\": 1,\n  \"b\": {\n    \"c\": [\n      2\n    ]\n  }\n}\n"
+	doc, v := valueWithPositions(raw)
+
+	pos, ok := doc.Position(v)
+	so(ok, isTrue)
+	so(pos, resemble, Position{Start: 0, End: len(raw) - 1, Line: 1, Column: 1, EndLine: 8, EndColumn: 2})
+
+	pos, _ = doc.Position(v.MustGet("a"))
+	so(pos, resemble, Position{Start: 9, End: 10, Line: 2, Column: 8, EndLine: 2, EndColumn: 9})
+
+	pos, _ = doc.Position(v.MustGet("b", "c"))
+	so(pos.Line, eq, 4)
+	so(pos.Column, eq, 10)
+	so(pos.EndLine, eq, 6)
+	so(pos.EndColumn, eq, 6)
+
+	pos, _ = doc.Position(v.MustGet("b", "c", 0))
+	so(pos.Line, eq, 5)
+	so(pos.Column, eq, 7)
+	so(raw[pos.Start:pos.End], eq, "2")
+}
+
+func testPositionMultiByte(t *testing.T) {
+	raw := "{\"中文\": \"值\", \"k\": 1}\n[\"éé\", 2]"
+	doc, v := valueWithPositions(raw[:len(raw)-len("\n[\"éé\", 2]")])
+	pos, _ := doc.Position(v.MustGet("中文"))
+	so(pos.Column, eq, 8)
+	so(pos.EndColumn, eq, 11)
+	pos, _ = doc.Position(v.MustGet("k"))
+	so(pos.Column, eq, 18)
+
+	doc, v = valueWithPositions("\n[\"éé\", 2]")
+	pos, _ = doc.Position(v.MustGet(1))
+	so(pos.Line, eq, 2)
+	so(pos.Column, eq, 8)
+
+	// long line across several rune count checkpoints
+	s := "["
+	for i := 0; i < 100; i++ {
+		s += "\"中\","
+	}
+	s += "1]"
+	doc, v = valueWithPositions(s)
+	pos, _ = doc.Position(v.MustGet(100))
+	so(pos.Start, eq, len(s)-2)
+	so(pos.Column, eq, 1+100*4+1)
+	so(pos.EndColumn, eq, 1+100*4+2)
+	pos, _ = doc.Position(v.MustGet(50))
+	so(pos.Column, eq, 1+50*4+1)
+}
+
+func testPositionWalk(t *testing.T) {
+	raw := "{\n\"a\": [1, {\"b\": \"c\"}],\n\"d\": false\n}"
+	doc, v := valueWithPositions(raw)
+
+	cnt := 0
+	v.Walk(func(path Path, v *V) bool {
+		pos, ok := doc.Position(v)
+		so(ok, isTrue)
+		so(MustUnmarshalString(raw[pos.Start:pos.End]).MustMarshalString(), eq, v.MustMarshalString())
+		cnt++
+		return true
+	})
+	so(cnt, eq, 3)
+
+	v.RangeObjects(func(k string, v *V) bool {
+		_, ok := doc.Position(v)
+		so(ok, isTrue)
+		return true
+	})
+}
+
+func testPositionWithOtherOptions(t *testing.T) {
+	raw := "// comment\n{\n  a: 'x', // c\n  b: 0x10,\n}"
+	doc, v := valueWithPositions(raw, UnmarshalOptJSON5())
+	pos, _ := doc.Position(v.MustGet("a"))
+	so(pos, resemble, Position{Start: 18, End: 21, Line: 3, Column: 6, EndLine: 3, EndColumn: 9})
+	pos, _ = doc.Position(v.MustGet("b"))
+	so(raw[pos.Start:pos.End], eq, "0x10")
+	so(pos.Line, eq, 4)
+
+	so(doc.Set(1, "a"), isNil)
+	so(doc.Value().MustGet("a").Int(), eq, 1)
+	pos, ok := doc.Position(doc.Value().MustGet("a"))
+	so(ok, isTrue)
+	so(raw[:pos.Start], eq, "// comment\n{\n  a: ")
+	so(pos.End, eq, pos.Start+1)
+}
+
+func testPositionKeepPositionsOption(t *testing.T) {
+	cv("general", func() {
+		raw := "{\n  \"a\": [1, \"中\"],\n  \"b\": null\n}"
+		pos := &Positions{}
+		v, err := UnmarshalStringWithOptions(raw, UnmarshalOptKeepPositions(pos))
+		so(err, isNil)
+
+		p, ok := pos.Position(v.MustGet("a", 1))
+		so(ok, isTrue)
+		so(p, resemble, Position{Start: 13, End: 18, Line: 2, Column: 12, EndLine: 2, EndColumn: 15})
+		p, ok = pos.Position(v.MustGet("b"))
+		so(ok, isTrue)
+		so(raw[p.Start:p.End], eq, "null")
+		so(p.Line, eq, 3)
+
+		cnt := 0
+		v.Walk(func(path Path, v *V) bool {
+			p, ok := pos.Position(v)
+			so(ok, isTrue)
+			so(MustUnmarshalString(raw[p.Start:p.End]).Equal(v), isTrue)
+			cnt++
+			return true
+		})
+		so(cnt, eq, 3)
+
+		_, ok = pos.Position(MustUnmarshalString(raw))
+		so(ok, isFalse)
+		_, ok = pos.Position(nil)
+		so(ok, isFalse)
+		_, ok = (*Positions)(nil).Position(v)
+		so(ok, isFalse)
+	})
+
+	cv("with other options", func() {
+		raw := []byte("[1, 'x', /* c */ 0x10,]")
+		pos := &Positions{}
+		v, err := UnmarshalWithOptions(raw, UnmarshalOptJSON5(), UnmarshalOptKeepPositions(pos))
+		so(err, isNil)
+		p, _ := pos.Position(v.MustGet(1))
+		so(string(raw[p.Start:p.End]), eq, "'x'")
+		p, _ = pos.Position(v.MustGet(2))
+		so(string(raw[p.Start:p.End]), eq, "0x10")
+	})
+
+	cv("cleared on each parsing", func() {
+		pos := &Positions{}
+		v1, err := UnmarshalStringWithOptions(`[1]`, UnmarshalOptKeepPositions(pos))
+		so(err, isNil)
+		v2, err := UnmarshalStringWithOptions(` [2]`, UnmarshalOptKeepPositions(pos))
+		so(err, isNil)
+
+		_, ok := pos.Position(v1)
+		so(ok, isFalse)
+		p, ok := pos.Position(v2.MustGet(0))
+		so(ok, isTrue)
+		so(p.Column, eq, 3)
+
+		_, err = UnmarshalStringWithOptions(`[3,`, UnmarshalOptKeepPositions(pos))
+		so(err, isErr)
+		_, ok = pos.Position(v2)
+		so(ok, isFalse)
+	})
+}
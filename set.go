@@ -3,6 +3,8 @@ package jsonvalue
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/shopspring/decimal"
 )
 
 // Setter type is for At() only.
@@ -23,6 +25,16 @@ type Setter interface {
 	// JSON 值的子成员，并且设置到指定的位置上。设置的逻辑说明起来比较抽象，请打开以下的例子以了解,
 	// 这非常重要。
 	At(firstParam any, otherParams ...any) (*V, error)
+
+	// AtPointer completes the following operation of Set() by a JSON Pointer
+	// (RFC 6901), such as "/spec/containers/0/image". All intermediate values
+	// should already exist. For an array, "-" or the length of array appends
+	// the value to the end, while an existing index replaces the element.
+	//
+	// AtPointer 使用 JSON Pointer (RFC 6901) 完成 Set() 函数的后续操作, 如
+	// "/spec/containers/0/image"。所有的中间值都必须已经存在。对于数组, "-" 或者是数组长度
+	// 表示添加到末尾, 而已存在的下标则表示替换该成员。
+	AtPointer(ptr string) (*V, error)
 }
 
 type setter struct {
@@ -143,6 +155,13 @@ func (v *V) SetFloat32(f float32) Setter {
 	return v.Set(NewFloat32(f))
 }
 
+// SetDecimal is equivalent to Set(jsonvalue.NewDecimal(d))
+//
+// SetDecimal 等效于 Set(jsonvalue.NewDecimal(d))
+func (v *V) SetDecimal(d decimal.Decimal) Setter {
+	return v.Set(NewDecimal(d))
+}
+
 // SetNull is equivalent to Set(jsonvalue.NewNull())
 //
 // SetNull 等效于 Set(jsonvalue.NewNull())
@@ -165,9 +184,10 @@ func (v *V) SetArray() Setter {
 }
 
 func setToObjectChildren(v *V, key string, child *V) {
-	v.children.incrID++
-	v.children.object[key] = childWithProperty{
-		id: v.children.incrID,
+	c := v.loadedChildren()
+	c.incrID++
+	c.object[key] = childWithProperty{
+		id: c.incrID,
 		v:  child,
 	}
 	addCaselessKey(v, key)
@@ -312,7 +332,7 @@ func (s *setter) atArray(firstParam any, otherParams []any) (*V, error) {
 }
 
 func posAtIndexForSet(v *V, pos int) (newPos int, appendToEnd bool) {
-	if pos == len(v.children.arr) {
+	if pos == len(v.loadedChildren().arr) {
 		return pos, true
 	}
 	pos = posAtIndexForRead(v, pos)
@@ -320,7 +340,7 @@ func posAtIndexForSet(v *V, pos int) (newPos int, appendToEnd bool) {
 }
 
 func posAtIndexForInsertBefore(v *V, pos int) (newPos int) {
-	le := len(v.children.arr)
+	le := len(v.loadedChildren().arr)
 	if le == 0 {
 		return -1
 	}
@@ -345,7 +365,7 @@ func posAtIndexForInsertBefore(v *V, pos int) (newPos int) {
 }
 
 func posAtIndexForInsertAfter(v *V, pos int) (newPos int, appendToEnd bool) {
-	le := len(v.children.arr)
+	le := len(v.loadedChildren().arr)
 	if le == 0 {
 		return -1, false
 	}
@@ -370,7 +390,7 @@ func posAtIndexForInsertAfter(v *V, pos int) (newPos int, appendToEnd bool) {
 }
 
 func posAtIndexForRead(v *V, pos int) int {
-	le := len(v.children.arr)
+	le := len(v.loadedChildren().arr)
 	if le == 0 {
 		return -1
 	}
@@ -395,7 +415,7 @@ func childAtIndex(v *V, pos int) (*V, bool) { // if nil returned, means that jus
 	if pos < 0 {
 		return &V{}, false
 	}
-	return v.children.arr[pos], true
+	return v.loadedChildren().arr[pos], true
 }
 
 func setAtIndex(v *V, child *V, pos int) error {
@@ -403,10 +423,11 @@ func setAtIndex(v *V, child *V, pos int) error {
 	if pos < 0 {
 		return ErrOutOfRange
 	}
+	c := v.loadedChildren()
 	if appendToEnd {
-		v.children.arr = append(v.children.arr, child)
+		c.arr = append(c.arr, child)
 	} else {
-		v.children.arr[pos] = child
+		c.arr[pos] = child
 	}
 	return nil
 }
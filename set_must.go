@@ -1,5 +1,7 @@
 package jsonvalue
 
+import "github.com/shopspring/decimal"
+
 // MARK: v.MustSet(xxx).At(xxx)
 
 // MustSetter is just like Setter, but not returning sub-value or error.
@@ -18,6 +20,12 @@ type MustSetter interface {
 	// JSON 值的子成员，并且设置到指定的位置上。设置的逻辑说明起来比较抽象，请打开以下的例子以了解,
 	// 这非常重要。
 	At(firstParam any, otherParams ...any)
+
+	// AtPointer is just like Setter.AtPointer(), but not returning sub-value or
+	// error.
+	//
+	// AtPointer 与 Setter.AtPointer() 相同, 但不返回子成员或错误。
+	AtPointer(ptr string)
 }
 
 type mSetter struct {
@@ -110,6 +118,13 @@ func (v *V) MustSetFloat32(f float32) MustSetter {
 	return v.MustSet(NewFloat32(f))
 }
 
+// MustSetDecimal is equivalent to Set(jsonvalue.NewDecimal(d))
+//
+// MustSetDecimal 等效于 Set(jsonvalue.NewDecimal(d))
+func (v *V) MustSetDecimal(d decimal.Decimal) MustSetter {
+	return v.MustSet(NewDecimal(d))
+}
+
 // MustSetNull is equivalent to Set(jsonvalue.NewNull())
 //
 // MustSetNull 等效于 Set(jsonvalue.NewNull())
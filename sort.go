@@ -50,17 +50,19 @@ func (v *sortArrayV) Sort() {
 }
 
 func (v *sortArrayV) Len() int {
-	return len(v.v.children.arr)
+	return len(v.v.loadedChildren().arr)
 }
 
 func (v *sortArrayV) Less(i, j int) bool {
-	v1 := v.v.children.arr[i]
-	v2 := v.v.children.arr[j]
+	c := v.v.loadedChildren()
+	v1 := c.arr[i]
+	v2 := c.arr[j]
 	return v.lessFunc(v1, v2)
 }
 
 func (v *sortArrayV) Swap(i, j int) {
-	v.v.children.arr[i], v.v.children.arr[j] = v.v.children.arr[j], v.v.children.arr[i]
+	c := v.v.loadedChildren()
+	c.arr[i], c.arr[j] = c.arr[j], c.arr[i]
 }
 
 // ---------------- marshal sorting ----------------
@@ -194,7 +196,9 @@ func (sov *sortObjectV) marshalObjectWithLessFunc(buf io.Writer, opt *Opt) {
 	for i, key := range sov.keys {
 		child := sov.values[i]
 		par := newParentInfo(child, sov.parentInfo, stringKey(key))
-		firstWritten = writeObjectChildren(par, buf, !firstWritten, key, child, opt)
+		if writeObjectChildren(par, buf, !firstWritten, key, child, opt) {
+			firstWritten = true
+		}
 	}
 }
 
@@ -219,13 +223,14 @@ func (sov *sortObjectV) Swap(i, j int) {
 }
 
 func newSortObjectV(v *V, parentInfo *ParentInfo, opt *Opt) *sortObjectV {
+	object := v.loadedChildren().object
 	sov := sortObjectV{
 		parentInfo: parentInfo,
 		lessFunc:   opt.MarshalLessFunc,
-		keys:       make([]string, 0, len(v.children.object)),
-		values:     make([]*V, 0, len(v.children.object)),
+		keys:       make([]string, 0, len(object)),
+		values:     make([]*V, 0, len(object)),
 	}
-	for k, child := range v.children.object {
+	for k, child := range object {
 		sov.keys = append(sov.keys, k)
 		sov.values = append(sov.values, child.v)
 	}
@@ -242,7 +247,9 @@ func (sssv *sortStringSliceV) marshalObjectWithStringSlice(buf io.Writer, opt *O
 	firstWritten := false
 	for i, key := range sssv.keys {
 		child := sssv.values[i]
-		firstWritten = writeObjectChildren(nil, buf, !firstWritten, key, child, opt)
+		if writeObjectChildren(nil, buf, !firstWritten, key, child, opt) {
+			firstWritten = true
+		}
 	}
 }
 
@@ -267,7 +274,7 @@ func newSortStringSliceV(v *V, opt *Opt) *sortStringSliceV {
 		keys:   make([]string, 0, v.Len()),
 		values: make([]*V, 0, v.Len()),
 	}
-	for k, child := range v.children.object {
+	for k, child := range v.loadedChildren().object {
 		sssv.keys = append(sssv.keys, k)
 		sssv.values = append(sssv.values, child.v)
 	}
@@ -276,8 +283,9 @@ func newSortStringSliceV(v *V, opt *Opt) *sortStringSliceV {
 }
 
 func newSortStringSliceVBySetSeq(v *V) *sortStringSliceV {
-	keySequence := make(map[string]int, len(v.children.object))
-	for k, child := range v.children.object {
+	c := v.loadedChildren()
+	keySequence := make(map[string]int, len(c.object))
+	for k, child := range c.object {
 		keySequence[k] = int(child.id)
 	}
 
@@ -287,7 +295,7 @@ func newSortStringSliceVBySetSeq(v *V) *sortStringSliceV {
 		keys:   make([]string, 0, v.Len()),
 		values: make([]*V, 0, v.Len()),
 	}
-	for k, child := range v.children.object {
+	for k, child := range c.object {
 		sssv.keys = append(sssv.keys, k)
 		sssv.values = append(sssv.values, child.v)
 	}
@@ -0,0 +1,416 @@
+package jsonvalue
+
+import (
+	"io"
+)
+
+// TokenType identifying type of a token read by Tokenizer.
+//
+// TokenType 表示 Tokenizer 读取到的符号的类型。
+type TokenType int
+
+const (
+	// TokenInvalid tells that the token is not valid
+	TokenInvalid TokenType = iota
+	// TokenObjectStart is '{' of an object
+	TokenObjectStart
+	// TokenObjectEnd is '}' of an object
+	TokenObjectEnd
+	// TokenArrayStart is '[' of an array
+	TokenArrayStart
+	// TokenArrayEnd is ']' of an array
+	TokenArrayEnd
+	// TokenKey is a key of object member
+	TokenKey
+	// TokenString is a string value
+	TokenString
+	// TokenNumber is a number value
+	TokenNumber
+	// TokenBool is a boolean value
+	TokenBool
+	// TokenNull is a null value
+	TokenNull
+)
+
+var tokenTypeStr = [TokenNull + 1]string{
+	"invalid",
+	"object start",
+	"object end",
+	"array start",
+	"array end",
+	"key",
+	"string",
+	"number",
+	"boolean",
+	"null",
+}
+
+// String show the name of token type
+func (t TokenType) String() string {
+	if t < 0 || t > TokenNull {
+		t = TokenInvalid
+	}
+	return tokenTypeStr[int(t)]
+}
+
+// Token is an event emitted by Tokenizer.
+//
+// Token 表示 Tokenizer 产生的一个事件。
+type Token struct {
+	// Type is the type of this token.
+	//
+	// Type 表示符号的类型。
+	Type TokenType
+	// Depth is the nesting depth of this token. Top-level values are at depth
+	// 0, starting and ending tokens of an object or array are at the same depth
+	// of the object or array, while its keys and values are one level deeper.
+	//
+	// Depth 表示符号的嵌套深度。顶层值的深度为 0, 对象或数组的起止符号与对象或数组本身深度相同, 而其中的键和值
+	// 则深一层。
+	Depth int
+	// Offset is the input stream byte offset of the first byte of this token,
+	// starting from 0.
+	//
+	// Offset 表示符号的第一个字节在输入流中的偏移量, 从 0 开始。
+	Offset int64
+
+	v *V
+}
+
+// String returns the key or the string value for TokenKey and TokenString
+// tokens, or text of other tokens, such as "{", "123" or "null".
+//
+// String 对于 TokenKey 和 TokenString 类型的符号, 返回键或字符串值; 对于其他符号, 则返回其文本, 如
+// "{", "123" 或 "null"。
+func (tok Token) String() string {
+	switch tok.Type {
+	default:
+		return ""
+	case TokenObjectStart:
+		return "{"
+	case TokenObjectEnd:
+		return "}"
+	case TokenArrayStart:
+		return "["
+	case TokenArrayEnd:
+		return "]"
+	case TokenKey, TokenString, TokenNumber, TokenBool, TokenNull:
+		return tok.v.String()
+	}
+}
+
+// Value returns the value of a TokenKey, TokenString, TokenNumber, TokenBool
+// or TokenNull token, which is the same as the one read by Unmarshal. A
+// NotExist value would be returned for other tokens.
+//
+// Value 返回 TokenKey, TokenString, TokenNumber, TokenBool 或 TokenNull 类型符号的值, 与使用
+// Unmarshal 读取到的值相同。对于其他符号, 则返回一个 NotExist 类型的值。
+func (tok Token) Value() *V {
+	if tok.v == nil {
+		return &V{}
+	}
+	return tok.v
+}
+
+type tokenizerState uint8
+
+const (
+	tokenizerExpectValue tokenizerState = iota
+	tokenizerExpectValueOrArrayEnd
+	tokenizerExpectKey
+	tokenizerExpectKeyOrObjectEnd
+	tokenizerExpectColon
+	tokenizerExpectCommaOrEnd
+)
+
+// Tokenizer reads JSON text from an input stream and emits tokens one by one,
+// without building any *V tree. Top-level values could be concatenated directly
+// or separated by blank characters, just like Decoder.
+//
+// Tokenizer 从一个输入流中读取 JSON 文本, 并逐个产生符号, 而不会构建任何 *V 树。与 Decoder 一样,
+// 顶层值可以直接首尾相连, 也可以用空白字符分隔。
+type Tokenizer struct {
+	readBuffer
+
+	stack  []byte // '{' or '[' of containers
+	state  tokenizerState
+	synErr error // sticky syntax error
+}
+
+// NewTokenizer returns a new tokenizer that reads from r. Data from r is read
+// incrementally and buffered only until a complete token is available.
+//
+// NewTokenizer 返回一个从 r 中读取数据的分词器。数据会被增量读取, 只缓存到能够组成一个完整的符号为止。
+func NewTokenizer(r io.Reader) *Tokenizer {
+	return &Tokenizer{
+		readBuffer: readBuffer{
+			r: r,
+		},
+	}
+}
+
+// Next returns the next token. io.EOF will be returned if there are no more
+// values in the input stream. Once a syntax error occurs, the same error would
+// be returned by all further calls.
+//
+// Next 返回下一个符号。如果输入流中已经没有更多的值了, 则返回 io.EOF。一旦出现语法错误, 后续的所有调用都会
+// 返回同样的错误。
+func (t *Tokenizer) Next() (Token, error) {
+	if t.r == nil {
+		return Token{}, ErrNilParameter
+	}
+	if t.synErr != nil {
+		return Token{}, t.synErr
+	}
+
+	tok, err := t.next()
+	if _, ok := err.(*SyntaxError); ok {
+		t.synErr = err
+	}
+	return tok, err
+}
+
+// InputOffset returns the input stream byte offset of the current tokenizer
+// position.
+//
+// InputOffset 返回分词器当前在输入流中的字节偏移量。
+func (t *Tokenizer) InputOffset() int64 {
+	return t.inputOffset()
+}
+
+func (t *Tokenizer) next() (Token, error) {
+	for {
+		if err := t.skipBlanks(); err != nil {
+			return Token{}, err
+		}
+
+		chr := t.buf[t.scanp]
+		offset := t.inputOffset()
+
+		switch t.state {
+		case tokenizerExpectColon:
+			if chr != ':' {
+				return Token{}, newSyntaxError(ErrNotObjectValue, int(offset), "':'", "missing colon for key")
+			}
+			t.scanp++
+			t.state = tokenizerExpectValue
+			continue
+
+		case tokenizerExpectCommaOrEnd:
+			top := t.stack[len(t.stack)-1]
+			switch {
+			case chr == ',':
+				t.scanp++
+				if top == '{' {
+					t.state = tokenizerExpectKey
+				} else {
+					t.state = tokenizerExpectValue
+				}
+				continue
+			case chr == '}' && top == '{':
+				return t.endContainer(TokenObjectEnd, offset), nil
+			case chr == ']' && top == '[':
+				return t.endContainer(TokenArrayEnd, offset), nil
+			case top == '{':
+				return Token{}, newSyntaxError(ErrNotObjectValue, int(offset), "',' or '}'", "invalid character \\u%04X", chr)
+			default:
+				return Token{}, newSyntaxError(ErrNotArrayValue, int(offset), "',' or ']'", "invalid character \\u%04X", chr)
+			}
+
+		case tokenizerExpectKey, tokenizerExpectKeyOrObjectEnd:
+			if chr == '}' && t.state == tokenizerExpectKeyOrObjectEnd {
+				return t.endContainer(TokenObjectEnd, offset), nil
+			}
+			if chr != '"' {
+				return Token{}, newSyntaxError(ErrNotObjectValue, int(offset), "string key", "invalid character \\u%04X", chr)
+			}
+			v, err := t.readString()
+			if err != nil {
+				return Token{}, err
+			}
+			t.state = tokenizerExpectColon
+			return Token{Type: TokenKey, Depth: len(t.stack), Offset: offset, v: v}, nil
+
+		default: // tokenizerExpectValue, tokenizerExpectValueOrArrayEnd
+			if chr == ']' && t.state == tokenizerExpectValueOrArrayEnd {
+				return t.endContainer(TokenArrayEnd, offset), nil
+			}
+			return t.readValue(chr, offset)
+		}
+	}
+}
+
+// skipBlanks skips blank characters and ensures that there is at least one
+// unread byte in buffer.
+func (t *Tokenizer) skipBlanks() error {
+	for {
+		offset, reachEnd := iter(t.buf).skipBlanks(t.scanp)
+		t.scanp = offset
+		if !reachEnd {
+			return nil
+		}
+
+		if t.err != nil {
+			if t.err != io.EOF {
+				return t.err
+			}
+			if len(t.stack) == 0 && t.state == tokenizerExpectValue {
+				return io.EOF
+			}
+			return t.unexpectedEOF()
+		}
+		t.refill()
+	}
+}
+
+func (t *Tokenizer) unexpectedEOF() error {
+	return newSyntaxError(ErrRawBytesUnrecognized, int(t.scanned)+len(t.buf), "", "unexpected EOF")
+}
+
+func (t *Tokenizer) readValue(chr byte, offset int64) (Token, error) {
+	tok := Token{Depth: len(t.stack), Offset: offset}
+
+	switch chr {
+	case '{':
+		t.scanp++
+		t.stack = append(t.stack, '{')
+		t.state = tokenizerExpectKeyOrObjectEnd
+		tok.Type = TokenObjectStart
+		return tok, nil
+
+	case '[':
+		t.scanp++
+		t.stack = append(t.stack, '[')
+		t.state = tokenizerExpectValueOrArrayEnd
+		tok.Type = TokenArrayStart
+		return tok, nil
+
+	case '"':
+		v, err := t.readString()
+		if err != nil {
+			return Token{}, err
+		}
+		tok.Type, tok.v = TokenString, v
+
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-', 't', 'f', 'n':
+		v, err := t.readLiteral(chr)
+		if err != nil {
+			return Token{}, err
+		}
+		tok.v = v
+		switch v.valueType {
+		case Number:
+			tok.Type = TokenNumber
+		case Boolean:
+			tok.Type = TokenBool
+		default:
+			tok.Type = TokenNull
+		}
+
+	default:
+		return Token{}, newSyntaxError(ErrRawBytesUnrecognized, int(offset), "value", "invalid character \\u%04X", chr)
+	}
+
+	t.valueDone()
+	return tok, nil
+}
+
+func (t *Tokenizer) endContainer(typ TokenType, offset int64) Token {
+	t.scanp++
+	t.stack = t.stack[:len(t.stack)-1]
+	t.valueDone()
+	return Token{Type: typ, Depth: len(t.stack), Offset: offset}
+}
+
+// valueDone updates state after a whole value is read.
+func (t *Tokenizer) valueDone() {
+	if len(t.stack) == 0 {
+		t.state = tokenizerExpectValue
+	} else {
+		t.state = tokenizerExpectCommaOrEnd
+	}
+}
+
+// readString reads a string starting at t.buf[t.scanp] with the same escape
+// handling as Unmarshal.
+func (t *Tokenizer) readString() (*V, error) {
+	end, err := t.ensure()
+	if err != nil {
+		return nil, err
+	}
+
+	base := t.inputOffset()
+	it := iter(t.buf[t.scanp:end])
+	le, _, err := it.parseStrFromBytesForwardWithQuote(0)
+	if err != nil {
+		return nil, shiftSyntaxError(err, base)
+	}
+	t.scanp = end
+	return NewString(string(it[1 : 1+le])), nil
+}
+
+// readLiteral reads a number, true, false or null with the same parsing as
+// Unmarshal.
+func (t *Tokenizer) readLiteral(chr byte) (*V, error) {
+	end, err := t.ensure()
+	if err != nil {
+		return nil, err
+	}
+
+	base := t.inputOffset()
+	b := make([]byte, end-t.scanp)
+	copy(b, t.buf[t.scanp:end])
+	it := iter(b)
+
+	var v *V
+	var sectEnd int
+	switch chr {
+	case 't':
+		sectEnd, err = it.parseTrue(0)
+		v = NewBool(true)
+	case 'f':
+		sectEnd, err = it.parseFalse(0)
+		v = NewBool(false)
+	case 'n':
+		sectEnd, err = it.parseNull(0)
+		v = NewNull()
+	default:
+		v, sectEnd, _, err = it.parseNumber(globalPool{}, 0)
+	}
+	if err == nil && sectEnd != len(b) {
+		err = newSyntaxError(ErrRawBytesUnrecognized, sectEnd, "", "invalid character \\u%04X", b[sectEnd])
+	}
+	if err != nil {
+		return nil, shiftSyntaxError(err, base)
+	}
+
+	t.scanp = end
+	return v, nil
+}
+
+// ensure reads more data until a complete string or literal starting at
+// t.buf[t.scanp] is buffered, and returns the end of it in t.buf. Scanning
+// continues from where it stopped after each reading.
+func (t *Tokenizer) ensure() (int, error) {
+	s := valueScanner{}
+	for {
+		if n, complete := s.scan(t.buf[t.scanp:], t.err == io.EOF); complete {
+			return t.scanp + n, nil
+		}
+		if t.err != nil {
+			if t.err != io.EOF {
+				return 0, t.err
+			}
+			return 0, t.unexpectedEOF()
+		}
+		t.refill()
+	}
+}
+
+// shiftSyntaxError converts offset of a *SyntaxError to input stream offset.
+func shiftSyntaxError(err error, base int64) error {
+	if se, ok := err.(*SyntaxError); ok {
+		se.Offset += int(base)
+	}
+	return err
+}
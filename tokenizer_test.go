@@ -0,0 +1,234 @@
+package jsonvalue
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func testTokenizer(t *testing.T) {
+	cv("token sequence", func() { testTokenizerSequence(t) })
+	cv("same as unmarshal", func() { testTokenizerSameAsUnmarshal(t) })
+	cv("multiple values", func() { testTokenizerMultipleValues(t) })
+	cv("errors", func() { testTokenizerErrors(t) })
+}
+
+type testTokenizerToken struct {
+	typ   TokenType
+	depth int
+	off   int64
+	s     string
+}
+
+func readAllTokens(tk *Tokenizer) ([]testTokenizerToken, error) {
+	var res []testTokenizerToken
+	for {
+		tok, err := tk.Next()
+		if err != nil {
+			return res, err
+		}
+		res = append(res, testTokenizerToken{tok.Type, tok.Depth, tok.Offset, tok.String()})
+	}
+}
+
+func testTokenizerSequence(t *testing.T) {
+	raw := `{"a": [1, "x\n", true], "b": {}, "c": null, "d": []}`
+	expected := []testTokenizerToken{
+		{TokenObjectStart, 0, 0, "{"},
+		{TokenKey, 1, 1, "a"},
+		{TokenArrayStart, 1, 6, "["},
+		{TokenNumber, 2, 7, "1"},
+		{TokenString, 2, 10, "x\n"},
+		{TokenBool, 2, 17, "true"},
+		{TokenArrayEnd, 1, 21, "]"},
+		{TokenKey, 1, 24, "b"},
+		{TokenObjectStart, 1, 29, "{"},
+		{TokenObjectEnd, 1, 30, "}"},
+		{TokenKey, 1, 33, "c"},
+		{TokenNull, 1, 38, "null"},
+		{TokenKey, 1, 44, "d"},
+		{TokenArrayStart, 1, 49, "["},
+		{TokenArrayEnd, 1, 50, "]"},
+		{TokenObjectEnd, 0, 51, "}"},
+	}
+
+	tokens, err := readAllTokens(NewTokenizer(strings.NewReader(raw)))
+	so(err, eq, io.EOF)
+	so(tokens, resemble, expected)
+
+	// reading byte by byte gives the same result
+	tk := NewTokenizer(iotest.OneByteReader(strings.NewReader(raw)))
+	tokens, err = readAllTokens(tk)
+	so(err, eq, io.EOF)
+	so(tokens, resemble, expected)
+	so(tk.InputOffset(), eq, len(raw))
+
+	// EOF is sticky
+	_, err = tk.Next()
+	so(err, eq, io.EOF)
+
+	so(TokenKey.String(), eq, "key")
+	so(TokenType(100).String(), eq, "invalid")
+	so(Token{}.String(), eq, "")
+	so(Token{}.Value().ValueType(), eq, NotExist)
+	so(Token{Type: TokenArrayStart}.Value().ValueType(), eq, NotExist)
+}
+
+// buildFromTokens rebuilds a value from tokens, checking that tokens are
+// enough to re-generate the same value.
+func buildFromTokens(tk *Tokenizer) (*V, error) {
+	tok, err := tk.Next()
+	if err != nil {
+		return nil, err
+	}
+	switch tok.Type {
+	default:
+		return tok.Value(), nil
+	case TokenObjectStart:
+		obj := NewObject()
+		for {
+			tok, err := tk.Next()
+			if err != nil {
+				return nil, err
+			}
+			if tok.Type == TokenObjectEnd {
+				return obj, nil
+			}
+			child, err := buildFromTokens(tk)
+			if err != nil {
+				return nil, err
+			}
+			obj.MustSet(child).At(tok.String())
+		}
+	case TokenArrayStart:
+		arr := NewArray()
+		for {
+			child, err := buildFromTokens(tk)
+			if err != nil {
+				return nil, err
+			}
+			if child.ValueType() == NotExist {
+				return arr, nil // array end
+			}
+			arr.MustAppend(child).InTheEnd()
+		}
+	}
+}
+
+func testTokenizerSameAsUnmarshal(t *testing.T) {
+	raw := `{
+		"str": "a\"b\\c\/中😀\t\u0000",
+		"int": -9223372036854775808, "uint": 18446744073709551615,
+		"float": -1.25e-3, "zero": 0, "big": 12345678901234567890.5,
+		"arr": [[], {}, [null, false, {"nested": "中文"}]],
+		"dup": 1, "dup": 2
+	}`
+	full, err := UnmarshalString(raw)
+	so(err, isNil)
+
+	readers := []io.Reader{
+		strings.NewReader(raw),
+		iotest.OneByteReader(strings.NewReader(raw)),
+		iotest.HalfReader(strings.NewReader(raw)),
+	}
+	for _, r := range readers {
+		v, err := buildFromTokens(NewTokenizer(r))
+		so(err, isNil)
+		so(v.Equal(full), isTrue)
+		so(v.MustGet("str").String(), eq, full.MustGet("str").String())
+		so(v.MustGet("int").Int64(), eq, full.MustGet("int").Int64())
+		so(v.MustGet("uint").Uint64(), eq, full.MustGet("uint").Uint64())
+		so(v.MustGet("big").String(), eq, "12345678901234567890.5")
+		so(v.MustGet("dup").Int(), eq, 2)
+	}
+
+	// a long string across many reads
+	long := strings.Repeat("中文abc\\n", 1000)
+	tk := NewTokenizer(iotest.HalfReader(strings.NewReader(`["` + long + `", 1]`)))
+	tokens, err := readAllTokens(tk)
+	so(err, eq, io.EOF)
+	so(len(tokens), eq, 4)
+	so(tokens[1].s, eq, strings.Repeat("中文abc\n", 1000))
+	so(tokens[2].off, eq, len(long)+5)
+
+	// long string and number read byte by byte, which are scanned only once
+	long = strings.Repeat("\\\"", 50000)
+	num := strings.Repeat("1", 50000)
+	tk = NewTokenizer(iotest.OneByteReader(strings.NewReader(`["` + long + `",` + num + `]`)))
+	tokens, err = readAllTokens(tk)
+	so(err, eq, io.EOF)
+	so(len(tokens), eq, 4)
+	so(tokens[1].s, eq, strings.Repeat(`"`, 50000))
+	so(tokens[2].s, eq, num)
+}
+
+func testTokenizerMultipleValues(t *testing.T) {
+	tokens, err := readAllTokens(NewTokenizer(strings.NewReader(" 1 \"a\"{}[]true\nnull -2.5 ")))
+	so(err, eq, io.EOF)
+	so(tokens, resemble, []testTokenizerToken{
+		{TokenNumber, 0, 1, "1"},
+		{TokenString, 0, 3, "a"},
+		{TokenObjectStart, 0, 6, "{"},
+		{TokenObjectEnd, 0, 7, "}"},
+		{TokenArrayStart, 0, 8, "["},
+		{TokenArrayEnd, 0, 9, "]"},
+		{TokenBool, 0, 10, "true"},
+		{TokenNull, 0, 15, "null"},
+		{TokenNumber, 0, 20, "-2.5"},
+	})
+
+	tokens, err = readAllTokens(NewTokenizer(strings.NewReader("")))
+	so(err, eq, io.EOF)
+	so(len(tokens), eq, 0)
+}
+
+func testTokenizerErrors(t *testing.T) {
+	_, err := NewTokenizer(nil).Next()
+	so(errors.Is(err, ErrNilParameter), isTrue)
+
+	cases := []struct {
+		raw    string
+		err    error
+		offset int
+		tokens int
+	}{
+		{`{"a" 1}`, ErrNotObjectValue, 5, 2},
+		{`{"a":1 "b":2}`, ErrNotObjectValue, 7, 3},
+		{`{"a":1,}`, ErrNotObjectValue, 7, 3},
+		{`{1:1}`, ErrNotObjectValue, 1, 1},
+		{`[1 2]`, ErrNotArrayValue, 3, 2},
+		{`[1,]`, ErrRawBytesUnrecognized, 3, 2},
+		{`[1}`, ErrNotArrayValue, 2, 2},
+		{`}`, ErrRawBytesUnrecognized, 0, 0},
+		{`[01]`, ErrNotValidNumberValue, 1, 1},
+		{`[1.5.5]`, ErrNotValidNumberValue, 4, 1},
+		{`[12ab]`, ErrRawBytesUnrecognized, 3, 1},
+		{`[tru]`, ErrNotValidBoolValue, 1, 1},
+		{`[nul, 1]`, ErrNotValidNullValue, 1, 1},
+		{`["a\x"]`, ErrIllegalString, 3, 1},
+		{`["a`, ErrRawBytesUnrecognized, 3, 1},
+		{`{"a":[1`, ErrRawBytesUnrecognized, 7, 4},
+		{`{"a"`, ErrRawBytesUnrecognized, 4, 2},
+	}
+	for _, c := range cases {
+		tk := NewTokenizer(iotest.OneByteReader(strings.NewReader(c.raw)))
+		tokens, err := readAllTokens(tk)
+		so(errors.Is(err, c.err), isTrue)
+		var se *SyntaxError
+		so(errors.As(err, &se), isTrue)
+		so(se.Offset, eq, c.offset)
+		so(len(tokens), eq, c.tokens)
+
+		// syntax error is sticky
+		_, again := tk.Next()
+		so(again, eq, err)
+	}
+
+	// reading errors
+	tk := NewTokenizer(iotest.TimeoutReader(strings.NewReader(`[1, 2`)))
+	tokens, err := readAllTokens(tk)
+	so(err, eq, iotest.ErrTimeout)
+	so(len(tokens), eq, 2)
+}
@@ -1,7 +1,6 @@
 package jsonvalue
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 
@@ -12,23 +11,39 @@ import (
 
 // unmarshalWithIter parse bytes with unknown value type.
 func unmarshalWithIter(p pool, it iter, offset int) (v *V, err error) {
+	return unmarshalWithIterAndOptions(p, it, offset, nil)
+}
+
+// unmarshalWithIterAndOptions parse bytes with unknown value type and given
+// unmarshal options. Options which change nothing are replaced by nil, with
+// which all option checks are skipped.
+func unmarshalWithIterAndOptions(p pool, it iter, offset int, opt *unmarshalOptions) (v *V, err error) {
+	if opt.isDefault() {
+		opt = nil // no checks at all
+	} else {
+		opt = opt.forParsing()
+	}
 	end := len(it)
-	offset, reachEnd := it.skipBlanks(offset)
+	offset, reachEnd := opt.skipBlanks(it, offset, end)
 	if reachEnd {
-		return &V{}, fmt.Errorf("%w, cannot find any symbol characters found", ErrRawBytesUnrecognized)
+		return &V{}, newSyntaxError(ErrRawBytesUnrecognized, offset, "value", "cannot find any symbol characters")
+	}
+	if err = opt.addNode(offset); err != nil {
+		return &V{}, err
 	}
 
+	start := offset
 	chr := it[offset]
-	switch chr {
+	switch opt.tokenKind(chr) {
 	case '{':
-		v, offset, err = unmarshalObjectWithIterUnknownEnd(p, it, offset, end)
+		v, offset, err = unmarshalObjectWithIterUnknownEnd(p, it, offset, end, opt)
 
 	case '[':
-		v, offset, err = unmarshalArrayWithIterUnknownEnd(p, it, offset, end)
+		v, offset, err = unmarshalArrayWithIterUnknownEnd(p, it, offset, end, opt)
 
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-':
 		var n *V
-		n, offset, _, err = it.parseNumber(p, offset)
+		n, offset, _, err = opt.parseNumber(p, it, offset)
 		if err == nil {
 			v = n
 		}
@@ -36,40 +51,46 @@ func unmarshalWithIter(p pool, it iter, offset int) (v *V, err error) {
 	case '"':
 		var sectLenWithoutQuote int
 		var sectEnd int
-		sectLenWithoutQuote, sectEnd, err = it.parseStrFromBytesForwardWithQuote(offset)
+		sectLenWithoutQuote, sectEnd, err = opt.parseString(it, offset)
 		if err == nil {
-			v, err = NewString(unsafe.BtoS(it[offset+1:offset+1+sectLenWithoutQuote])), nil
+			err = opt.checkString(it, offset+1, sectLenWithoutQuote)
+		}
+		if err == nil {
+			v = newString(p, unsafe.BtoS(it[offset+1:offset+1+sectLenWithoutQuote]))
 			offset = sectEnd
 		}
 
 	case 't':
 		offset, err = it.parseTrue(offset)
 		if err == nil {
-			v = NewBool(true)
+			v = newBool(p, true)
 		}
 
 	case 'f':
 		offset, err = it.parseFalse(offset)
 		if err == nil {
-			v = NewBool(false)
+			v = newBool(p, false)
 		}
 
 	case 'n':
 		offset, err = it.parseNull(offset)
 		if err == nil {
-			v = NewNull()
+			v = newNull(p)
 		}
 
 	default:
-		return &V{}, fmt.Errorf("%w, invalid character \\u%04X at Position %d", ErrRawBytesUnrecognized, chr, offset)
+		return &V{}, newSyntaxError(ErrRawBytesUnrecognized, offset, "value", "invalid character \\u%04X", chr)
 	}
 
 	if err != nil {
 		return &V{}, err
 	}
+	if opt != nil && opt.onNode != nil {
+		opt.onNode(v, nodeSpan{start, offset, -1, -1})
+	}
 
-	if offset, reachEnd = it.skipBlanks(offset, end); !reachEnd {
-		return &V{}, fmt.Errorf("%w, unnecessary trailing data remains at Position %d", ErrRawBytesUnrecognized, offset)
+	if offset, reachEnd = opt.skipBlanks(it, offset, end); !reachEnd {
+		return &V{}, newSyntaxError(ErrRawBytesUnrecognized, offset, "end of input", "unnecessary trailing data remains")
 	}
 
 	return v, nil
@@ -77,30 +98,51 @@ func unmarshalWithIter(p pool, it iter, offset int) (v *V, err error) {
 
 // unmarshalArrayWithIterUnknownEnd is similar with unmarshalArrayWithIter, though should start with '[',
 // but it does not known where its ']' is
-func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V, end int, err error) {
+func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int, opt *unmarshalOptions) (_ *V, end int, err error) {
+	if err = opt.enterContainer(offset); err != nil {
+		return nil, -1, err
+	}
 	offset++
 	arr := newArray(p)
 
 	reachEnd := false
+	sep := separatorState{}
+	if opt != nil {
+		sep.allowTrailingComma = opt.json5.trailingCommas
+	}
+	count := 0
 
 	for offset < right {
 		// search for ending ']'
-		offset, reachEnd = it.skipBlanks(offset, right)
+		offset, reachEnd = opt.skipBlanks(it, offset, right)
 		if reachEnd {
 			// ']' not found
-			return nil, -1, fmt.Errorf("%w, cannot find ']'", ErrNotArrayValue)
+			return nil, -1, newSyntaxError(ErrNotArrayValue, offset, "']'", "cannot find ']'")
 		}
 
-		chr := it[offset]
-		switch chr {
+		start, chr := offset, it[offset]
+		if opt != nil && opt.strictSeparators {
+			if err = sep.checkInArray(offset, chr); err != nil {
+				return nil, -1, err
+			}
+		}
+		if chr != ']' && chr != ',' {
+			count++
+			if err = opt.addArrayElement(offset, count); err != nil {
+				return nil, -1, err
+			}
+		}
+
+		switch opt.tokenKind(chr) {
 		case ']':
+			opt.leaveContainer()
 			return arr, offset + 1, nil
 
 		case ',':
 			offset++
 
 		case '{':
-			v, sectEnd, err := unmarshalObjectWithIterUnknownEnd(p, it, offset, right)
+			v, sectEnd, err := unmarshalObjectWithIterUnknownEnd(p, it, offset, right, opt)
 			if err != nil {
 				return nil, -1, err
 			}
@@ -108,7 +150,7 @@ func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V,
 			offset = sectEnd
 
 		case '[':
-			v, sectEnd, err := unmarshalArrayWithIterUnknownEnd(p, it, offset, right)
+			v, sectEnd, err := unmarshalArrayWithIterUnknownEnd(p, it, offset, right, opt)
 			if err != nil {
 				return nil, -1, err
 			}
@@ -117,7 +159,7 @@ func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V,
 
 		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-':
 			var v *V
-			v, sectEnd, _, err := it.parseNumber(p, offset)
+			v, sectEnd, _, err := opt.parseNumber(p, it, offset)
 			if err != nil {
 				return nil, -1, err
 			}
@@ -125,11 +167,14 @@ func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V,
 			offset = sectEnd
 
 		case '"':
-			sectLenWithoutQuote, sectEnd, err := it.parseStrFromBytesForwardWithQuote(offset)
+			sectLenWithoutQuote, sectEnd, err := opt.parseString(it, offset)
 			if err != nil {
 				return nil, -1, err
 			}
-			v := NewString(unsafe.BtoS(it[offset+1 : offset+1+sectLenWithoutQuote]))
+			if err = opt.checkString(it, offset+1, sectLenWithoutQuote); err != nil {
+				return nil, -1, err
+			}
+			v := newString(p, unsafe.BtoS(it[offset+1:offset+1+sectLenWithoutQuote]))
 			appendToArr(arr, v)
 			offset = sectEnd
 
@@ -138,7 +183,7 @@ func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V,
 			if err != nil {
 				return nil, -1, err
 			}
-			appendToArr(arr, NewBool(true))
+			appendToArr(arr, newBool(p, true))
 			offset = sectEnd
 
 		case 'f':
@@ -146,7 +191,7 @@ func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V,
 			if err != nil {
 				return nil, -1, err
 			}
-			appendToArr(arr, NewBool(false))
+			appendToArr(arr, newBool(p, false))
 			offset = sectEnd
 
 		case 'n':
@@ -154,71 +199,113 @@ func unmarshalArrayWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V,
 			if err != nil {
 				return nil, -1, err
 			}
-			appendToArr(arr, NewNull())
+			appendToArr(arr, newNull(p))
 			offset = sectEnd
 
 		default:
-			return nil, -1, fmt.Errorf("%w, invalid character \\u%04X at Position %d", ErrRawBytesUnrecognized, chr, offset)
+			return nil, -1, newSyntaxError(ErrRawBytesUnrecognized, offset, "value or ']'", "invalid character \\u%04X", chr)
+		}
+
+		if opt != nil && opt.onNode != nil && chr != ',' {
+			opt.onNode(arr.children.arr[len(arr.children.arr)-1], nodeSpan{start, offset, -1, -1})
 		}
 	}
 
-	return nil, -1, fmt.Errorf("%w, cannot find ']'", ErrNotArrayValue)
+	return nil, -1, newSyntaxError(ErrNotArrayValue, offset, "']'", "cannot find ']'")
 }
 
 func appendToArr(v *V, child *V) {
-	if v.children.arr == nil {
-		v.children.arr = make([]*V, 0, initialArrayCapacity)
+	c := v.loadedChildren()
+	if c.arr == nil {
+		c.arr = make([]*V, 0, initialArrayCapacity)
+	}
+	c.arr = append(c.arr, child)
+}
+
+// isObjectValueStart tells whether chr in an object starts a value rather than
+// a key or separator.
+func isObjectValueStart(chr byte, keyRead bool) bool {
+	switch chr {
+	case '}', ',', ':':
+		return false
+	default:
+		return keyRead
 	}
-	v.children.arr = append(v.children.arr, child)
 }
 
 // unmarshalObjectWithIterUnknownEnd unmarshal object from raw bytes. it[offset] must be '{'
-func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V, end int, err error) {
+func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int, opt *unmarshalOptions) (_ *V, end int, err error) {
+	if err = opt.enterContainer(offset); err != nil {
+		return nil, -1, err
+	}
 	offset++
 	obj := newObject(p)
 
 	keyStart, keyEnd := 0, 0
+	keyTokenStart, keyTokenEnd := 0, 0
 	colonFound := false
 
 	reachEnd := false
+	sep := separatorState{}
+	if opt != nil {
+		sep.allowTrailingComma = opt.json5.trailingCommas
+	}
+	count := 0
 
 	keyNotFoundErr := func() error {
 		if keyEnd == 0 {
-			return fmt.Errorf(
-				"%w, missing key for another value at Position %d", ErrNotObjectValue, offset,
-			)
+			return newSyntaxError(ErrNotObjectValue, offset, "string key", "missing key for another value")
 		}
 		if !colonFound {
-			return fmt.Errorf(
-				"%w, missing colon for key at Position %d", ErrNotObjectValue, offset,
-			)
+			return newSyntaxError(ErrNotObjectValue, offset, "':'", "missing colon for key")
 		}
 		return nil
 	}
 
 	valNotFoundErr := func() error {
 		if keyEnd > 0 {
-			return fmt.Errorf(
-				"%w, missing value for key '%s' at Position %d",
-				ErrNotObjectValue, unsafe.BtoS(it[keyStart:keyEnd]), keyStart,
+			return newSyntaxError(
+				ErrNotObjectValue, keyStart, "value",
+				"missing value for key '%s'", unsafe.BtoS(it[keyStart:keyEnd]),
 			)
 		}
 		return nil
 	}
 
 	for offset < right {
-		offset, reachEnd = it.skipBlanks(offset, right)
+		offset, reachEnd = opt.skipBlanks(it, offset, right)
 		if reachEnd {
 			// '}' not found
-			return nil, -1, fmt.Errorf("%w, cannot find '}'", ErrNotObjectValue)
+			return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "'}'", "cannot find '}'")
 		}
 
 		chr := it[offset]
-		switch chr {
+		if opt != nil && opt.strictSeparators {
+			if err = sep.checkInObject(offset, chr, keyEnd > 0); err != nil {
+				return nil, -1, err
+			}
+		}
+		if isObjectValueStart(chr, keyEnd > 0) {
+			count++
+			if err = opt.addObjectMember(offset, count); err != nil {
+				return nil, -1, err
+			}
+		} else if keyEnd == 0 && opt != nil && opt.json5.unquotedKeys && isIdentifierStart(chr) {
+			keyStart, keyEnd = offset, it.parseUnquotedKey(offset, right)
+			keyTokenStart, keyTokenEnd = keyStart, keyEnd
+			if err = opt.checkString(it, keyStart, keyEnd-keyStart); err != nil {
+				return nil, -1, err
+			}
+			offset = keyEnd
+			continue
+		}
+
+		switch opt.tokenKind(chr) {
 		case '}':
 			if err = valNotFoundErr(); err != nil {
 				return nil, -1, err
 			}
+			opt.leaveContainer()
 			return obj, offset + 1, nil
 
 		case ',':
@@ -230,7 +317,7 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 
 		case ':':
 			if colonFound {
-				return nil, -1, fmt.Errorf("%w, duplicate colon at Position %d", ErrNotObjectValue, keyStart)
+				return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "value", "duplicate colon")
 			}
 			colonFound = true
 			if err = keyNotFoundErr(); err != nil {
@@ -243,11 +330,14 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 			if err = keyNotFoundErr(); err != nil {
 				return nil, -1, err
 			}
-			v, sectEnd, err := unmarshalObjectWithIterUnknownEnd(p, it, offset, right)
+			v, sectEnd, err := unmarshalObjectWithIterUnknownEnd(p, it, offset, right, opt)
 			if err != nil {
 				return nil, -1, err
 			}
-			setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), v)
+			span := nodeSpan{offset, sectEnd, keyTokenStart, keyTokenEnd}
+			if err = opt.setObjectChild(obj, it, keyStart, keyEnd, v, span); err != nil {
+				return nil, -1, err
+			}
 			keyEnd, colonFound = 0, false
 			offset = sectEnd
 
@@ -255,11 +345,14 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 			if err = keyNotFoundErr(); err != nil {
 				return nil, -1, err
 			}
-			v, sectEnd, err := unmarshalArrayWithIterUnknownEnd(p, it, offset, right)
+			v, sectEnd, err := unmarshalArrayWithIterUnknownEnd(p, it, offset, right, opt)
 			if err != nil {
 				return nil, -1, err
 			}
-			setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), v)
+			span := nodeSpan{offset, sectEnd, keyTokenStart, keyTokenEnd}
+			if err = opt.setObjectChild(obj, it, keyStart, keyEnd, v, span); err != nil {
+				return nil, -1, err
+			}
 			keyEnd, colonFound = 0, false
 			offset = sectEnd
 
@@ -268,11 +361,14 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 				return nil, -1, err
 			}
 			var v *V
-			v, sectEnd, _, err := it.parseNumber(p, offset)
+			v, sectEnd, _, err := opt.parseNumber(p, it, offset)
 			if err != nil {
 				return nil, -1, err
 			}
-			setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), v)
+			span := nodeSpan{offset, sectEnd, keyTokenStart, keyTokenEnd}
+			if err = opt.setObjectChild(obj, it, keyStart, keyEnd, v, span); err != nil {
+				return nil, -1, err
+			}
 			keyEnd, colonFound = 0, false
 			offset = sectEnd
 
@@ -280,26 +376,37 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 			if keyEnd > 0 {
 				// string value
 				if !colonFound {
-					return nil, -1, fmt.Errorf("%w, missing value for key '%s' at Position %d",
-						ErrNotObjectValue, unsafe.BtoS(it[keyStart:keyEnd]), keyStart,
+					return nil, -1, newSyntaxError(
+						ErrNotObjectValue, offset, "':'",
+						"missing colon for key '%s'", unsafe.BtoS(it[keyStart:keyEnd]),
 					)
 				}
-				sectLenWithoutQuote, sectEnd, err := it.parseStrFromBytesForwardWithQuote(offset)
+				sectLenWithoutQuote, sectEnd, err := opt.parseString(it, offset)
 				if err != nil {
 					return nil, -1, err
 				}
-				v := NewString(unsafe.BtoS(it[offset+1 : offset+1+sectLenWithoutQuote]))
-				setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), v)
+				if err = opt.checkString(it, offset+1, sectLenWithoutQuote); err != nil {
+					return nil, -1, err
+				}
+				v := newString(p, unsafe.BtoS(it[offset+1:offset+1+sectLenWithoutQuote]))
+				span := nodeSpan{offset, sectEnd, keyTokenStart, keyTokenEnd}
+				if err = opt.setObjectChild(obj, it, keyStart, keyEnd, v, span); err != nil {
+					return nil, -1, err
+				}
 				keyEnd, colonFound = 0, false
 				offset = sectEnd
 
 			} else {
 				// string key
-				sectLenWithoutQuote, sectEnd, err := it.parseStrFromBytesForwardWithQuote(offset)
+				sectLenWithoutQuote, sectEnd, err := opt.parseString(it, offset)
 				if err != nil {
 					return nil, -1, err
 				}
+				if err = opt.checkString(it, offset+1, sectLenWithoutQuote); err != nil {
+					return nil, -1, err
+				}
 				keyStart, keyEnd = offset+1, offset+1+sectLenWithoutQuote
+				keyTokenStart, keyTokenEnd = offset, sectEnd
 				offset = sectEnd
 			}
 
@@ -311,7 +418,10 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 			if err != nil {
 				return nil, -1, err
 			}
-			setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), NewBool(true))
+			span := nodeSpan{offset, sectEnd, keyTokenStart, keyTokenEnd}
+			if err = opt.setObjectChild(obj, it, keyStart, keyEnd, newBool(p, true), span); err != nil {
+				return nil, -1, err
+			}
 			keyEnd, colonFound = 0, false
 			offset = sectEnd
 
@@ -323,7 +433,10 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 			if err != nil {
 				return nil, -1, err
 			}
-			setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), NewBool(false))
+			span := nodeSpan{offset, sectEnd, keyTokenStart, keyTokenEnd}
+			if err = opt.setObjectChild(obj, it, keyStart, keyEnd, newBool(p, false), span); err != nil {
+				return nil, -1, err
+			}
 			keyEnd, colonFound = 0, false
 			offset = sectEnd
 
@@ -335,17 +448,20 @@ func unmarshalObjectWithIterUnknownEnd(p pool, it iter, offset, right int) (_ *V
 			if err != nil {
 				return nil, -1, err
 			}
-			setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), NewNull())
+			span := nodeSpan{offset, sectEnd, keyTokenStart, keyTokenEnd}
+			if err = opt.setObjectChild(obj, it, keyStart, keyEnd, newNull(p), span); err != nil {
+				return nil, -1, err
+			}
 			keyEnd, colonFound = 0, false
 			offset = sectEnd
 
 		default:
-			return nil, -1, fmt.Errorf("%w, invalid character \\u%04X at Position %d", ErrRawBytesUnrecognized, chr, offset)
+			return nil, -1, newSyntaxError(ErrRawBytesUnrecognized, offset, "", "invalid character \\u%04X", chr)
 		}
 
 	}
 
-	return nil, -1, fmt.Errorf("%w, cannot find '}'", ErrNotObjectValue)
+	return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "'}'", "cannot find '}'")
 }
 
 // parseNumber parse a number string. Reference:
@@ -359,7 +475,7 @@ func parseNumber(v *V, p pool) (err error) {
 		return err
 	}
 	if !reachEnd {
-		return fmt.Errorf("invalid character: 0x%02x", v.srcByte[end])
+		return newSyntaxError(ErrNotValidNumberValue, end, "end of number", "invalid character: 0x%02x", v.srcByte[end])
 	}
 
 	*v = *parsed
@@ -385,9 +501,9 @@ func (it iter) parseStrFromBytesForwardWithQuote(offset int) (sectLenWithoutQuot
 
 	shift := func(i *int, le int) {
 		if end-*i < le {
-			err = fmt.Errorf(
-				"%w, expect at least %d remaining bytes, but got %d at Position %d",
-				ErrIllegalString, end-*i, le, *i,
+			err = newSyntaxError(
+				ErrIllegalString, *i, "",
+				"expect at least %d remaining bytes, but got %d", le, end-*i,
 			)
 			return
 		}
@@ -419,26 +535,26 @@ func (it iter) parseStrFromBytesForwardWithQuote(offset int) (sectLenWithoutQuot
 		case runeIdentifyingBytes4(chr):
 			shift(&i, 4)
 		default:
-			err = fmt.Errorf("%w: illegal UTF8 string at Position %d", ErrIllegalString, i)
+			err = newSyntaxError(ErrIllegalString, i, "", "illegal UTF8 string")
 		}
 		if err != nil {
 			return -1, -1, err
 		}
 	}
 
-	err = errors.New("ending double quote of a string is not found")
+	err = newSyntaxError(ErrIllegalString, offset-1, "'\"'", "ending double quote of a string is not found")
 	return
 }
 
 func (it iter) handleEscapeStart(i *int, sectEnd *int) error {
 	if len(it)-1-*i < 1 {
-		return errors.New("escape symbol not followed by another character")
+		return newSyntaxError(ErrIllegalString, *i, "escaped character", "escape symbol not followed by another character")
 	}
 
 	chr := it[*i+1]
 	switch chr {
 	default:
-		return fmt.Errorf("unrecognized character 0x%02X after escape symbol", chr)
+		return newSyntaxError(ErrIllegalString, *i, "escaped character", "unrecognized character 0x%02X after escape symbol", chr)
 	case '"', '\'', '/', '\\':
 		it[*sectEnd] = chr
 		*sectEnd++
@@ -471,7 +587,7 @@ func (it iter) handleEscapeStart(i *int, sectEnd *int) error {
 
 func (it iter) handleEscapeUnicodeStartWithEnd(i *int, end int, sectEnd *int) (err error) {
 	if end-*i <= 5 {
-		return errors.New("escape symbol not followed by another character")
+		return newSyntaxError(ErrIllegalString, *i, "4 hexadecimal digits", "insufficient unicode escaping characters")
 	}
 
 	b3 := chrToHex(it[*i+2], &err)
@@ -479,7 +595,7 @@ func (it iter) handleEscapeUnicodeStartWithEnd(i *int, end int, sectEnd *int) (e
 	b1 := chrToHex(it[*i+4], &err)
 	b0 := chrToHex(it[*i+5], &err)
 	if err != nil {
-		return
+		return newSyntaxError(ErrIllegalString, *i, "4 hexadecimal digits", "%v", err)
 	}
 
 	r := (rune(b3) << 12) + (rune(b2) << 8) + (rune(b1) << 4) + rune(b0)
@@ -495,10 +611,10 @@ func (it iter) handleEscapeUnicodeStartWithEnd(i *int, end int, sectEnd *int) (e
 	// reference: [JSON 序列化中的转义和 Unicode 编码](https://cloud.tencent.com/developer/article/1625557/)
 	// should get another unicode-escaped character
 	if end-*i <= 11 {
-		return fmt.Errorf("insufficient UTF-16 data at offset %d", *i)
+		return newSyntaxError(ErrIllegalString, *i, "UTF-16 low surrogate", "insufficient UTF-16 data")
 	}
 	if it[*i+6] != '\\' || it[*i+7] != 'u' {
-		return fmt.Errorf("expect unicode escape character at position %d but not", *i+6)
+		return newSyntaxError(ErrIllegalString, *i+6, "UTF-16 low surrogate", "expect unicode escape character but not")
 	}
 
 	ex3 := chrToHex(it[*i+8], &err)
@@ -506,21 +622,21 @@ func (it iter) handleEscapeUnicodeStartWithEnd(i *int, end int, sectEnd *int) (e
 	ex1 := chrToHex(it[*i+10], &err)
 	ex0 := chrToHex(it[*i+11], &err)
 	if err != nil {
-		return
+		return newSyntaxError(ErrIllegalString, *i+6, "4 hexadecimal digits", "%v", err)
 	}
 
 	ex := (rune(ex3) << 12) + (rune(ex2) << 8) + (rune(ex1) << 4) + rune(ex0)
 	if ex < 0xDC00 {
-		return fmt.Errorf(
-			"%w, expect second UTF-16 encoding but got 0x04%X at position %d",
-			ErrIllegalString, r, *i+8,
+		return newSyntaxError(
+			ErrIllegalString, *i+6, "UTF-16 low surrogate",
+			"expect second UTF-16 encoding but got 0x%04X", ex,
 		)
 	}
 	ex -= 0xDC00
 	if ex > 0x03FF {
-		return fmt.Errorf(
-			"%w, expect second UTF-16 encoding but got 0x04%X at position %d",
-			ErrIllegalString, r, *i+8,
+		return newSyntaxError(
+			ErrIllegalString, *i+6, "UTF-16 low surrogate",
+			"expect second UTF-16 encoding but got 0x%04X", ex+0xDC00,
 		)
 	}
 
@@ -604,7 +720,7 @@ func runeIdentifyingBytes4(chr byte) bool {
 
 func (it iter) parseTrue(offset int) (end int, err error) {
 	if len(it)-offset < 4 {
-		return -1, fmt.Errorf("%w, insufficient character from Position %d", ErrNotValidBoolValue, offset)
+		return -1, newSyntaxError(ErrNotValidBoolValue, offset, "'true'", "insufficient character")
 	}
 
 	if it[offset] == 't' &&
@@ -614,12 +730,12 @@ func (it iter) parseTrue(offset int) (end int, err error) {
 		return offset + 4, nil
 	}
 
-	return -1, fmt.Errorf("%w, not 'true' at Position %d", ErrNotValidBoolValue, offset)
+	return -1, newSyntaxError(ErrNotValidBoolValue, offset, "'true'", "not 'true'")
 }
 
 func (it iter) parseFalse(offset int) (end int, err error) {
 	if len(it)-offset < 5 {
-		return -1, fmt.Errorf("%w, insufficient character from Position %d", ErrNotValidBoolValue, offset)
+		return -1, newSyntaxError(ErrNotValidBoolValue, offset, "'false'", "insufficient character")
 	}
 
 	if it[offset] == 'f' &&
@@ -630,12 +746,12 @@ func (it iter) parseFalse(offset int) (end int, err error) {
 		return offset + 5, nil
 	}
 
-	return -1, fmt.Errorf("%w, not 'false' at Position %d", ErrNotValidBoolValue, offset)
+	return -1, newSyntaxError(ErrNotValidBoolValue, offset, "'false'", "not 'false'")
 }
 
 func (it iter) parseNull(offset int) (end int, err error) {
 	if len(it)-offset < 4 {
-		return -1, fmt.Errorf("%w, insufficient character from Position %d", ErrNotValidNullValue, offset)
+		return -1, newSyntaxError(ErrNotValidNullValue, offset, "'null'", "insufficient character")
 	}
 
 	if it[offset] == 'n' &&
@@ -645,7 +761,7 @@ func (it iter) parseNull(offset int) (end int, err error) {
 		return offset + 4, nil
 	}
 
-	return -1, fmt.Errorf("%w, not 'null' at Position %d", ErrNotValidBoolValue, offset)
+	return -1, newSyntaxError(ErrNotValidNullValue, offset, "'null'", "not 'null'")
 }
 
 // skipBlanks skip blank characters until end or reaching a non-blank character
@@ -678,13 +794,29 @@ func (it iter) parseNumber(
 	p pool, offset int,
 ) (v *V, end int, reachEnd bool, err error) {
 
+	end, floated, negative, integer, err := it.scanNumber(offset)
+	if err != nil {
+		return
+	}
+
+	if floated {
+		v, err = it.parseFloatResult(p, offset, end)
+	} else if negative {
+		v, err = it.parseNegativeIntResult(p, offset, end, integer)
+	} else {
+		v, err = it.parsePositiveIntResult(p, offset, end, integer)
+	}
+
+	return v, end, len(it)-end == 0, err
+}
+
+// scanNumber finds the end of a number and checks its format without creating
+// any value.
+func (it iter) scanNumber(offset int) (end int, floated, negative bool, integer uint64, err error) {
 	idx := offset
-	negative := false
-	floated := false
 	exponentGot := false
 	dotGot := false
 	intAfterDotGot := false
-	integer := uint64(0)
 	edgeFound := false
 
 	// len(it)-idx means remain bytes
@@ -768,7 +900,6 @@ func (it iter) parseNumber(
 			err = it.numErrorf(offset, "integer after dot missing")
 			return
 		}
-		v, err = it.parseFloatResult(p, offset, idx)
 	} else {
 		if integer > 0 && it[offset] == '0' {
 			err = it.numErrorf(offset, "non-zero integer should not start with zero")
@@ -784,20 +915,13 @@ func (it iter) parseNumber(
 				return
 			}
 		}
-
-		if negative {
-			v, err = it.parseNegativeIntResult(p, offset, idx, integer)
-		} else {
-			v, err = it.parsePositiveIntResult(p, offset, idx, integer)
-		}
 	}
 
-	return v, idx, len(it)-idx == 0, err
+	return idx, floated, negative, integer, nil
 }
 
 func (it iter) numErrorf(offset int, f string, a ...any) error {
-	a = append([]any{offset}, a...)
-	return fmt.Errorf("parsing number at index %d: "+f, a...)
+	return newSyntaxError(ErrNotValidNumberValue, offset, "", "parsing number: "+f, a...)
 
 	// debug ONLY below
 
@@ -832,7 +956,7 @@ const (
 func (it iter) parseFloatResult(p pool, start, end int) (*V, error) {
 	f, err := strconv.ParseFloat(unsafe.BtoS(it[start:end]), 64)
 	if err != nil {
-		return nil, it.numErrorf(start, "%w", err)
+		return nil, it.numErrorf(start, "%v", err)
 	}
 
 	v := new(p, Number)
@@ -848,14 +972,8 @@ func (it iter) parseFloatResult(p pool, start, end int) (*V, error) {
 }
 
 func (it iter) parsePositiveIntResult(p pool, start, end int, integer uint64) (*V, error) {
-	le := end - start
-
-	if le > len(uintMaxStr) {
-		return nil, it.numErrorf(start, "value too large")
-	} else if le == len(uintMaxStr) {
-		if integer < uintMaxDigits {
-			return nil, it.numErrorf(start, "value too large")
-		}
+	if positiveIntOverflows(start, end, integer) {
+		return it.parseBigIntResult(p, start, end, false), nil
 	}
 
 	v := new(p, Number)
@@ -871,14 +989,8 @@ func (it iter) parsePositiveIntResult(p pool, start, end int, integer uint64) (*
 }
 
 func (it iter) parseNegativeIntResult(p pool, start, end int, integer uint64) (*V, error) {
-	le := end - start
-
-	if le > len(intMinStr) {
-		return nil, it.numErrorf(start, "absolute value too large")
-	} else if le == len(intMinStr) {
-		if integer > intMinAbs {
-			return nil, it.numErrorf(start, "absolute value too large")
-		}
+	if negativeIntOverflows(start, end, integer) {
+		return it.parseBigIntResult(p, start, end, true), nil
 	}
 
 	v := new(p, Number)
@@ -898,3 +1010,43 @@ func (it iter) parseNegativeIntResult(p pool, start, end int, integer uint64) (*
 
 	return v, nil
 }
+
+// parseBigIntResult parses an integer beyond the range of int64 and uint64. Its
+// text is kept exactly, while i64 and u64 are saturated.
+func (it iter) parseBigIntResult(p pool, start, end int, negative bool) *V {
+	v := new(p, Number)
+	v.srcByte = it[start:end]
+
+	v.num.negative = negative
+	v.num.floated = false
+	v.num.f64, _ = strconv.ParseFloat(unsafe.BtoS(v.srcByte), 64) // ±Inf if too large
+	saturateNum(v)
+
+	return v
+}
+
+// positiveIntOverflows tells whether a positive integer it[start:end] is beyond
+// the range of uint64, in which case integer is not exact.
+func positiveIntOverflows(start, end int, integer uint64) bool {
+	le := end - start
+
+	if le > len(uintMaxStr) {
+		return true
+	} else if le == len(uintMaxStr) {
+		return integer < uintMaxDigits
+	}
+	return false
+}
+
+// negativeIntOverflows tells whether a negative integer it[start:end] is beyond
+// the range of int64.
+func negativeIntOverflows(start, end int, integer uint64) bool {
+	le := end - start
+
+	if le > len(intMinStr) {
+		return true
+	} else if le == len(intMinStr) {
+		return integer > intMinAbs
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package jsonvalue
 
 import (
 	"encoding/hex"
+	"math"
 	"testing"
 )
 
@@ -121,13 +122,24 @@ func testIterFloat(t *testing.T) {
 
 func testUnmarshalFloatErrors(*testing.T) {
 	cv("overflow", func() {
-		_, err := UnmarshalString(`-9223372036854775809`)
-		so(err, isErr)
-		_, err = UnmarshalString(`18446744073709551616`)
-		so(err, isErr)
-		_, err = UnmarshalString(`-9999999999999999999`)
-		so(err, isErr)
-		_, err = UnmarshalString(`9999999999999999999999999999999999999999999999999999999999999999999`)
+		// integers beyond int64 and uint64 are saturated
+		v, err := UnmarshalString(`-9223372036854775809`)
+		so(err, isNil)
+		so(v.Int64(), eq, int64(math.MinInt64))
+		so(v.MustMarshalString(), eq, `-9223372036854775809`)
+		v, err = UnmarshalString(`18446744073709551616`)
+		so(err, isNil)
+		so(v.Uint64(), eq, uint64(math.MaxUint64))
+		so(v.MustMarshalString(), eq, `18446744073709551616`)
+		v, err = UnmarshalString(`-9999999999999999999`)
+		so(err, isNil)
+		so(v.Int64(), eq, int64(math.MinInt64))
+		v, err = UnmarshalString(`9999999999999999999999999999999999999999999999999999999999999999999`)
+		so(err, isNil)
+		so(v.Int64(), eq, int64(math.MaxInt64))
+
+		// floats beyond float64 are not supported
+		_, err = UnmarshalString(`1e400`)
 		so(err, isErr)
 		_, err = UnmarshalString(`99999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999.9999999999999999999999999999999999999999999999999999999999999999999`)
 		so(err, isErr)
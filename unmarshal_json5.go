@@ -0,0 +1,485 @@
+package jsonvalue
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Andrew-M-C/go.jsonvalue/internal/unsafe"
+)
+
+// ================ JSON5 / JSONC ================
+
+// json5Options holds extensions of relaxed parsing. Each of them could be
+// enabled separately.
+type json5Options struct {
+	comments           bool
+	trailingCommas     bool
+	unquotedKeys       bool
+	singleQuotes       bool
+	hexNumbers         bool
+	extendedNumbers    bool
+	extendedEscapes    bool
+	extendedWhitespace bool
+}
+
+func (o json5Options) relaxedBlanks() bool {
+	return o.comments || o.extendedWhitespace
+}
+
+func (o json5Options) relaxedStrings() bool {
+	return o.singleQuotes || o.extendedEscapes
+}
+
+func (o json5Options) relaxedNumbers() bool {
+	return o.hexNumbers || o.extendedNumbers
+}
+
+// UnmarshalOptAllowComments allows single line comments // ... and block
+// comments /* ... */ wherever blank characters are allowed.
+//
+// UnmarshalOptAllowComments 允许在可以出现空白字符的地方使用单行注释 // ... 和块注释 /* ... */。
+func UnmarshalOptAllowComments() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.comments = true }}
+}
+
+// UnmarshalOptAllowTrailingCommas allows one trailing comma after the last
+// member of arrays and objects, such as [1,2,] or {"a":1,}. It only matters
+// with UnmarshalOptStrictSeparators, because commas are loosely handled by
+// default.
+//
+// UnmarshalOptAllowTrailingCommas 允许 array 和 object 的最后一个成员之后有一个逗号, 比如
+// [1,2,] 或 {"a":1,}。由于默认情况下对逗号的处理是宽松的, 因此该选项仅在搭配
+// UnmarshalOptStrictSeparators 时才有意义。
+func UnmarshalOptAllowTrailingCommas() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.trailingCommas = true }}
+}
+
+// UnmarshalOptAllowUnquotedKeys allows object keys without quotes, such as
+// {key: 1}. Unquoted keys should be identifiers, which start with a letter, '_'
+// or '$', followed by letters, digits, '_' or '$'. Non-ASCII characters are
+// treated as letters.
+//
+// UnmarshalOptAllowUnquotedKeys 允许 object 的键不带引号, 比如 {key: 1}。不带引号的键必须是
+// 标识符, 即以字母, '_' 或 '$' 开头, 后跟字母, 数字, '_' 或 '$'。非 ASCII 字符视为字母。
+func UnmarshalOptAllowUnquotedKeys() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.unquotedKeys = true }}
+}
+
+// UnmarshalOptAllowSingleQuotes allows strings and object keys quoted by single
+// quotes, such as 'Hello, "world"'.
+//
+// UnmarshalOptAllowSingleQuotes 允许使用单引号包裹字符串和 object 的键, 比如
+// 'Hello, "world"'。
+func UnmarshalOptAllowSingleQuotes() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.singleQuotes = true }}
+}
+
+// UnmarshalOptAllowHexNumbers allows hexadecimal integers, such as 0xFF or
+// -0x1f. They are converted to decimal integers.
+//
+// UnmarshalOptAllowHexNumbers 允许十六进制整数, 比如 0xFF 或 -0x1f。它们会被转换为十进制整数。
+func UnmarshalOptAllowHexNumbers() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.hexNumbers = true }}
+}
+
+// UnmarshalOptAllowExtendedNumbers allows leading or trailing decimal points
+// such as .5 and 5., an explicit plus sign such as +1, and Infinity, -Infinity
+// and NaN. Numbers are normalized to standard JSON text, while marshaling
+// Infinity and NaN follows OptFloatInfToXxx and OptFloatNaNToXxx options.
+//
+// UnmarshalOptAllowExtendedNumbers 允许以小数点开头或结尾的数字如 .5 和 5., 显式的正号如
+// +1, 以及 Infinity, -Infinity 和 NaN。数字会被规范化为标准的 JSON 文本, 而 Infinity 和 NaN
+// 的序列化则遵循 OptFloatInfToXxx 和 OptFloatNaNToXxx 选项。
+func UnmarshalOptAllowExtendedNumbers() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.extendedNumbers = true }}
+}
+
+// UnmarshalOptAllowExtendedEscapes allows escapes in JSON5 strings, including
+// \v, \0, \xHH, escaped line terminators for multi-line strings, and any other
+// escaped character which represents itself, such as \a for 'a'.
+//
+// UnmarshalOptAllowExtendedEscapes 允许 JSON5 字符串中的转义, 包括 \v, \0, \xHH, 用于多行
+// 字符串的转义换行符, 以及其他表示其自身的转义字符, 比如 \a 表示 'a'。
+func UnmarshalOptAllowExtendedEscapes() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.extendedEscapes = true }}
+}
+
+// UnmarshalOptAllowExtendedWhitespace allows \v, \f, byte order mark (BOM),
+// U+2028, U+2029 and all Unicode space separators as blank characters.
+//
+// UnmarshalOptAllowExtendedWhitespace 允许将 \v, \f, 字节顺序标记 (BOM), U+2028, U+2029
+// 以及所有 Unicode 空格分隔符作为空白字符。
+func UnmarshalOptAllowExtendedWhitespace() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) { o.extendedWhitespace = true }}
+}
+
+// UnmarshalOptJSON5 enables all JSON5 (https://spec.json5.org/) extensions. The
+// parsed value is a normal *V, which marshals as standard JSON.
+//
+// UnmarshalOptJSON5 启用所有的 JSON5 (https://spec.json5.org/) 扩展。解析得到的是普通的 *V,
+// 其序列化结果为标准的 JSON。
+func UnmarshalOptJSON5() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) {
+		*o = json5Options{
+			comments:           true,
+			trailingCommas:     true,
+			unquotedKeys:       true,
+			singleQuotes:       true,
+			hexNumbers:         true,
+			extendedNumbers:    true,
+			extendedEscapes:    true,
+			extendedWhitespace: true,
+		}
+	}}
+}
+
+// UnmarshalOptJSONC enables comments and trailing commas, which is known as
+// "JSON with comments" and used by many configuration files.
+//
+// UnmarshalOptJSONC 启用注释和末尾逗号, 也就是很多配置文件使用的 "JSON with comments" 格式。
+func UnmarshalOptJSONC() UnmarshalOption {
+	return unmarshalOptJSON5{func(o *json5Options) {
+		o.comments = true
+		o.trailingCommas = true
+	}}
+}
+
+type unmarshalOptJSON5 struct {
+	set func(*json5Options)
+}
+
+func (o unmarshalOptJSON5) mergeToUnmarshalOpt(opt *unmarshalOptions) {
+	o.set(&opt.json5)
+}
+
+// skipBlanks is similar to iter.skipBlanks, but also skips comments and
+// extended whitespace if enabled. An unterminated block comment is left
+// unskipped, so that the caller reports '/' as an invalid character.
+func (opt *unmarshalOptions) skipBlanks(it iter, offset, end int) (newOffset int, reachEnd bool) {
+	if opt == nil || !opt.json5.relaxedBlanks() {
+		return it.skipBlanks(offset, end)
+	}
+	return opt.skipRelaxedBlanks(it, offset, end)
+}
+
+func (opt *unmarshalOptions) skipRelaxedBlanks(it iter, offset, end int) (newOffset int, reachEnd bool) {
+	for offset < end {
+		chr := it[offset]
+		switch {
+		case chr == ' ' || chr == '\r' || chr == '\n' || chr == '\t' || chr == '\b':
+			offset++
+		case chr == '/' && opt.json5.comments:
+			next := skipComment(it, offset, end)
+			if next == offset {
+				return offset, false
+			}
+			offset = next
+		case opt.json5.extendedWhitespace:
+			if chr == '\v' || chr == '\f' {
+				offset++
+				continue
+			}
+			if chr < utf8.RuneSelf {
+				return offset, false
+			}
+			r, size := utf8.DecodeRune(it[offset:end])
+			if r != '\u00a0' && r != '\u2028' && r != '\u2029' && r != '\ufeff' && !unicode.Is(unicode.Zs, r) {
+				return offset, false
+			}
+			offset += size
+		default:
+			return offset, false
+		}
+	}
+
+	return end, true
+}
+
+// skipComment returns offset after the comment starting at it[offset]. If it
+// is not a complete comment, offset is returned.
+func skipComment(it iter, offset, end int) int {
+	if end-offset < 2 {
+		return offset
+	}
+	switch it[offset+1] {
+	case '/':
+		for i := offset + 2; i < end; i++ {
+			if it[i] == '\n' || it[i] == '\r' {
+				return i + 1
+			}
+		}
+		return end
+	case '*':
+		for i := offset + 2; i < end-1; i++ {
+			if it[i] == '*' && it[i+1] == '/' {
+				return i + 2
+			}
+		}
+	}
+	return offset
+}
+
+// parseString parses a string or a quoted key, where it[offset] is the quote.
+func (opt *unmarshalOptions) parseString(it iter, offset int) (sectLenWithoutQuote int, sectEnd int, err error) {
+	if opt == nil || !opt.json5.relaxedStrings() {
+		return it.parseStrFromBytesForwardWithQuote(offset)
+	}
+	return opt.parseRelaxedString(it, offset)
+}
+
+func (opt *unmarshalOptions) parseRelaxedString(it iter, offset int) (sectLenWithoutQuote int, sectEnd int, err error) {
+	if it[offset] == '\'' && !opt.json5.singleQuotes {
+		return -1, -1, newSyntaxError(ErrRawBytesUnrecognized, offset, "value", "invalid character \\u%04X", it[offset])
+	}
+	return it.parseRelaxedStrFromBytesForwardWithQuote(offset, opt.json5.extendedEscapes)
+}
+
+// parseRelaxedStrFromBytesForwardWithQuote is similar to
+// parseStrFromBytesForwardWithQuote, but it[offset] could be either a double
+// quote or a single quote.
+func (it iter) parseRelaxedStrFromBytesForwardWithQuote(offset int, extendedEscapes bool) (sectLenWithoutQuote int, sectEnd int, err error) {
+	quote := it[offset]
+	offset++ // skip quote
+	end := len(it)
+	sectEnd = offset
+
+	for i := offset; i < end; {
+		chr := it[i]
+
+		switch {
+		case chr == quote:
+			return sectEnd - offset, i + 1, nil
+		case chr == '\\' && extendedEscapes:
+			err = it.handleExtendedEscapeStart(&i, &sectEnd)
+		case chr == '\\':
+			err = it.handleEscapeStart(&i, &sectEnd)
+		case chr <= 0x7F:
+			it[sectEnd] = chr
+			i++
+			sectEnd++
+		default:
+			le := 0
+			switch {
+			case runeIdentifyingBytes2(chr):
+				le = 2
+			case runeIdentifyingBytes3(chr):
+				le = 3
+			case runeIdentifyingBytes4(chr):
+				le = 4
+			default:
+				return -1, -1, newSyntaxError(ErrIllegalString, i, "", "illegal UTF8 string")
+			}
+			if end-i < le {
+				return -1, -1, newSyntaxError(
+					ErrIllegalString, i, "",
+					"expect at least %d remaining bytes, but got %d", le, end-i,
+				)
+			}
+			it.memcpy(sectEnd, i, le)
+			sectEnd += le
+			i += le
+		}
+		if err != nil {
+			return -1, -1, err
+		}
+	}
+
+	err = newSyntaxError(ErrIllegalString, offset-1, "'"+string(quote)+"'", "ending quote of a string is not found")
+	return
+}
+
+func (it iter) handleExtendedEscapeStart(i *int, sectEnd *int) error {
+	if len(it)-1-*i < 1 {
+		return newSyntaxError(ErrIllegalString, *i, "escaped character", "escape symbol not followed by another character")
+	}
+
+	chr := it[*i+1]
+	switch chr {
+	case 'b', 'f', 'r', 'n', 't', 'u':
+		return it.handleEscapeStart(i, sectEnd)
+	case 'v':
+		it[*sectEnd] = '\v'
+	case '0':
+		if len(it)-*i > 2 && it[*i+2] >= '0' && it[*i+2] <= '9' {
+			return newSyntaxError(ErrIllegalString, *i, "", "octal escape is not allowed")
+		}
+		it[*sectEnd] = 0
+	case 'x':
+		if len(it)-*i < 4 {
+			return newSyntaxError(ErrIllegalString, *i, "2 hexadecimal digits", "insufficient hex escaping characters")
+		}
+		var err error
+		h := chrToHex(it[*i+2], &err)<<4 + chrToHex(it[*i+3], &err)
+		if err != nil {
+			return newSyntaxError(ErrIllegalString, *i, "2 hexadecimal digits", "%v", err)
+		}
+		*sectEnd += it.assignASCIICodedRune(*sectEnd, rune(h))
+		*i += 4
+		return nil
+	case '\n':
+		*i += 2
+		return nil
+	case '\r':
+		*i += 2
+		if *i < len(it) && it[*i] == '\n' {
+			*i++
+		}
+		return nil
+	default:
+		if chr >= '1' && chr <= '9' {
+			return newSyntaxError(ErrIllegalString, *i, "", "decimal digit escape is not allowed")
+		}
+		if chr >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(it[*i+1:])
+			*i++
+			if r == '\u2028' || r == '\u2029' {
+				*i += size // line continuation
+			}
+			// otherwise, the character is copied as it is in next loop
+			return nil
+		}
+		// identity escape
+		it[*sectEnd] = chr
+	}
+	*sectEnd++
+	*i += 2
+	return nil
+}
+
+// parseUnquotedKey parses an identifier key starting at it[offset].
+func (it iter) parseUnquotedKey(offset, end int) (keyEnd int) {
+	for keyEnd = offset; keyEnd < end && isIdentifierPart(it[keyEnd]); keyEnd++ {
+		// continue
+	}
+	return keyEnd
+}
+
+func isIdentifierStart(chr byte) bool {
+	return chr == '_' || chr == '$' || (chr|0x20 >= 'a' && chr|0x20 <= 'z') || chr >= utf8.RuneSelf
+}
+
+func isIdentifierPart(chr byte) bool {
+	return isIdentifierStart(chr) || (chr >= '0' && chr <= '9')
+}
+
+// tokenKind maps relaxed tokens to standard ones, so that the parser could
+// handle them in the same way. For example, a single quote is mapped to a
+// double quote if single quotes are allowed.
+func (opt *unmarshalOptions) tokenKind(chr byte) byte {
+	if opt == nil {
+		return chr
+	}
+	switch {
+	case chr == '\'' && opt.json5.singleQuotes:
+		return '"'
+	case opt.json5.extendedNumbers && opt.isNumberStart(chr):
+		return '0'
+	default:
+		return chr
+	}
+}
+
+// isNumberStart tells whether chr starts a number with given options.
+func (opt *unmarshalOptions) isNumberStart(chr byte) bool {
+	switch chr {
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-':
+		return true
+	case '+', '.', 'I', 'N':
+		return opt.json5.extendedNumbers
+	default:
+		return false
+	}
+}
+
+// parseNumber is similar to iter.parseNumber, but also accepts JSON5 numbers if
+// enabled, which are converted to standard JSON text.
+func (opt *unmarshalOptions) parseNumber(p pool, it iter, offset int) (v *V, end int, reachEnd bool, err error) {
+	if opt == nil || !opt.json5.relaxedNumbers() {
+		return it.parseNumber(p, offset)
+	}
+	return opt.parseRelaxedNumber(p, it, offset)
+}
+
+func (opt *unmarshalOptions) parseRelaxedNumber(p pool, it iter, offset int) (v *V, end int, reachEnd bool, err error) {
+	end = offset
+	for ; end < len(it) && isRelaxedNumberChar(it[end]); end++ {
+		// continue
+	}
+	token := unsafe.BtoS(it[offset:end])
+	reachEnd = end == len(it)
+
+	s, negative := token, false
+	if s != "" && (s[0] == '-' || (s[0] == '+' && opt.json5.extendedNumbers)) {
+		s, negative = s[1:], s[0] == '-'
+	}
+
+	text := ""
+	switch {
+	case opt.json5.extendedNumbers && (s == "Infinity" || s == "NaN"):
+		f := math.NaN()
+		if s == "Infinity" {
+			f = math.Inf(1)
+			if negative {
+				f = math.Inf(-1)
+			}
+		}
+		return newFloat64f(p, f, 'g', -1, 64), end, reachEnd, nil
+
+	case opt.json5.hexNumbers && len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X'):
+		if u, err := strconv.ParseUint(s[2:], 16, 64); err == nil {
+			text = strconv.FormatUint(u, 10)
+		} else if b, ok := (&big.Int{}).SetString(s[2:], 16); ok && b.Sign() >= 0 && s[2] != '+' && s[2] != '-' {
+			text = b.String() // beyond uint64
+		} else {
+			return nil, -1, false, it.numErrorf(offset, "invalid hexadecimal number %s", token)
+		}
+
+	case opt.json5.extendedNumbers:
+		text = normalizeExtendedNumber(s)
+
+	default:
+		text = s
+	}
+	if text == "" || text[0] < '0' || text[0] > '9' {
+		return nil, -1, false, it.numErrorf(offset, "invalid number %s", token)
+	}
+	if negative {
+		text = "-" + text
+	}
+	b := iter(text)
+	v, parsedEnd, _, err := b.parseNumber(p, 0)
+	if err != nil || parsedEnd != len(b) {
+		return nil, -1, false, it.numErrorf(offset, "invalid number %s", token)
+	}
+	return v, end, reachEnd, nil
+}
+
+func isRelaxedNumberChar(chr byte) bool {
+	return (chr >= '0' && chr <= '9') || (chr|0x20 >= 'a' && chr|0x20 <= 'z') ||
+		chr == '.' || chr == '+' || chr == '-'
+}
+
+// normalizeExtendedNumber converts numbers like .5, 5. and 5.e3 to standard
+// JSON form. A dot without any adjacent digit is left unchanged, which is then
+// rejected by the parser.
+func normalizeExtendedNumber(s string) string {
+	if len(s) > 1 && s[0] == '.' && s[1] >= '0' && s[1] <= '9' {
+		s = "0" + s
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '.' {
+			continue
+		}
+		if i == len(s)-1 {
+			return s[:i]
+		}
+		if s[i+1] == 'e' || s[i+1] == 'E' {
+			return s[:i] + s[i+1:]
+		}
+		break
+	}
+	return s
+}
@@ -0,0 +1,242 @@
+package jsonvalue
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func testUnmarshalJSON5(t *testing.T) {
+	cv("disabled by default", func() { testJSON5DisabledByDefault(t) })
+	cv("comments", func() { testJSON5Comments(t) })
+	cv("trailing commas", func() { testJSON5TrailingCommas(t) })
+	cv("unquoted keys", func() { testJSON5UnquotedKeys(t) })
+	cv("single quotes", func() { testJSON5SingleQuotes(t) })
+	cv("numbers", func() { testJSON5Numbers(t) })
+	cv("escapes and whitespace", func() { testJSON5EscapesAndWhitespace(t) })
+	cv("full document", func() { testJSON5Document(t) })
+}
+
+func testJSON5DisabledByDefault(t *testing.T) {
+	cases := []string{
+		`[1, /* c */ 2]`, "[1, // c\n 2]", `{a:1}`, `['a']`, `{'a':1}`, `[0x10]`,
+		`[.5]`, `[+1]`, `[Infinity]`, `[NaN]`, `["\x41"]`, "[\v1]",
+	}
+	for _, c := range cases {
+		_, err := UnmarshalString(c)
+		so(err, isErr)
+		_, err = UnmarshalStringWithOptions(c)
+		so(err, isErr)
+	}
+
+	// an extension does not enable others
+	_, err := UnmarshalStringWithOptions(`{a:1}`, UnmarshalOptAllowComments())
+	so(err, isErr)
+	_, err = UnmarshalStringWithOptions(`['a']`, UnmarshalOptAllowExtendedEscapes())
+	so(err, isErr)
+	_, err = UnmarshalStringWithOptions(`[.5]`, UnmarshalOptAllowHexNumbers())
+	so(err, isErr)
+	_, err = UnmarshalStringWithOptions(`[0x10]`, UnmarshalOptAllowExtendedNumbers())
+	so(err, isErr)
+}
+
+func testJSON5Comments(t *testing.T) {
+	opt := UnmarshalOptAllowComments()
+
+	raw := "// leading\n/* block\n comment */{\n" +
+		"  \"a\": 1, // line comment\n" +
+		"  \"b\" /* before colon */ : /* after colon */ [1, /**/ 2 /***/],\n" +
+		"  \"c\": \"// not a comment /* */\"\r\n" +
+		"} // trailing"
+	v, err := UnmarshalStringWithOptions(raw, opt)
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence(), OptEscapeSlash(false)), eq, `{"a":1,"b":[1,2],"c":"// not a comment /* */"}`)
+
+	v, err = UnmarshalStringWithOptions("1// comment at end without new line", opt)
+	so(err, isNil)
+	so(v.Int(), eq, 1)
+
+	invalid := []string{`[1 /* unterminated ]`, `[1] /* unterminated`, `[1, / 2]`, `/`, `/* only comment */`}
+	for _, s := range invalid {
+		_, err := UnmarshalStringWithOptions(s, opt)
+		so(err, isErr)
+	}
+}
+
+func testJSON5TrailingCommas(t *testing.T) {
+	opts := []UnmarshalOption{UnmarshalOptStrictSeparators(), UnmarshalOptAllowTrailingCommas()}
+
+	v, err := UnmarshalStringWithOptions(`{"a":[1,2,],"b":{"c":true,},}`, opts...)
+	so(err, isNil)
+	so(v.MustGet("a").Len(), eq, 2)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":[1,2],"b":{"c":true}}`)
+
+	invalid := []string{`[1,,]`, `[,]`, `{,}`, `{"a":1,,}`, `[1 2,]`}
+	for _, s := range invalid {
+		_, err := UnmarshalStringWithOptions(s, opts...)
+		so(err, isErr)
+	}
+
+	v, err = UnmarshalStringWithOptions("[1,// comment\n]", UnmarshalOptJSONC(), UnmarshalOptStrictSeparators())
+	so(err, isNil)
+	so(v.Len(), eq, 1)
+}
+
+func testJSON5UnquotedKeys(t *testing.T) {
+	opt := UnmarshalOptAllowUnquotedKeys()
+
+	v, err := UnmarshalStringWithOptions(`{a:1, _b2 : "x", $c:null, 中文:true, null:{true:false}}`, opt)
+	so(err, isNil)
+	so(v.MustGet("a").Int(), eq, 1)
+	so(v.MustGet("_b2").String(), eq, "x")
+	so(v.MustGet("$c").IsNull(), isTrue)
+	so(v.MustGet("中文").Bool(), isTrue)
+	so(v.MustGet("null", "true").Bool(), isFalse)
+
+	invalid := []string{`{1a:1}`, `{a b:1}`, `{a}`, `{a:}`, `{-a:1}`, `{a:b}`}
+	for _, s := range invalid {
+		_, err := UnmarshalStringWithOptions(s, opt)
+		so(err, isErr)
+	}
+
+	_, err = UnmarshalStringWithOptions(`{a:1 b:2}`, opt, UnmarshalOptStrictSeparators())
+	so(err, isErr)
+	_, err = UnmarshalStringWithOptions(`{a:1, a:2}`, opt, UnmarshalOptRejectDuplicateKeys())
+	so(errors.Is(err, ErrDuplicateKey), isTrue)
+	_, err = UnmarshalStringWithOptions(`{abcd:1}`, opt, UnmarshalOptMaxStringLength(3))
+	so(errors.Is(err, ErrStringLengthLimitExceeded), isTrue)
+}
+
+func testJSON5SingleQuotes(t *testing.T) {
+	opt := UnmarshalOptAllowSingleQuotes()
+
+	v, err := UnmarshalStringWithOptions(`{'a':'He said "hi"', "b":'it\'s', 'c':["x",'中']}`, opt)
+	so(err, isNil)
+	so(v.MustGet("a").String(), eq, `He said "hi"`)
+	so(v.MustGet("b").String(), eq, `it's`)
+	so(v.MustGet("c", 1).String(), eq, "中")
+	so(v.MustGet("a").MustMarshalString(), eq, `"He said \"hi\""`)
+
+	v, err = UnmarshalStringWithOptions(`'top'`, opt)
+	so(err, isNil)
+	so(v.String(), eq, "top")
+
+	_, err = UnmarshalStringWithOptions(`['unterminated"]`, opt)
+	so(errors.Is(err, ErrIllegalString), isTrue)
+	_, err = UnmarshalStringWithOptions(`["mixed']`, opt)
+	so(errors.Is(err, ErrIllegalString), isTrue)
+}
+
+func testJSON5Numbers(t *testing.T) {
+	cv("hexadecimal", func() {
+		opt := UnmarshalOptAllowHexNumbers()
+		v, err := UnmarshalStringWithOptions(`[0xFF, 0X1f, -0x10, 0x0, 0xFFFFFFFFFFFFFFFF]`, opt)
+		so(err, isNil)
+		so(v.MustMarshalString(), eq, `[255,31,-16,0,18446744073709551615]`)
+		so(v.MustGet(2).Int(), eq, -16)
+		so(v.MustGet(4).Uint64(), eq, uint64(math.MaxUint64))
+
+		// beyond uint64
+		v, err = UnmarshalStringWithOptions(`[0x10000000000000000, -0xFFFFFFFFFFFFFFFF]`, opt)
+		so(err, isNil)
+		so(v.MustMarshalString(), eq, `[18446744073709551616,-18446744073709551615]`)
+		so(v.MustGet(1).Int64(), eq, int64(math.MinInt64))
+
+		invalid := []string{`0x`, `0xG`, `0x1.5`, `0x_1`, `0x-1`, `00x1`}
+		for _, s := range invalid {
+			_, err := UnmarshalStringWithOptions(s, opt)
+			so(errors.Is(err, ErrNotValidNumberValue), isTrue)
+		}
+	})
+
+	cv("extended", func() {
+		opt := UnmarshalOptAllowExtendedNumbers()
+		v, err := UnmarshalStringWithOptions(`[.5, -.5, 5., +1, +1.5e3, 5.e2, 1.0]`, opt)
+		so(err, isNil)
+		so(v.MustMarshalString(), eq, `[0.5,-0.5,5,1,1.5e3,5e2,1.0]`)
+		so(v.MustGet(4).Float64(), eq, 1500)
+
+		v, err = UnmarshalStringWithOptions(`{"a":Infinity,"b":-Infinity,"c":NaN,"d":+Infinity}`, opt)
+		so(err, isNil)
+		so(math.IsInf(v.MustGet("a").Float64(), 1), isTrue)
+		so(math.IsInf(v.MustGet("b").Float64(), -1), isTrue)
+		so(math.IsNaN(v.MustGet("c").Float64()), isTrue)
+		so(math.IsInf(v.MustGet("d").Float64(), 1), isTrue)
+		s := v.MustMarshalString(OptDefaultStringSequence(), OptFloatNaNToNull(), OptFloatInfToNull())
+		so(s, eq, `{"a":null,"b":null,"c":null,"d":null}`)
+
+		invalid := []string{`.`, `+`, `+-1`, `1..2`, `..5`, `.e1`, `Inf`, `nan`, `--1`, `01`}
+		for _, s := range invalid {
+			_, err := UnmarshalStringWithOptions(s, opt)
+			so(err, isErr)
+		}
+	})
+}
+
+func testJSON5EscapesAndWhitespace(t *testing.T) {
+	cv("escapes", func() {
+		opt := UnmarshalOptAllowExtendedEscapes()
+		v, err := UnmarshalStringWithOptions(`"\x41\v\0\a\q\"\n"`, opt)
+		so(err, isNil)
+		so(v.String(), eq, "A\v\x00aq\"\n")
+
+		v, err = UnmarshalStringWithOptions("\"line 1 \\\nline 2 \\\r\nline 3 \\ end\\中\"", opt)
+		so(err, isNil)
+		so(v.String(), eq, "line 1 line 2 line 3 end中")
+
+		invalid := []string{`"\x4"`, `"\xZZ"`, `"\01"`, `"\1"`, `"\`}
+		for _, s := range invalid {
+			_, err := UnmarshalStringWithOptions(s, opt)
+			so(errors.Is(err, ErrIllegalString), isTrue)
+		}
+	})
+
+	cv("whitespace", func() {
+		opt := UnmarshalOptAllowExtendedWhitespace()
+		v, err := UnmarshalStringWithOptions("\ufeff[\v1,\f2,\u00a03,\u20284,\u20295,\u30006]", opt)
+		so(err, isNil)
+		so(v.MustMarshalString(), eq, `[1,2,3,4,5,6]`)
+
+		_, err = UnmarshalStringWithOptions("[1,\u200b2]", opt) // zero width space is not a space separator
+		so(err, isErr)
+	})
+}
+
+func testJSON5Document(t *testing.T) {
+	raw := `// server configuration
+{
+	name: 'demo',        // single quoted
+	port: 0x1F90,
+	ratio: .75,
+	retries: +3,
+	tags: ['a', "b",],
+	limits: {
+		max: Infinity,
+	},
+	motd: 'Welcome \
+to the server',
+	/* disabled: true, */
+}
+`
+	v, err := UnmarshalStringWithOptions(raw, UnmarshalOptJSON5(), UnmarshalOptStrict())
+	so(err, isNil)
+	so(v.MustGet("port").Int(), eq, 8080)
+	so(v.MustGet("ratio").Float64(), eq, 0.75)
+	so(v.MustGet("motd").String(), eq, "Welcome to the server")
+	so(math.IsInf(v.MustGet("limits", "max").Float64(), 1), isTrue)
+
+	v.MustDelete("limits")
+	s := v.MustMarshalString(OptDefaultStringSequence())
+	so(s, eq, `{"motd":"Welcome to the server","name":"demo","port":8080,"ratio":0.75,"retries":3,"tags":["a","b"]}`)
+
+	// marshaled text is standard JSON
+	_, err = UnmarshalStringWithOptions(s, UnmarshalOptStrict())
+	so(err, isNil)
+
+	// JSONC only enables comments and trailing commas
+	_, err = UnmarshalStringWithOptions(raw, UnmarshalOptJSONC())
+	so(err, isErr)
+	v, err = UnmarshalStringWithOptions("{\"a\": [1, 2,], // comment\n}", UnmarshalOptJSONC(), UnmarshalOptStrict())
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `{"a":[1,2]}`)
+}
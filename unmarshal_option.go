@@ -0,0 +1,500 @@
+package jsonvalue
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/Andrew-M-C/go.jsonvalue/internal/unsafe"
+)
+
+// ================ UNMARSHAL OPTIONS ================
+
+// UnmarshalOption is the option type for UnmarshalWithOptions and related
+// functions.
+//
+// UnmarshalOption 表示 UnmarshalWithOptions 及相关函数的选项。
+type UnmarshalOption interface {
+	mergeToUnmarshalOpt(opt *unmarshalOptions)
+}
+
+// unmarshalOptions holds options of one parsing. Its methods used by the parser
+// accept nil, which means no option and skips all checks.
+type unmarshalOptions struct {
+	rejectDuplicateKeys bool
+	rejectInvalidUTF8   bool
+	strictSeparators    bool
+
+	limits unmarshalLimits
+	json5  json5Options
+
+	// onNode is invoked for each parsed value if not nil
+	onNode func(v *V, span nodeSpan)
+	// positions records spans of parsed values if not nil
+	positions *Positions
+}
+
+// nodeSpan describes where a value is in the source text. For object members,
+// keyStart and keyEnd are the range of the key including quotes, otherwise they
+// are -1.
+type nodeSpan struct {
+	start, end       int
+	keyStart, keyEnd int
+}
+
+// unmarshalLimits holds resource limits for untrusted input. Zero means no
+// limit. Counters are states during one parsing, therefore unmarshalOptions
+// with limits is copied before each parsing.
+type unmarshalLimits struct {
+	enabled bool
+
+	maxDepth      int
+	maxBytes      int
+	maxStringLen  int
+	maxObjectKeys int
+	maxArrayLen   int
+	maxNodes      int
+
+	depth int
+	nodes int
+}
+
+// defaultUnmarshalOptions is used by Unmarshal and other functions without
+// options. It should never be modified.
+var defaultUnmarshalOptions = &unmarshalOptions{}
+
+func combineUnmarshalOptions(opts []UnmarshalOption) *unmarshalOptions {
+	if len(opts) == 0 {
+		return defaultUnmarshalOptions
+	}
+	opt := &unmarshalOptions{}
+	for _, o := range opts {
+		if o != nil {
+			o.mergeToUnmarshalOpt(opt)
+		}
+	}
+	return opt
+}
+
+// UnmarshalOptRejectDuplicateKeys rejects objects with duplicated keys, such as
+// {"a":1,"a":2}. By default, the last one wins.
+//
+// UnmarshalOptRejectDuplicateKeys 表示拒绝包含重复键的 object, 如 {"a":1,"a":2}。默认情况下,
+// 最后一个值会生效。
+func UnmarshalOptRejectDuplicateKeys() UnmarshalOption {
+	return unmarshalOptRejectDuplicateKeys{}
+}
+
+type unmarshalOptRejectDuplicateKeys struct{}
+
+func (unmarshalOptRejectDuplicateKeys) mergeToUnmarshalOpt(opt *unmarshalOptions) {
+	opt.rejectDuplicateKeys = true
+}
+
+// UnmarshalOptRejectInvalidUTF8 validates all strings and object keys strictly.
+// Overlong encodings, UTF-8 encoded surrogates, code points beyond U+10FFFF and
+// broken continuation bytes are rejected. By default, only leading bytes of
+// UTF-8 sequences are checked.
+//
+// UnmarshalOptRejectInvalidUTF8 表示严格校验所有的字符串和 object 的键。超长编码, 以 UTF-8
+// 编码的代理项, 超出 U+10FFFF 的码点以及不完整的后续字节均会被拒绝。默认情况下, 只会检查 UTF-8
+// 序列的首字节。
+func UnmarshalOptRejectInvalidUTF8() UnmarshalOption {
+	return unmarshalOptRejectInvalidUTF8{}
+}
+
+type unmarshalOptRejectInvalidUTF8 struct{}
+
+func (unmarshalOptRejectInvalidUTF8) mergeToUnmarshalOpt(opt *unmarshalOptions) {
+	opt.rejectInvalidUTF8 = true
+}
+
+// UnmarshalOptStrictSeparators requires exactly one comma between members of
+// arrays and objects. Missing commas such as [1 2], redundant commas such as
+// [1,,2] and trailing commas such as {"a":1,} are rejected. By default, commas
+// are loosely handled.
+//
+// UnmarshalOptStrictSeparators 要求 array 和 object 的成员之间有且仅有一个逗号。缺少逗号
+// (如 [1 2]), 多余的逗号 (如 [1,,2]) 以及末尾的逗号 (如 {"a":1,}) 均会被拒绝。默认情况下,
+// 对逗号的处理是宽松的。
+func UnmarshalOptStrictSeparators() UnmarshalOption {
+	return unmarshalOptStrictSeparators{}
+}
+
+type unmarshalOptStrictSeparators struct{}
+
+func (unmarshalOptStrictSeparators) mergeToUnmarshalOpt(opt *unmarshalOptions) {
+	opt.strictSeparators = true
+}
+
+// UnmarshalOptStrict enables all strict checks, including
+// UnmarshalOptRejectDuplicateKeys, UnmarshalOptRejectInvalidUTF8 and
+// UnmarshalOptStrictSeparators. It is useful for security-sensitive inputs, so
+// that jsonvalue never accepts a text which other strict parsers would
+// interpret differently.
+//
+// UnmarshalOptStrict 启用所有的严格检查, 包括 UnmarshalOptRejectDuplicateKeys,
+// UnmarshalOptRejectInvalidUTF8 和 UnmarshalOptStrictSeparators。适用于对安全敏感的输入,
+// 从而确保 jsonvalue 不会接受其他严格解析器会有不同理解的文本。
+func UnmarshalOptStrict() UnmarshalOption {
+	return unmarshalOptStrict{}
+}
+
+type unmarshalOptStrict struct{}
+
+func (unmarshalOptStrict) mergeToUnmarshalOpt(opt *unmarshalOptions) {
+	opt.rejectDuplicateKeys = true
+	opt.rejectInvalidUTF8 = true
+	opt.strictSeparators = true
+}
+
+// UnmarshalOptMaxDepth limits nesting depth of arrays and objects. For example,
+// [[1]] has a depth of 2. Zero or negative value means no limit. When exceeded,
+// ErrDepthLimitExceeded is returned.
+//
+// UnmarshalOptMaxDepth 限制 array 和 object 的嵌套深度, 比如 [[1]] 的深度为 2。零或负数表示
+// 不限制。超出限制时返回 ErrDepthLimitExceeded。
+func UnmarshalOptMaxDepth(depth int) UnmarshalOption {
+	return unmarshalOptLimit{ptr: func(l *unmarshalLimits) *int { return &l.maxDepth }, n: depth}
+}
+
+// UnmarshalOptMaxBytes limits total bytes of raw text, which is checked before
+// parsing. Zero or negative value means no limit. When exceeded,
+// ErrSizeLimitExceeded is returned.
+//
+// UnmarshalOptMaxBytes 限制原始文本的总字节数, 该检查在解析之前进行。零或负数表示不限制。超出
+// 限制时返回 ErrSizeLimitExceeded。
+func UnmarshalOptMaxBytes(size int) UnmarshalOption {
+	return unmarshalOptLimit{ptr: func(l *unmarshalLimits) *int { return &l.maxBytes }, n: size}
+}
+
+// UnmarshalOptMaxStringLength limits length in bytes of each string and object
+// key after unescaping. Zero or negative value means no limit. When exceeded,
+// ErrStringLengthLimitExceeded is returned.
+//
+// UnmarshalOptMaxStringLength 限制每个字符串和 object 的键在反转义之后的字节长度。零或负数表示
+// 不限制。超出限制时返回 ErrStringLengthLimitExceeded。
+func UnmarshalOptMaxStringLength(length int) UnmarshalOption {
+	return unmarshalOptLimit{ptr: func(l *unmarshalLimits) *int { return &l.maxStringLen }, n: length}
+}
+
+// UnmarshalOptMaxObjectKeys limits count of key-value pairs in each object.
+// Duplicated keys are counted repeatedly. Zero or negative value means no limit.
+// When exceeded, ErrObjectKeysLimitExceeded is returned.
+//
+// UnmarshalOptMaxObjectKeys 限制每个 object 中键值对的数量, 重复的键会被重复计数。零或负数
+// 表示不限制。超出限制时返回 ErrObjectKeysLimitExceeded。
+func UnmarshalOptMaxObjectKeys(count int) UnmarshalOption {
+	return unmarshalOptLimit{ptr: func(l *unmarshalLimits) *int { return &l.maxObjectKeys }, n: count}
+}
+
+// UnmarshalOptMaxArrayLength limits count of elements in each array. Zero or
+// negative value means no limit. When exceeded, ErrArrayLengthLimitExceeded is
+// returned.
+//
+// UnmarshalOptMaxArrayLength 限制每个 array 中元素的数量。零或负数表示不限制。超出限制时返回
+// ErrArrayLengthLimitExceeded。
+func UnmarshalOptMaxArrayLength(length int) UnmarshalOption {
+	return unmarshalOptLimit{ptr: func(l *unmarshalLimits) *int { return &l.maxArrayLen }, n: length}
+}
+
+// UnmarshalOptMaxNodes limits total count of values in the whole document,
+// including the top-level value, arrays, objects and all their descendants.
+// Zero or negative value means no limit. When exceeded, ErrNodeLimitExceeded
+// is returned.
+//
+// UnmarshalOptMaxNodes 限制整个文档中值的总数, 包括顶层值, array, object 及其所有后代。零或
+// 负数表示不限制。超出限制时返回 ErrNodeLimitExceeded。
+func UnmarshalOptMaxNodes(count int) UnmarshalOption {
+	return unmarshalOptLimit{ptr: func(l *unmarshalLimits) *int { return &l.maxNodes }, n: count}
+}
+
+type unmarshalOptLimit struct {
+	ptr func(*unmarshalLimits) *int
+	n   int
+}
+
+func (o unmarshalOptLimit) mergeToUnmarshalOpt(opt *unmarshalOptions) {
+	n := o.n
+	if n < 0 {
+		n = 0
+	}
+	*o.ptr(&opt.limits) = n
+
+	l := &opt.limits
+	l.enabled = l.maxDepth > 0 || l.maxBytes > 0 || l.maxStringLen > 0 ||
+		l.maxObjectKeys > 0 || l.maxArrayLen > 0 || l.maxNodes > 0
+}
+
+// UnmarshalWithOptions parses raw bytes with given options. Without any option,
+// it is the same as Unmarshal.
+//
+// There are no options for trailing data, escaped lone UTF-16 surrogates or a
+// leading byte order mark (BOM), because they are not modes: both Unmarshal and
+// UnmarshalWithOptions reject any non-blank data after the top-level value and
+// any escaped lone surrogate. A leading BOM is rejected as well, unless
+// UnmarshalOptAllowExtendedWhitespace is given.
+//
+// UnmarshalWithOptions 按照给定的选项解析原始字节数据。如果未指定任何选项, 则与 Unmarshal 相同。
+//
+// 顶层值之后的数据, 转义的孤立 UTF-16 代理项以及开头的字节顺序标记 (BOM) 没有对应的选项, 因为它们不是可选的模式:
+// Unmarshal 和 UnmarshalWithOptions 都会拒绝顶层值之后的非空白数据以及转义的孤立代理项。除非指定了
+// UnmarshalOptAllowExtendedWhitespace, 否则开头的 BOM 也会被拒绝。
+func UnmarshalWithOptions(b []byte, opts ...UnmarshalOption) (*V, error) {
+	if len(b) == 0 {
+		return nil, ErrNilParameter
+	}
+	opt := combineUnmarshalOptions(opts)
+	if err := opt.checkSize(len(b)); err != nil {
+		return nil, err
+	}
+
+	trueB := make([]byte, len(b))
+	copy(trueB, b)
+	return unmarshalNoCopyWithOptions(trueB, b, opt)
+}
+
+// UnmarshalStringWithOptions is equivalent to UnmarshalWithOptions([]byte(s), opts...).
+//
+// UnmarshalStringWithOptions 等效于 UnmarshalWithOptions([]byte(s), opts...)。
+func UnmarshalStringWithOptions(s string, opts ...UnmarshalOption) (*V, error) {
+	if s == "" {
+		return nil, ErrNilParameter
+	}
+	opt := combineUnmarshalOptions(opts)
+	if err := opt.checkSize(len(s)); err != nil {
+		return nil, err
+	}
+	return unmarshalNoCopyWithOptions([]byte(s), unsafe.StoB(s), opt)
+}
+
+// unmarshalNoCopyWithOptions parses b which could be modified, while src is the
+// original text for locating syntax errors.
+func unmarshalNoCopyWithOptions(b, src []byte, opt *unmarshalOptions) (*V, error) {
+	if opt.positions != nil {
+		opt = opt.recordPositions(src)
+	}
+	p := newPool(len(b))
+	v, err := unmarshalWithIterAndOptions(p, iter(b), 0, opt)
+	p.release()
+	if err != nil {
+		if opt.positions != nil {
+			opt.positions.reset(nil)
+		}
+		return v, locateSyntaxError(err, src)
+	}
+	return v, nil
+}
+
+// recordPositions returns a copy of opt which records spans of parsed values
+// into opt.positions, besides invoking the original onNode.
+func (opt *unmarshalOptions) recordPositions(src []byte) *unmarshalOptions {
+	pos, onNode := opt.positions, opt.onNode
+	pos.reset(src)
+
+	o := *opt
+	o.onNode = func(v *V, span nodeSpan) {
+		pos.spans[v] = span
+		if onNode != nil {
+			onNode(v, span)
+		}
+	}
+	return &o
+}
+
+// checkString validates a parsed string or key, which is it[start:start+length].
+func (opt *unmarshalOptions) checkString(it iter, start, length int) error {
+	if opt == nil {
+		return nil
+	}
+	return opt.validateString(it, start, length)
+}
+
+func (opt *unmarshalOptions) validateString(it iter, start, length int) error {
+	if opt.rejectInvalidUTF8 {
+		if s := it[start : start+length]; !utf8.Valid(s) {
+			return newSyntaxError(ErrIllegalString, start, "", "invalid UTF-8 sequence")
+		}
+	}
+	if l := &opt.limits; l.maxStringLen > 0 && length > l.maxStringLen {
+		return newSyntaxError(
+			ErrStringLengthLimitExceeded, start, "",
+			"string length %d exceeds limit %d", length, l.maxStringLen,
+		)
+	}
+	return nil
+}
+
+// forParsing returns options which are safe to hold states of one parsing.
+func (opt *unmarshalOptions) forParsing() *unmarshalOptions {
+	if !opt.limits.enabled {
+		return opt
+	}
+	o := *opt
+	o.limits.depth, o.limits.nodes = 0, 0
+	return &o
+}
+
+func (opt *unmarshalOptions) checkSize(size int) error {
+	if l := &opt.limits; l.maxBytes > 0 && size > l.maxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds limit %d", ErrSizeLimitExceeded, size, l.maxBytes)
+	}
+	return nil
+}
+
+// enterContainer should be invoked when an array or object starts at offset.
+func (opt *unmarshalOptions) enterContainer(offset int) error {
+	if opt == nil || !opt.limits.enabled {
+		return nil
+	}
+	return opt.limits.enterContainer(offset)
+}
+
+// leaveContainer should be invoked when an array or object ends.
+func (opt *unmarshalOptions) leaveContainer() {
+	if opt != nil && opt.limits.enabled {
+		opt.limits.depth--
+	}
+}
+
+// addNode should be invoked when a value starts at offset.
+func (opt *unmarshalOptions) addNode(offset int) error {
+	if opt == nil || !opt.limits.enabled {
+		return nil
+	}
+	return opt.limits.addNode(offset)
+}
+
+// addArrayElement should be invoked when the count-th element of an array
+// starts at offset.
+func (opt *unmarshalOptions) addArrayElement(offset, count int) error {
+	if opt == nil || !opt.limits.enabled {
+		return nil
+	}
+	return opt.limits.addArrayElement(offset, count)
+}
+
+// addObjectMember should be invoked when the count-th value of an object starts
+// at offset.
+func (opt *unmarshalOptions) addObjectMember(offset, count int) error {
+	if opt == nil || !opt.limits.enabled {
+		return nil
+	}
+	return opt.limits.addObjectMember(offset, count)
+}
+
+func (l *unmarshalLimits) enterContainer(offset int) error {
+	l.depth++
+	if l.maxDepth > 0 && l.depth > l.maxDepth {
+		return newSyntaxError(ErrDepthLimitExceeded, offset, "", "nesting depth exceeds limit %d", l.maxDepth)
+	}
+	return nil
+}
+
+func (l *unmarshalLimits) addNode(offset int) error {
+	l.nodes++
+	if l.maxNodes > 0 && l.nodes > l.maxNodes {
+		return newSyntaxError(ErrNodeLimitExceeded, offset, "", "node count exceeds limit %d", l.maxNodes)
+	}
+	return nil
+}
+
+func (l *unmarshalLimits) addArrayElement(offset, count int) error {
+	if l.maxArrayLen > 0 && count > l.maxArrayLen {
+		return newSyntaxError(ErrArrayLengthLimitExceeded, offset, "']'", "array length exceeds limit %d", l.maxArrayLen)
+	}
+	return l.addNode(offset)
+}
+
+func (l *unmarshalLimits) addObjectMember(offset, count int) error {
+	if l.maxObjectKeys > 0 && count > l.maxObjectKeys {
+		return newSyntaxError(ErrObjectKeysLimitExceeded, offset, "'}'", "object keys exceed limit %d", l.maxObjectKeys)
+	}
+	return l.addNode(offset)
+}
+
+// separatorState tracks commas between members of an array or object, used by
+// UnmarshalOptStrictSeparators.
+type separatorState struct {
+	valueRead  bool // a member is read and a comma or ending bracket is expected
+	commaFound bool // a comma is read and another member is expected
+
+	allowTrailingComma bool
+}
+
+func (s *separatorState) checkInArray(offset int, chr byte) error {
+	switch chr {
+	case ']':
+		if s.commaFound && !s.allowTrailingComma {
+			return newSyntaxError(ErrNotArrayValue, offset, "value", "trailing comma")
+		}
+	case ',':
+		if !s.valueRead {
+			return newSyntaxError(ErrNotArrayValue, offset, "value or ']'", "unexpected comma")
+		}
+		s.valueRead, s.commaFound = false, true
+	default:
+		if s.valueRead {
+			return newSyntaxError(ErrNotArrayValue, offset, "',' or ']'", "missing comma")
+		}
+		s.valueRead, s.commaFound = true, false
+	}
+	return nil
+}
+
+func (s *separatorState) checkInObject(offset int, chr byte, keyRead bool) error {
+	switch chr {
+	case '}':
+		if s.commaFound && !s.allowTrailingComma {
+			return newSyntaxError(ErrNotObjectValue, offset, "string key", "trailing comma")
+		}
+	case ',':
+		if !s.valueRead {
+			return newSyntaxError(ErrNotObjectValue, offset, "string key or '}'", "unexpected comma")
+		}
+		s.valueRead, s.commaFound = false, true
+	case ':':
+		// checked by parser
+	default:
+		if keyRead {
+			s.valueRead = true
+			return nil
+		}
+		// a key
+		if s.valueRead {
+			return newSyntaxError(ErrNotObjectValue, offset, "',' or '}'", "missing comma")
+		}
+		s.commaFound = false
+	}
+	return nil
+}
+
+// setObjectChild sets v to obj with key it[keyStart:keyEnd]. The span is of v
+// and its key.
+func (opt *unmarshalOptions) setObjectChild(obj *V, it iter, keyStart, keyEnd int, v *V, span nodeSpan) error {
+	if opt == nil {
+		setToObjectChildren(obj, unsafe.BtoS(it[keyStart:keyEnd]), v)
+		return nil
+	}
+	return opt.setObjectChildWithOptions(obj, it, keyStart, keyEnd, v, span)
+}
+
+func (opt *unmarshalOptions) setObjectChildWithOptions(obj *V, it iter, keyStart, keyEnd int, v *V, span nodeSpan) error {
+	key := unsafe.BtoS(it[keyStart:keyEnd])
+	if opt.rejectDuplicateKeys {
+		if _, exist := obj.children.object[key]; exist {
+			return newSyntaxError(ErrDuplicateKey, keyStart, "", "duplicate key '%s'", key)
+		}
+	}
+	setToObjectChildren(obj, key, v)
+	if opt.onNode != nil {
+		opt.onNode(v, span)
+	}
+	return nil
+}
+
+// isDefault tells whether opt changes nothing from Unmarshal, so that all option
+// checks could be skipped.
+func (opt *unmarshalOptions) isDefault() bool {
+	return opt == nil || opt == defaultUnmarshalOptions || (!opt.rejectDuplicateKeys && !opt.rejectInvalidUTF8 &&
+		!opt.strictSeparators && !opt.limits.enabled && opt.json5 == json5Options{} && opt.onNode == nil && opt.positions == nil)
+}
@@ -0,0 +1,255 @@
+package jsonvalue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testUnmarshalOption(t *testing.T) {
+	cv("no option", func() { testUnmarshalWithoutOption(t) })
+	cv("reject duplicate keys", func() { testUnmarshalRejectDuplicateKeys(t) })
+	cv("reject invalid UTF-8", func() { testUnmarshalRejectInvalidUTF8(t) })
+	cv("strict separators", func() { testUnmarshalStrictSeparators(t) })
+	cv("always rejected", func() { testUnmarshalAlwaysRejected(t) })
+	cv("resource limits", func() { testUnmarshalLimits(t) })
+}
+
+func testUnmarshalWithoutOption(t *testing.T) {
+	raw := `{"a":1,"a":2,"b":[1 2,,3,],"c":"` + "\xc0\xaf" + `"}`
+	v, err := UnmarshalWithOptions([]byte(raw))
+	so(err, isNil)
+	so(v.MustGet("a").Int(), eq, 2)
+	so(v.MustGet("b").Len(), eq, 3)
+
+	v, err = UnmarshalStringWithOptions(raw, nil)
+	so(err, isNil)
+	so(v.MustGet("a").Int(), eq, 2)
+
+	_, err = UnmarshalWithOptions(nil)
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = UnmarshalStringWithOptions("")
+	so(errors.Is(err, ErrNilParameter), isTrue)
+}
+
+func testUnmarshalRejectDuplicateKeys(t *testing.T) {
+	opt := UnmarshalOptRejectDuplicateKeys()
+
+	cases := []string{
+		`{"a":1,"a":2}`,
+		`{"a":{},"a":{}}`,
+		`{"a":[],"a":null}`,
+		`{"a":"1","a":true}`,
+		`{"a":false,"a":false}`,
+		`[{"x":{"b":1,"c":2,"b":3}}]`,
+	}
+	for _, c := range cases {
+		_, err := UnmarshalStringWithOptions(c, opt)
+		so(errors.Is(err, ErrDuplicateKey), isTrue)
+
+		var se *SyntaxError
+		so(errors.As(err, &se), isTrue)
+		so(se.Error(), hasSubStr, "duplicate key")
+	}
+
+	// keys in different objects, or differing in cases, are not duplicated
+	v, err := UnmarshalStringWithOptions(`{"a":{"a":1},"A":2,"b":[{"a":1},{"a":2}]}`, opt)
+	so(err, isNil)
+	so(v.Len(), eq, 3)
+
+	// escaped keys are compared after unescaping
+	_, err = UnmarshalStringWithOptions(`{"a":1,"\u0061":2}`, opt)
+	so(errors.Is(err, ErrDuplicateKey), isTrue)
+}
+
+func testUnmarshalRejectInvalidUTF8(t *testing.T) {
+	opt := UnmarshalOptRejectInvalidUTF8()
+
+	invalid := []string{
+		"\xed\xa0\x80",     // encoded surrogate
+		"\xc0\xaf",         // overlong
+		"\xe0\x80\xaf",     // overlong
+		"\xf4\x90\x80\x80", // beyond U+10FFFF
+		"\xe4\xb8",         // truncated
+		"a\xe4\x41\x41",    // broken continuation bytes
+	}
+	for _, s := range invalid {
+		_, err := UnmarshalStringWithOptions(`"`+s+`"`, opt)
+		so(errors.Is(err, ErrIllegalString), isTrue)
+
+		_, err = UnmarshalStringWithOptions(`["`+s+`"]`, opt)
+		so(errors.Is(err, ErrIllegalString), isTrue)
+
+		_, err = UnmarshalStringWithOptions(`{"a":"`+s+`"}`, opt)
+		so(errors.Is(err, ErrIllegalString), isTrue)
+
+		_, err = UnmarshalStringWithOptions(`{"`+s+`":1}`, opt)
+		so(errors.Is(err, ErrIllegalString), isTrue)
+	}
+
+	v, err := UnmarshalStringWithOptions(`{"中文":"😀","esc":"\ud83d\ude00\u4e2d"}`, opt)
+	so(err, isNil)
+	so(v.MustGet("中文").String(), eq, "😀")
+	so(v.MustGet("esc").String(), eq, "😀中")
+}
+
+func testUnmarshalStrictSeparators(t *testing.T) {
+	opt := UnmarshalOptStrictSeparators()
+
+	invalid := []string{
+		`[1 2]`, `[1,,2]`, `[,1]`, `[1,]`, `[,]`, `[[] []]`, `[{} "a"]`,
+		`{"a":1 "b":2}`, `{"a":1,,"b":2}`, `{,"a":1}`, `{"a":1,}`, `{,}`,
+		`{"a":[1,]}`, `[{"a":1,}]`,
+	}
+	for _, s := range invalid {
+		_, err := UnmarshalString(s)
+		so(err, isNil)
+
+		_, err = UnmarshalStringWithOptions(s, opt)
+		so(err, isErr)
+		se := &SyntaxError{}
+		so(errors.As(err, &se), isTrue)
+	}
+
+	valid := []string{
+		`[]`, `{}`, `[ ]`, `{ }`, `[1]`, `[1, 2, 3]`, `[[],{},"",true,false,null,-1]`,
+		`{"a":1}`, `{ "a" : 1 , "b" : [ 1 , 2 ] , "c" : { } }`, `[{"a":{"b":[true]}}]`,
+	}
+	for _, s := range valid {
+		_, err := UnmarshalStringWithOptions(s, opt)
+		so(err, isNil)
+	}
+}
+
+func testUnmarshalAlwaysRejected(t *testing.T) {
+	opts := []UnmarshalOption{nil, UnmarshalOptStrict()}
+	for _, o := range opts {
+		_, err := UnmarshalStringWithOptions(`{"a":1} {}`, o)
+		so(errors.Is(err, ErrRawBytesUnrecognized), isTrue)
+
+		_, err = UnmarshalStringWithOptions(`[1]]`, o)
+		so(errors.Is(err, ErrRawBytesUnrecognized), isTrue)
+
+		_, err = UnmarshalStringWithOptions("\xef\xbb\xbf{}", o)
+		so(errors.Is(err, ErrRawBytesUnrecognized), isTrue)
+
+		_, err = UnmarshalStringWithOptions(`"\ud800"`, o)
+		so(errors.Is(err, ErrIllegalString), isTrue)
+
+		_, err = UnmarshalStringWithOptions(`"\udc00"`, o)
+		so(errors.Is(err, ErrIllegalString), isTrue)
+	}
+
+	v, err := UnmarshalStringWithOptions(" \t\r\n{\"a\":[1,2]} \t\r\n", UnmarshalOptStrict())
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `{"a":[1,2]}`)
+}
+
+func testUnmarshalLimits(t *testing.T) {
+	cv("max depth", func() {
+		opt := UnmarshalOptMaxDepth(3)
+		_, err := UnmarshalStringWithOptions(`[[[1]]]`, opt)
+		so(err, isNil)
+		_, err = UnmarshalStringWithOptions(`{"a":[{"b":1}],"c":[[2]]}`, opt)
+		so(err, isNil)
+
+		_, err = UnmarshalStringWithOptions(`[[[[1]]]]`, opt)
+		so(errors.Is(err, ErrDepthLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`{"a":{"b":{"c":{}}}}`, opt)
+		so(errors.Is(err, ErrDepthLimitExceeded), isTrue)
+
+		// fails fast without parsing the rest
+		deep := strings.Repeat("[", 1000000)
+		_, err = UnmarshalStringWithOptions(deep, opt)
+		so(errors.Is(err, ErrDepthLimitExceeded), isTrue)
+		se := &SyntaxError{}
+		so(errors.As(err, &se), isTrue)
+		so(se.Offset, eq, 3)
+	})
+
+	cv("max bytes", func() {
+		opt := UnmarshalOptMaxBytes(7)
+		_, err := UnmarshalWithOptions([]byte(`[1,2,3]`), opt)
+		so(err, isNil)
+		_, err = UnmarshalWithOptions([]byte(`[1,2,3] `), opt)
+		so(errors.Is(err, ErrSizeLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`[1,2,3,4]`, opt)
+		so(errors.Is(err, ErrSizeLimitExceeded), isTrue)
+	})
+
+	cv("max string length", func() {
+		opt := UnmarshalOptMaxStringLength(3)
+		_, err := UnmarshalStringWithOptions(`{"abc":["abc","\u4e2d"]}`, opt)
+		so(err, isNil)
+		_, err = UnmarshalStringWithOptions(`"abcd"`, opt)
+		so(errors.Is(err, ErrStringLengthLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`["abcd"]`, opt)
+		so(errors.Is(err, ErrStringLengthLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`{"abcd":1}`, opt)
+		so(errors.Is(err, ErrStringLengthLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`{"a":"abcd"}`, opt)
+		so(errors.Is(err, ErrStringLengthLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`"\u4e2da"`, opt)
+		so(errors.Is(err, ErrStringLengthLimitExceeded), isTrue)
+	})
+
+	cv("max object keys", func() {
+		opt := UnmarshalOptMaxObjectKeys(2)
+		_, err := UnmarshalStringWithOptions(`{"a":1,"b":{"c":1,"d":2},"e":[]}`, opt)
+		so(errors.Is(err, ErrObjectKeysLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`{"a":1,"b":{"c":1,"d":2}}`, opt)
+		so(err, isNil)
+		_, err = UnmarshalStringWithOptions(`{"a":1,"a":2,"a":3}`, opt)
+		so(errors.Is(err, ErrObjectKeysLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`[{"a":1,"b":"2","c":null}]`, opt)
+		so(errors.Is(err, ErrObjectKeysLimitExceeded), isTrue)
+	})
+
+	cv("max array length", func() {
+		opt := UnmarshalOptMaxArrayLength(2)
+		_, err := UnmarshalStringWithOptions(`[[1,2],{"a":["x","y"]}]`, opt)
+		so(err, isNil)
+		_, err = UnmarshalStringWithOptions(`[1,2,3]`, opt)
+		so(errors.Is(err, ErrArrayLengthLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`{"a":[true,false,null]}`, opt)
+		so(errors.Is(err, ErrArrayLengthLimitExceeded), isTrue)
+	})
+
+	cv("max nodes", func() {
+		opt := UnmarshalOptMaxNodes(5)
+		_, err := UnmarshalStringWithOptions(`{"a":[1,2],"b":"c"}`, opt)
+		so(err, isNil)
+		_, err = UnmarshalStringWithOptions(`{"a":[1,2],"b":"c","d":null}`, opt)
+		so(errors.Is(err, ErrNodeLimitExceeded), isTrue)
+		_, err = UnmarshalStringWithOptions(`[[],[],[],[],[]]`, opt)
+		so(errors.Is(err, ErrNodeLimitExceeded), isTrue)
+
+		_, err = UnmarshalStringWithOptions(`1`, UnmarshalOptMaxNodes(1))
+		so(err, isNil)
+	})
+
+	cv("combined and reused", func() {
+		opts := []UnmarshalOption{
+			UnmarshalOptMaxDepth(2), UnmarshalOptMaxNodes(4), UnmarshalOptStrict(),
+		}
+		for i := 0; i < 3; i++ {
+			_, err := UnmarshalStringWithOptions(`[[1],[2]]`, opts...)
+			so(errors.Is(err, ErrNodeLimitExceeded), isTrue)
+			_, err = UnmarshalStringWithOptions(`[[1],2]`, opts...)
+			so(err, isNil)
+		}
+	})
+
+	cv("no limit", func() {
+		deep := strings.Repeat("[", 1000) + strings.Repeat("]", 1000)
+		opts := []UnmarshalOption{
+			UnmarshalOptMaxDepth(0), UnmarshalOptMaxBytes(-1), UnmarshalOptMaxNodes(0),
+		}
+		_, err := UnmarshalStringWithOptions(deep, opts...)
+		so(err, isNil)
+
+		// later option overrides former one
+		_, err = UnmarshalStringWithOptions(deep, UnmarshalOptMaxDepth(10), UnmarshalOptMaxDepth(0))
+		so(err, isNil)
+	})
+}
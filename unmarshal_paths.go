@@ -0,0 +1,447 @@
+package jsonvalue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Andrew-M-C/go.jsonvalue/internal/unsafe"
+)
+
+// ================ PATH-FILTERED UNMARSHAL ================
+
+// UnmarshalPaths parses raw bytes but only keeps branches selected by given
+// paths. Unselected values are checked and skipped without generating any
+// value, so it is much faster than Unmarshal when only a few values of a large
+// document are needed.
+//
+// A path could be a JSON Pointer (RFC 6901) starting with '/', such as
+// "/data/user/id", or a dotted path such as "data.items[*].sku", where "*" or
+// "[*]" matches any member of an object or array, and ["key"] could be used for
+// keys with special characters. An empty path selects the whole document.
+//
+// Objects and arrays along paths are always kept, even if nothing is selected
+// in them. Unselected array elements before a selected one are kept as null,
+// therefore the same paths could be used to get values from the result. Only
+// standard JSON is accepted.
+//
+// UnmarshalPaths 解析原始字节数据, 但是只保留给定路径所选中的分支。未被选中的值只会被校验并跳过, 而不会生成
+// 任何值, 因此当只需要读取一个大文档中的少数几个值时, 这比 Unmarshal 快得多。
+//
+// 路径可以是以 '/' 开头的 JSON Pointer (RFC 6901), 如 "/data/user/id"; 也可以是以点分隔的路径, 如
+// "data.items[*].sku", 其中 "*" 或 "[*]" 匹配对象或数组中的任意成员, 而包含特殊字符的键可以使用 ["key"]
+// 的形式。空路径表示选中整个文档。
+//
+// 路径上的对象和数组总会被保留, 即便其中没有选中任何值。数组中位于选中成员之前的未选中成员会被保留为 null,
+// 因此可以使用同样的路径从结果中获取值。仅接受标准 JSON。
+func UnmarshalPaths(b []byte, paths ...string) (*V, error) {
+	le := len(b)
+	if le == 0 {
+		return nil, ErrNilParameter
+	}
+
+	trueB := make([]byte, le)
+	copy(trueB, b)
+	return unmarshalPathsNoCopy(trueB, b, paths)
+}
+
+// UnmarshalPathsString is equivalent to UnmarshalPaths([]byte(s), paths...).
+//
+// UnmarshalPathsString 等效于 UnmarshalPaths([]byte(s), paths...)。
+func UnmarshalPathsString(s string, paths ...string) (*V, error) {
+	if len(s) == 0 {
+		return nil, ErrNilParameter
+	}
+	return unmarshalPathsNoCopy([]byte(s), unsafe.StoB(s), paths)
+}
+
+func unmarshalPathsNoCopy(b, src []byte, paths []string) (*V, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w, no path given", ErrParameterError)
+	}
+	root := &pathFilter{}
+	for _, path := range paths {
+		if err := root.add(path); err != nil {
+			return nil, err
+		}
+	}
+
+	it := iter(b)
+	offset, reachEnd := it.skipBlanks(0)
+	if reachEnd {
+		return nil, newSyntaxError(ErrRawBytesUnrecognized, offset, "value", "cannot find any symbol characters")
+	}
+
+	v, end, err := it.unmarshalFiltered(offset, root)
+	if err != nil {
+		return nil, locateSyntaxError(err, src)
+	}
+	if end, reachEnd = it.skipBlanks(end); !reachEnd {
+		err = newSyntaxError(ErrRawBytesUnrecognized, end, "end of input", "unnecessary trailing data remains")
+		return nil, locateSyntaxError(err, src)
+	}
+	if v == nil {
+		return nil, fmt.Errorf("%w, paths do not match a value which is not an object or array", ErrNotFound)
+	}
+	return v, nil
+}
+
+// unmarshalFiltered parses value at offset with filter f. A nil value would be
+// returned if the value is not selected.
+func (it iter) unmarshalFiltered(offset int, f *pathFilter) (v *V, end int, err error) {
+	if f.all {
+		if end, err = it.checkLazyValue(offset); err != nil {
+			return nil, -1, err
+		}
+		v, err = unmarshalWithIter(globalPool{}, it[offset:end], 0)
+		return v, end, err
+	}
+
+	switch it[offset] {
+	case '{':
+		return it.unmarshalFilteredObject(offset, f)
+	case '[':
+		return it.unmarshalFilteredArray(offset, f)
+	default:
+		// paths go deeper but this is not an object or array
+		end, err = it.checkLazyValue(offset)
+		return nil, end, err
+	}
+}
+
+func (it iter) unmarshalFilteredObject(offset int, f *pathFilter) (_ *V, end int, err error) {
+	obj := newObject(globalPool{})
+
+	offset, reachEnd := it.skipBlanks(offset + 1)
+	if reachEnd {
+		return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "'}'", "cannot find '}'")
+	}
+	if it[offset] == '}' {
+		return obj, offset + 1, nil
+	}
+
+	for {
+		if it[offset] != '"' {
+			return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "string key", "invalid character \\u%04X", it[offset])
+		}
+		le, sectEnd, err := it.parseStrFromBytesForwardWithQuote(offset)
+		if err != nil {
+			return nil, -1, err
+		}
+		key := unsafe.BtoS(it[offset+1 : offset+1+le])
+
+		if offset, reachEnd = it.skipBlanks(sectEnd); reachEnd || it[offset] != ':' {
+			return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "':'", "missing colon for key '%s'", key)
+		}
+		if offset, reachEnd = it.skipBlanks(offset + 1); reachEnd {
+			return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "value", "missing value for key '%s'", key)
+		}
+
+		if cf := f.matchKey(key); cf == nil {
+			offset, err = it.checkLazyValue(offset)
+		} else {
+			var child *V
+			child, offset, err = it.unmarshalFiltered(offset, cf)
+			if child != nil {
+				setToObjectChildren(obj, key, child)
+			}
+		}
+		if err != nil {
+			return nil, -1, err
+		}
+
+		if offset, reachEnd = it.skipBlanks(offset); reachEnd {
+			return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "'}'", "cannot find '}'")
+		}
+		switch it[offset] {
+		case '}':
+			return obj, offset + 1, nil
+		case ',':
+			if offset, reachEnd = it.skipBlanks(offset + 1); reachEnd {
+				return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "string key", "cannot find '}'")
+			}
+		default:
+			return nil, -1, newSyntaxError(ErrNotObjectValue, offset, "',' or '}'", "invalid character \\u%04X", it[offset])
+		}
+	}
+}
+
+func (it iter) unmarshalFilteredArray(offset int, f *pathFilter) (_ *V, end int, err error) {
+	arr := newArray(globalPool{})
+
+	offset, reachEnd := it.skipBlanks(offset + 1)
+	if reachEnd {
+		return nil, -1, newSyntaxError(ErrNotArrayValue, offset, "']'", "cannot find ']'")
+	}
+	if it[offset] == ']' {
+		return arr, offset + 1, nil
+	}
+
+	for i := 0; ; i++ {
+		if cf := f.matchIndex(i); cf == nil {
+			offset, err = it.checkLazyValue(offset)
+		} else {
+			var child *V
+			child, offset, err = it.unmarshalFiltered(offset, cf)
+			if child != nil {
+				for arr.Len() < i {
+					appendToArr(arr, NewNull())
+				}
+				appendToArr(arr, child)
+			}
+		}
+		if err != nil {
+			return nil, -1, err
+		}
+
+		if offset, reachEnd = it.skipBlanks(offset); reachEnd {
+			return nil, -1, newSyntaxError(ErrNotArrayValue, offset, "']'", "cannot find ']'")
+		}
+		switch it[offset] {
+		case ']':
+			return arr, offset + 1, nil
+		case ',':
+			if offset, reachEnd = it.skipBlanks(offset + 1); reachEnd {
+				return nil, -1, newSyntaxError(ErrNotArrayValue, offset, "value", "cannot find ']'")
+			}
+		default:
+			return nil, -1, newSyntaxError(ErrNotArrayValue, offset, "',' or ']'", "invalid character \\u%04X", it[offset])
+		}
+	}
+}
+
+// ================ PATH FILTER ================
+
+// pathFilter is a trie of paths. A JSON Pointer token which is a valid array
+// index is stored in keyOrIndexes, as its meaning depends on the type of value.
+type pathFilter struct {
+	all          bool // whole value is selected
+	keys         map[string]*pathFilter
+	indexes      map[int]*pathFilter
+	keyOrIndexes map[int]*pathFilter
+	wildcard     *pathFilter
+}
+
+// pathSegment is a parsed path segment. wildcard would be true for "*".
+type pathSegment struct {
+	key      string
+	index    int // -1 if not an index
+	isKey    bool
+	wildcard bool
+}
+
+func (f *pathFilter) add(path string) error {
+	var segs []pathSegment
+	var err error
+	if path == "" || path[0] == '/' {
+		segs, err = parsePointerSegments(path)
+	} else {
+		segs, err = parseDottedPath(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, seg := range segs {
+		if f.all {
+			return nil
+		}
+		switch {
+		case seg.wildcard:
+			if f.wildcard == nil {
+				f.wildcard = &pathFilter{}
+			}
+			f = f.wildcard
+		case seg.isKey && seg.index >= 0:
+			f = f.keyOrIndexChild(seg.index)
+		case seg.isKey:
+			f = f.keyChild(seg.key)
+		default:
+			f = f.indexChild(seg.index)
+		}
+	}
+
+	*f = pathFilter{all: true}
+	return nil
+}
+
+func (f *pathFilter) keyChild(key string) *pathFilter {
+	if f.keys == nil {
+		f.keys = map[string]*pathFilter{}
+	}
+	next, exist := f.keys[key]
+	if !exist {
+		next = &pathFilter{}
+		f.keys[key] = next
+	}
+	return next
+}
+
+func (f *pathFilter) indexChild(index int) *pathFilter {
+	if f.indexes == nil {
+		f.indexes = map[int]*pathFilter{}
+	}
+	next, exist := f.indexes[index]
+	if !exist {
+		next = &pathFilter{}
+		f.indexes[index] = next
+	}
+	return next
+}
+
+func (f *pathFilter) keyOrIndexChild(index int) *pathFilter {
+	if f.keyOrIndexes == nil {
+		f.keyOrIndexes = map[int]*pathFilter{}
+	}
+	next, exist := f.keyOrIndexes[index]
+	if !exist {
+		next = &pathFilter{}
+		f.keyOrIndexes[index] = next
+	}
+	return next
+}
+
+func (f *pathFilter) matchKey(key string) *pathFilter {
+	res := mergePathFilters(f.keys[key], f.wildcard)
+	if len(f.keyOrIndexes) > 0 {
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && strconv.Itoa(i) == key {
+			res = mergePathFilters(res, f.keyOrIndexes[i])
+		}
+	}
+	return res
+}
+
+func (f *pathFilter) matchIndex(index int) *pathFilter {
+	res := mergePathFilters(f.indexes[index], f.wildcard)
+	return mergePathFilters(res, f.keyOrIndexes[index])
+}
+
+// mergePathFilters returns a filter which selects values selected by a or b.
+func mergePathFilters(a, b *pathFilter) *pathFilter {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.all || b.all:
+		return &pathFilter{all: true}
+	}
+
+	res := &pathFilter{
+		wildcard: mergePathFilters(a.wildcard, b.wildcard),
+	}
+	for _, src := range []*pathFilter{a, b} {
+		for k, child := range src.keys {
+			if res.keys == nil {
+				res.keys = map[string]*pathFilter{}
+			}
+			res.keys[k] = mergePathFilters(res.keys[k], child)
+		}
+		for i, child := range src.indexes {
+			if res.indexes == nil {
+				res.indexes = map[int]*pathFilter{}
+			}
+			res.indexes[i] = mergePathFilters(res.indexes[i], child)
+		}
+		for i, child := range src.keyOrIndexes {
+			if res.keyOrIndexes == nil {
+				res.keyOrIndexes = map[int]*pathFilter{}
+			}
+			res.keyOrIndexes[i] = mergePathFilters(res.keyOrIndexes[i], child)
+		}
+	}
+	return res
+}
+
+func parsePointerSegments(ptr string) ([]pathSegment, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	segs := make([]pathSegment, 0, len(tokens))
+	for _, t := range tokens {
+		seg := pathSegment{key: t, index: -1, isKey: true}
+		if i, err := strconv.Atoi(t); err == nil && i >= 0 && strconv.Itoa(i) == t {
+			seg.index = i
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// parseDottedPath parses path like `data.items[*].sku` or `a["b.c"][0]`.
+func parseDottedPath(path string) ([]pathSegment, error) {
+	var segs []pathSegment
+	invalid := func(f string, a ...any) error {
+		return fmt.Errorf("%w, invalid path '%s': %s", ErrParameterError, path, fmt.Sprintf(f, a...))
+	}
+
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, invalid("missing ']'")
+			}
+			inner := path[i+1 : i+end]
+			if inner != "" && inner[0] == '"' {
+				// ']' may be inside the quoted key
+				end = strings.Index(path[i:], "\"]")
+				if end < 0 {
+					return nil, invalid("missing ']'")
+				}
+				end++
+				inner = path[i+1 : i+end]
+			}
+			seg, err := parseBracketSegment(inner)
+			if err != nil {
+				return nil, invalid("%v", err)
+			}
+			segs = append(segs, seg)
+			i += end + 1
+
+		case '.':
+			if i == 0 || i == len(path)-1 || path[i+1] == '.' || path[i+1] == '[' {
+				return nil, invalid("empty key")
+			}
+			i++
+
+		default:
+			if i > 0 && path[i-1] != '.' {
+				return nil, invalid("unexpected character at %d", i)
+			}
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			key := path[i : i+end]
+			if key == "*" {
+				segs = append(segs, pathSegment{index: -1, wildcard: true})
+			} else {
+				segs = append(segs, pathSegment{key: key, index: -1, isKey: true})
+			}
+			i += end
+		}
+	}
+	return segs, nil
+}
+
+func parseBracketSegment(s string) (pathSegment, error) {
+	switch {
+	case s == "*":
+		return pathSegment{index: -1, wildcard: true}, nil
+	case s != "" && s[0] == '"':
+		key, err := strconv.Unquote(s)
+		if err != nil {
+			return pathSegment{}, fmt.Errorf("illegal quoted key %s", s)
+		}
+		return pathSegment{key: key, index: -1, isKey: true}, nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 {
+		return pathSegment{}, fmt.Errorf("illegal index '%s'", s)
+	}
+	return pathSegment{index: i}, nil
+}
@@ -0,0 +1,167 @@
+package jsonvalue
+
+import (
+	"errors"
+	"testing"
+)
+
+func testUnmarshalPaths(t *testing.T) {
+	cv("select by paths", func() { testUnmarshalPathsSelect(t) })
+	cv("select by JSON pointers", func() { testUnmarshalPathsPointer(t) })
+	cv("wildcards", func() { testUnmarshalPathsWildcard(t) })
+	cv("array indexes", func() { testUnmarshalPathsIndex(t) })
+	cv("errors", func() { testUnmarshalPathsErrors(t) })
+}
+
+const testUnmarshalPathsText = `{
+	"code": 0,
+	"data": {
+		"user": {"id": 12345, "name": "Alice", "tags": ["a", "b"]},
+		"items": [
+			{"sku": "A-1", "price": 1.5, "extra": {"x": [1, 2, 3]}},
+			{"sku": "B-2", "price": 2.5},
+			{"price": 3.5}
+		],
+		"a.b": {"c]": "中\"文"}
+	},
+	"trace": "abcdefg"
+}`
+
+func testUnmarshalPathsSelect(t *testing.T) {
+	v, err := UnmarshalPathsString(testUnmarshalPathsText, "data.user.id", "code")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"code":0,"data":{"user":{"id":12345}}}`)
+	so(v.MustGet("data", "user", "id").Int(), eq, 12345)
+
+	// selected branches are the same as full unmarshal
+	full := MustUnmarshalString(testUnmarshalPathsText)
+	v, err = UnmarshalPaths([]byte(testUnmarshalPathsText), "data.user", "data.items")
+	so(err, isNil)
+	so(v.MustGet("data", "user").Equal(full.MustGet("data", "user")), isTrue)
+	so(v.MustGet("data", "items").Equal(full.MustGet("data", "items")), isTrue)
+	so(v.MustGet("data").Len(), eq, 2)
+	so(v.Len(), eq, 1)
+
+	// keys with special characters
+	v, err = UnmarshalPathsString(testUnmarshalPathsText, `data["a.b"]["c]"]`)
+	so(err, isNil)
+	so(v.MustGet("data", "a.b", "c]").String(), eq, "中\"文")
+
+	// overlapping paths
+	v, err = UnmarshalPathsString(testUnmarshalPathsText, "data.user.tags", "data.user", "data.user.id")
+	so(err, isNil)
+	so(v.MustGet("data", "user").Equal(full.MustGet("data", "user")), isTrue)
+
+	// containers along paths are kept, while scalars are skipped
+	v, err = UnmarshalPathsString(testUnmarshalPathsText, "data.user.not_exist", "trace.x")
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `{"data":{"user":{}}}`)
+
+	// empty path selects all
+	v, err = UnmarshalPathsString(testUnmarshalPathsText, "")
+	so(err, isNil)
+	so(v.Equal(full), isTrue)
+	v, err = UnmarshalPathsString(`"str"`, "")
+	so(err, isNil)
+	so(v.String(), eq, "str")
+}
+
+func testUnmarshalPathsPointer(t *testing.T) {
+	v, err := UnmarshalPathsString(testUnmarshalPathsText, "/data/user/id", "/data/items/1/sku", "/data/a.b")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence(), OptUTF8()), eq,
+		`{"data":{"a.b":{"c]":"中\"文"},"items":[null,{"sku":"B-2"}],"user":{"id":12345}}}`)
+	so(v.MustGetByPointer("/data/items/1/sku").String(), eq, "B-2")
+
+	// numeric token is a key for objects
+	v, err = UnmarshalPathsString(`{"0":{"a":1,"b":2},"1":3}`, "/0/a")
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `{"0":{"a":1}}`)
+
+	v, err = UnmarshalPathsString(`{"a/b":{"~":1},"c":2}`, "/a~1b/~0")
+	so(err, isNil)
+	so(v.MustMarshalString(OptEscapeSlash(false)), eq, `{"a/b":{"~":1}}`)
+
+	// mixed with dotted paths
+	v, err = UnmarshalPathsString(`{"a":[{"x":1,"y":2,"z":3}]}`, "a[0].x", "/a/0/y")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":[{"x":1,"y":2}]}`)
+
+	v, err = UnmarshalPathsString(`{"a":[{"x":1,"y":2,"z":3}]}`, "/a/0/y", "a[0].x")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":[{"x":1,"y":2}]}`)
+
+	v, err = UnmarshalPathsString(`{"0":{"x":1,"y":2,"z":3}}`, "0.x", "/0/y")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"0":{"x":1,"y":2}}`)
+
+	// dotted key "0" does not match array index
+	v, err = UnmarshalPathsString(`[{"x":1,"y":2,"z":3}]`, "0.x", "/0/y")
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `[{"y":2}]`)
+}
+
+func testUnmarshalPathsWildcard(t *testing.T) {
+	v, err := UnmarshalPathsString(testUnmarshalPathsText, "data.items[*].sku", "data.user.id")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq,
+		`{"data":{"items":[{"sku":"A-1"},{"sku":"B-2"},{}],"user":{"id":12345}}}`)
+
+	// wildcard key, merged with specified ones
+	v, err = UnmarshalPathsString(testUnmarshalPathsText, "data.*.id", "data.items[0].price", "data.items.*.sku")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq,
+		`{"data":{"a.b":{},"items":[{"price":1.5,"sku":"A-1"},{"sku":"B-2"},{}],"user":{"id":12345}}}`)
+
+	v, err = UnmarshalPathsString(`[[1,2],[3,4]]`, "[*][1]")
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `[[null,2],[null,4]]`)
+
+	v, err = UnmarshalPathsString(`{"a":{"b":1},"c":{"d":2}}`, "*", "a.b")
+	so(err, isNil)
+	so(v.MustMarshalString(OptDefaultStringSequence()), eq, `{"a":{"b":1},"c":{"d":2}}`)
+}
+
+func testUnmarshalPathsIndex(t *testing.T) {
+	v, err := UnmarshalPathsString(`[0, 1, {"a": 2}, 3]`, "[2].a", "[0]")
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `[0,null,{"a":2}]`)
+	so(v.MustGet(2, "a").Int(), eq, 2)
+
+	v, err = UnmarshalPathsString(`[0, 1]`, "[5]")
+	so(err, isNil)
+	so(v.MustMarshalString(), eq, `[]`)
+}
+
+func testUnmarshalPathsErrors(t *testing.T) {
+	_, err := UnmarshalPaths(nil, "a")
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = UnmarshalPathsString("", "a")
+	so(errors.Is(err, ErrNilParameter), isTrue)
+	_, err = UnmarshalPathsString(`{}`)
+	so(errors.Is(err, ErrParameterError), isTrue)
+	_, err = UnmarshalPathsString(`1`, "a")
+	so(errors.Is(err, ErrNotFound), isTrue)
+
+	invalidPaths := []string{"a..b", ".a", "a.", "a[", "a[x]", "a[-1]", `a["b]`, `a["\q"]`, "a[0]b", "a.[0]"}
+	for _, p := range invalidPaths {
+		_, err := UnmarshalPathsString(`{}`, p)
+		so(errors.Is(err, ErrParameterError), isTrue)
+	}
+	_, err = UnmarshalPathsString(`{}`, "/a/~2")
+	so(errors.Is(err, ErrInvalidPointer), isTrue)
+
+	// unselected values are still checked
+	invalid := []string{
+		`{"a":1,"b":[1 2]}`, `{"a":1,"b":tru}`, `{"a":1,"b":"\x"}`, `{"a":1,}`, `{"a":1} 2`,
+		`{"a":[1,2,}`, `{"a" 1}`, `{1:1}`, `{"a":{"b":01}}`, `[1,2`, ` `,
+	}
+	for _, s := range invalid {
+		_, err := UnmarshalPathsString(s, "a")
+		so(err, isErr)
+	}
+	_, err = UnmarshalPathsString("{\"a\":1,\n\"b\":[1 2]}", "a")
+	var se *SyntaxError
+	so(errors.As(err, &se), isTrue)
+	so(se.Line, eq, 2)
+}